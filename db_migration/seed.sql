@@ -0,0 +1,11 @@
+-- Sample users and relationships for local testing.
+-- The tables are created by the migrations, run this script once they are applied (the app does it on startup).
+-- Relationship status: 1 friends, 2 subscribed, 3 blocked, 4 pending friend request.
+
+INSERT INTO user (Id, Email) VALUES
+(1,'johndoe@gmail.com'),(2,'janedoe@gmail.com'),(3,'kytruong@yahoo.com'),(4,'example@email.com'),(5,'abc123@gmail.com'),
+(6,'a@gmail.com'),(7,'b@gmail.com'),(8,'c@gmail.com'),(9,'d@gmail.com'),(10,'e@gmail.com'),
+(11,'sangdepchai@gmail.com'),(12,'kytruong@gmail.com'),(13,'123@email.com'),(14,'1@email.com');
+
+INSERT INTO relationship (Id, RequestUserId, TargetUserId, Status) VALUES
+(37,1,2,3),(38,2,5,1),(39,5,1,1),(40,2,7,1),(41,3,5,1),(42,6,7,3),(43,7,6,2);
@@ -0,0 +1,77 @@
+package common
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// emailProvider tells how a mail provider delivers variants of an address to the same mailbox.
+type emailProvider struct {
+	// domain is the domain the provider's addresses are stored with.
+	domain string
+	// ignoreDots is set when the dots of the local part don't matter.
+	ignoreDots bool
+	// plusTags is set when "name+tag" is delivered to "name".
+	plusTags bool
+}
+
+var emailProviders = map[string]emailProvider{
+	"gmail.com":      {domain: "gmail.com", ignoreDots: true, plusTags: true},
+	"googlemail.com": {domain: "gmail.com", ignoreDots: true, plusTags: true},
+	"outlook.com":    {domain: "outlook.com", plusTags: true},
+	"hotmail.com":    {domain: "hotmail.com", plusTags: true},
+	"icloud.com":     {domain: "icloud.com", plusTags: true},
+}
+
+var providerRules int32
+
+// SetProviderRules enables or disables the provider rules of NormalizeEmail, they are disabled by default.
+// It's meant to be called once at startup, before any email is normalized.
+func SetProviderRules(enabled bool) {
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&providerRules, value)
+}
+
+// NormalizeEmail returns the canonical form of an email, which identifies its owner: it's trimmed and lower-cased,
+// and when the provider rules are enabled the dots and plus tags ignored by known providers like Gmail are removed.
+func NormalizeEmail(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if atomic.LoadInt32(&providerRules) == 0 {
+		return email
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+
+	local, domain := email[:at], email[at+1:]
+	provider, ok := emailProviders[domain]
+	if !ok {
+		return email
+	}
+
+	if provider.plusTags {
+		if plus := strings.Index(local, "+"); plus > 0 {
+			local = local[:plus]
+		}
+	}
+	if provider.ignoreDots {
+		local = strings.Replace(local, ".", "", -1)
+	}
+
+	return local + "@" + provider.domain
+}
+
+// NormalizeEmails returns the canonical forms of the emails in the same order.
+func NormalizeEmails(emails []string) []string {
+	normalized := make([]string, len(emails))
+	for i, email := range emails {
+		normalized[i] = NormalizeEmail(email)
+	}
+
+	return normalized
+}
@@ -0,0 +1,37 @@
+package common_test
+
+import (
+	"friendMgmt/common"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	var emails = map[string]string{
+		"john@example.com":         "john@example.com",
+		" John@Example.COM ":       "john@example.com",
+		"John.Doe+news@Gmail.com":  "john.doe+news@gmail.com",
+		"jane.doe+work@icloud.com": "jane.doe+work@icloud.com",
+	}
+	for email, expected := range emails {
+		assert.Equal(t, expected, common.NormalizeEmail(email), email)
+	}
+}
+
+func TestNormalizeEmailWithProviderRules(t *testing.T) {
+	common.SetProviderRules(true)
+	defer common.SetProviderRules(false)
+
+	var emails = map[string]string{
+		" John@Example.COM ":         "john@example.com",
+		"john.doe+news@example.com":  "john.doe+news@example.com",
+		"John.Doe+news@Gmail.com":    "johndoe@gmail.com",
+		"j.o.h.n.doe@googlemail.com": "johndoe@gmail.com",
+		"jane.doe+work@icloud.com":   "jane.doe@icloud.com",
+		"+tag@outlook.com":           "+tag@outlook.com",
+	}
+	for email, expected := range emails {
+		assert.Equal(t, expected, common.NormalizeEmail(email), email)
+	}
+}
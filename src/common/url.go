@@ -0,0 +1,10 @@
+package common
+
+import "net/url"
+
+// IsWebUrl reports whether value is an absolute http or https url of at most maxLength bytes.
+func IsWebUrl(value string, maxLength int) bool {
+	parsed, err := url.Parse(value)
+
+	return err == nil && len(value) <= maxLength && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
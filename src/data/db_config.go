@@ -3,17 +3,58 @@ package data
 import (
 	"database/sql"
 	"fmt"
+	"os"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/joho/godotenv"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	MySQL  = "mysql"
+	SQLite = "sqlite"
+	Memory = "memory"
 )
 
-func InitDB() (*sql.DB, error) {
-	dbDriver := "mysql"
-	dbUser := "root"
-	dbPass := "123456@x@X"
-	dbName := "friendMgmt"
-	dbPort := "3306"
-	dbHost := "fullstack-mysql"
-	dbUrl := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8&parseTime=True&loc=Local", dbUser, dbPass, dbHost, dbPort, dbName)
-	db, err := sql.Open(dbDriver, dbUrl)
+type DBConfig struct {
+	Driver string
+	User   string
+	Pass   string
+	Name   string
+	Port   string
+	Host   string
+	Path   string
+}
+
+// LoadDBConfig reads the database configuration from the environment (or an optional .env file),
+// falling back to the settings of the docker-compose MySQL container.
+func LoadDBConfig() DBConfig {
+	godotenv.Load()
+
+	return DBConfig{
+		Driver: getEnv("DB_DRIVER", MySQL),
+		User:   getEnv("DB_USER", "root"),
+		Pass:   getEnv("DB_PASSWORD", "123456@x@X"),
+		Name:   getEnv("DB_NAME", "friendMgmt"),
+		Port:   getEnv("DB_PORT", "3306"),
+		Host:   getEnv("DB_HOST", "fullstack-mysql"),
+		Path:   getEnv("DB_PATH", "friendMgmt.db"),
+	}
+}
+
+func InitDB(config DBConfig) (*sql.DB, error) {
+	var db *sql.DB
+	var err error
+
+	switch config.Driver {
+	case MySQL:
+		dbUrl := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8&parseTime=True&loc=Local", config.User, config.Pass, config.Host, config.Port, config.Name)
+		db, err = sql.Open("mysql", dbUrl)
+	case SQLite:
+		db, err = sql.Open("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on", config.Path))
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", config.Driver)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -23,5 +64,18 @@ func InitDB() (*sql.DB, error) {
 		return nil, err
 	}
 
+	if config.Driver == SQLite {
+		// SQLite only allows one writer at a time, sharing a single connection avoids "database is locked" errors.
+		db.SetMaxOpenConns(1)
+	}
+
 	return db, nil
 }
+
+func getEnv(key string, defaultValue string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+
+	return defaultValue
+}
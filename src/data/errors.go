@@ -0,0 +1,83 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/mattn/go-sqlite3"
+)
+
+// mysqlDuplicateEntry is the MySQL error number of a unique key violation.
+const mysqlDuplicateEntry = 1062
+
+// MySQL error numbers of the schema changes failing because they are already done.
+const (
+	mysqlTableExists        = 1050
+	mysqlDuplicateColumn    = 1060
+	mysqlDuplicateKey       = 1061
+	mysqlCantDropFieldOrKey = 1091
+)
+
+// NotFoundError tells that the looked up record doesn't exist, as opposed to the storage failing.
+type NotFoundError struct {
+	Entity string
+	Key    string
+}
+
+func (err *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %s is not found", err.Entity, err.Key)
+}
+
+// IsNotFound reports whether err, or any error it wraps, is a NotFoundError.
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
+
+// DuplicateError tells that a write was rejected because the record already exists.
+type DuplicateError struct {
+	Entity string
+	Key    string
+}
+
+func (err *DuplicateError) Error() string {
+	return fmt.Sprintf("%s %s already exists", err.Entity, err.Key)
+}
+
+// IsDuplicate reports whether err, or any error it wraps, is a DuplicateError.
+func IsDuplicate(err error) bool {
+	var duplicate *DuplicateError
+	return errors.As(err, &duplicate)
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation of the MySQL or SQLite driver.
+func isUniqueViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDuplicateEntry
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+	}
+
+	return false
+}
+
+// isAlreadyApplied reports whether err is a MySQL error telling that the table, column or key a schema change creates
+// already exists, or that the one it drops is already gone.
+func isAlreadyApplied(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+
+	switch mysqlErr.Number {
+	case mysqlTableExists, mysqlDuplicateColumn, mysqlDuplicateKey, mysqlCantDropFieldOrKey:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,415 @@
+package data
+
+import (
+	"friendMgmt/common"
+	"friendMgmt/models"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps users, relationships and posts in memory, it's shared by the memory repositories
+// so both of them see the same data. All accesses are guarded by a single lock.
+type MemoryStore struct {
+	mu                 sync.RWMutex
+	lastUserId         int64
+	lastRelationshipId int64
+	lastPostId         int64
+	users              map[int64]models.User
+	userIds            map[string]int64
+	relationships      map[int64]models.Relationship
+	outgoing           map[int64]map[int64][]int64
+	incoming           map[int64]map[int64][]int64
+	posts              []memoryPost
+	// undo reverts the changes made to the maps in the order they were made, for the store of a transaction only.
+	undo          []func()
+	inTransaction bool
+}
+
+type memoryPost struct {
+	ID           int64
+	SenderUserId int64
+	Text         string
+	MentionIds   []int64
+	CreatedAt    time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:         map[int64]models.User{},
+		userIds:       map[string]int64{},
+		relationships: map[int64]models.Relationship{},
+		outgoing:      map[int64]map[int64][]int64{},
+		incoming:      map[int64]map[int64][]int64{},
+	}
+}
+
+// begin returns the store of a transaction, sharing the maps of the store and recording how to revert the changes
+// made to them. The caller must hold the lock until the transaction is committed or rolled back.
+func (store *MemoryStore) begin() *MemoryStore {
+	return &MemoryStore{
+		lastUserId:         store.lastUserId,
+		lastRelationshipId: store.lastRelationshipId,
+		lastPostId:         store.lastPostId,
+		users:              store.users,
+		userIds:            store.userIds,
+		relationships:      store.relationships,
+		outgoing:           store.outgoing,
+		incoming:           store.incoming,
+		posts:              store.posts,
+		inTransaction:      true,
+	}
+}
+
+// commit keeps the changes of the transaction tx, the posts and last ids it holds replacing the ones of the store,
+// and forgets how to revert them.
+func (store *MemoryStore) commit(tx *MemoryStore) {
+	store.lastUserId = tx.lastUserId
+	store.lastRelationshipId = tx.lastRelationshipId
+	store.lastPostId = tx.lastPostId
+	store.posts = tx.posts
+	tx.undo = nil
+}
+
+// rollback reverts the changes the transaction made to the maps, last change first.
+func (store *MemoryStore) rollback() {
+	for i := len(store.undo) - 1; i >= 0; i-- {
+		store.undo[i]()
+	}
+	store.undo = nil
+}
+
+// record keeps how to revert a change when the store is the one of a transaction.
+func (store *MemoryStore) record(undo func()) {
+	if store.inTransaction {
+		store.undo = append(store.undo, undo)
+	}
+}
+
+// setUser stores the user by its id.
+func (store *MemoryStore) setUser(user models.User) {
+	store.recordUser(user.ID)
+	store.users[user.ID] = user
+}
+
+// deleteUser removes the user with the id, its email is left indexed.
+func (store *MemoryStore) deleteUser(id int64) {
+	store.recordUser(id)
+	delete(store.users, id)
+}
+
+func (store *MemoryStore) recordUser(id int64) {
+	previous, ok := store.users[id]
+	store.record(func() {
+		if ok {
+			store.users[id] = previous
+		} else {
+			delete(store.users, id)
+		}
+	})
+}
+
+// indexEmail finds the user with the id by the email.
+func (store *MemoryStore) indexEmail(email string, id int64) {
+	previous, ok := store.userIds[email]
+	store.record(func() {
+		if ok {
+			store.userIds[email] = previous
+		} else {
+			delete(store.userIds, email)
+		}
+	})
+	store.userIds[email] = id
+}
+
+func (store *MemoryStore) addRelationship(relationship models.Relationship) {
+	store.recordRelationship(relationship.ID, relationship)
+	store.relationships[relationship.ID] = relationship
+	addEdge(store.outgoing, relationship.RequestUserId, relationship.TargetUserId, relationship.ID)
+	addEdge(store.incoming, relationship.TargetUserId, relationship.RequestUserId, relationship.ID)
+}
+
+func (store *MemoryStore) removeRelationship(id int64) {
+	relationship, ok := store.relationships[id]
+	if !ok {
+		return
+	}
+
+	store.recordRelationship(id, relationship)
+	delete(store.relationships, id)
+	removeEdge(store.outgoing, relationship.RequestUserId, relationship.TargetUserId, id)
+	removeEdge(store.incoming, relationship.TargetUserId, relationship.RequestUserId, id)
+}
+
+// recordRelationship keeps how to revert a change of the relationship with the id, along with the edges between the
+// users of relationship. The edges are restored as they were, their ids staying in the same order.
+func (store *MemoryStore) recordRelationship(id int64, relationship models.Relationship) {
+	previous, ok := store.relationships[id]
+	outgoing := store.outgoing[relationship.RequestUserId][relationship.TargetUserId]
+	incoming := store.incoming[relationship.TargetUserId][relationship.RequestUserId]
+	store.record(func() {
+		if ok {
+			store.relationships[id] = previous
+		} else {
+			delete(store.relationships, id)
+		}
+		setEdges(store.outgoing, relationship.RequestUserId, relationship.TargetUserId, outgoing)
+		setEdges(store.incoming, relationship.TargetUserId, relationship.RequestUserId, incoming)
+	})
+}
+
+// relationshipIds returns the ids of the relationships from requestUserId to targetUserId with the given status.
+func (store *MemoryStore) relationshipIds(requestUserId int64, targetUserId int64, status models.RelationshipStatus) []int64 {
+	var ids []int64
+	for _, id := range store.outgoing[requestUserId][targetUserId] {
+		if store.relationships[id].Status == status {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+func (store *MemoryStore) hasRelationship(requestUserId int64, targetUserId int64, status models.RelationshipStatus) bool {
+	return len(store.relationshipIds(requestUserId, targetUserId, status)) > 0
+}
+
+// targets returns the users that userId points to with the given status.
+func (store *MemoryStore) targets(userId int64, status models.RelationshipStatus) map[int64]bool {
+	return store.neighbours(store.outgoing, userId, status)
+}
+
+// requestors returns the users pointing to userId with the given status.
+func (store *MemoryStore) requestors(userId int64, status models.RelationshipStatus) map[int64]bool {
+	return store.neighbours(store.incoming, userId, status)
+}
+
+func (store *MemoryStore) neighbours(adjacency map[int64]map[int64][]int64, userId int64, status models.RelationshipStatus) map[int64]bool {
+	result := map[int64]bool{}
+	for neighbourId, ids := range adjacency[userId] {
+		for _, id := range ids {
+			if store.relationships[id].Status == status {
+				result[neighbourId] = true
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+func (store *MemoryStore) friendIds(userId int64) map[int64]bool {
+	friendIds := store.targets(userId, models.RelationshipFriend)
+	for id := range store.requestors(userId, models.RelationshipFriend) {
+		friendIds[id] = true
+	}
+
+	return friendIds
+}
+
+// visibleFriendIds returns the active friends of userId who didn't block userId.
+func (store *MemoryStore) visibleFriendIds(userId int64) map[int64]bool {
+	friendIds := store.activeIds(store.friendIds(userId))
+	for id := range store.requestors(userId, models.RelationshipBlocked) {
+		delete(friendIds, id)
+	}
+
+	return friendIds
+}
+
+// isActive reports whether userId is a known user whose account is active.
+func (store *MemoryStore) isActive(userId int64) bool {
+	user, ok := store.users[userId]
+	return ok && user.Status == models.UserActive
+}
+
+// activeIds removes the users who aren't active from userIds and returns it.
+func (store *MemoryStore) activeIds(userIds map[int64]bool) map[int64]bool {
+	for id := range userIds {
+		if !store.isActive(id) {
+			delete(userIds, id)
+		}
+	}
+
+	return userIds
+}
+
+// emails returns the emails of the given users ordered by id, unknown ids are skipped like an inner join would.
+func (store *MemoryStore) emails(userIds map[int64]bool) []string {
+	ids := make([]int64, 0, len(userIds))
+	for id := range userIds {
+		if _, ok := store.users[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	sortIds(ids)
+
+	var emails []string
+	for _, id := range ids {
+		emails = append(emails, store.users[id].Email)
+	}
+
+	return emails
+}
+
+// pageKeys returns the keys of the page among keys sorted in ascending order and the key to continue from, 0 when
+// the page is the last one.
+func pageKeys(keys []int64, page models.Page) ([]int64, int64) {
+	start := sort.Search(len(keys), func(i int) bool { return keys[i] > page.After })
+	keys = keys[start:]
+	if len(keys) > page.Limit+1 {
+		keys = keys[:page.Limit+1]
+	}
+
+	size, next := pageEnd(keys, page.Limit)
+
+	return keys[:size], next
+}
+
+// emailPage returns a page of the emails of the given users ordered by id, unknown ids are skipped like an inner join would.
+func (store *MemoryStore) emailPage(userIds map[int64]bool, page models.Page) ([]string, int64) {
+	ids := make([]int64, 0, len(userIds))
+	for id := range userIds {
+		if _, ok := store.users[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	sortIds(ids)
+
+	ids, next := pageKeys(ids, page)
+
+	var emails []string
+	for _, id := range ids {
+		emails = append(emails, store.users[id].Email)
+	}
+
+	return emails, next
+}
+
+// unindexEmail stops finding the user by its email. The caller must hold the lock and have removed the user or
+// changed its email.
+func (store *MemoryStore) unindexEmail(user models.User) {
+	if store.userIds[user.Email] == user.ID {
+		store.record(func() { store.userIds[user.Email] = user.ID })
+		delete(store.userIds, user.Email)
+	}
+}
+
+// findIds returns the ids of the users owning the emails in ascending order. The caller must hold the lock.
+func (store *MemoryStore) findIds(emails []string) []int64 {
+	found := map[int64]bool{}
+	for _, email := range emails {
+		if id, ok := store.userIds[common.NormalizeEmail(email)]; ok {
+			found[id] = true
+		}
+	}
+
+	var ids []int64
+	for id := range found {
+		ids = append(ids, id)
+	}
+	sortIds(ids)
+
+	return ids
+}
+
+// usersOf returns the given users in the same order.
+func (store *MemoryStore) usersOf(ids []int64) []models.User {
+	var users []models.User
+	for _, id := range ids {
+		users = append(users, store.users[id])
+	}
+
+	return users
+}
+
+// userOrder returns whether an user comes before another one in the given order, ties being ordered by id.
+func (store *MemoryStore) userOrder(order models.UserSort) func(a int64, b int64) bool {
+	switch order {
+	case models.UserSortEmail:
+		return func(a int64, b int64) bool {
+			if store.users[a].Email != store.users[b].Email {
+				return store.users[a].Email < store.users[b].Email
+			}
+			return a < b
+		}
+	case models.UserSortEmailDesc:
+		return func(a int64, b int64) bool {
+			if store.users[a].Email != store.users[b].Email {
+				return store.users[a].Email > store.users[b].Email
+			}
+			return a > b
+		}
+	default:
+		return func(a int64, b int64) bool { return a < b }
+	}
+}
+
+// follows returns whether an user comes after the item a page continues from in the given order, like the SQL
+// conditions on After and AfterSort.
+func (store *MemoryStore) follows(id int64, order models.UserSort, page models.Page) bool {
+	email := store.users[id].Email
+	switch order {
+	case models.UserSortEmail:
+		return email > page.AfterSort || (email == page.AfterSort && id > page.After)
+	case models.UserSortEmailDesc:
+		return email < page.AfterSort || (email == page.AfterSort && id < page.After)
+	default:
+		return id > page.After
+	}
+}
+
+// missingUser is the error of a write referencing an unknown user, which a foreign key rejects in SQL.
+func missingUser(id int64) error {
+	return &NotFoundError{Entity: "user", Key: strconv.FormatInt(id, 10)}
+}
+
+func addEdge(adjacency map[int64]map[int64][]int64, from int64, to int64, id int64) {
+	if adjacency[from] == nil {
+		adjacency[from] = map[int64][]int64{}
+	}
+	adjacency[from][to] = append(adjacency[from][to], id)
+}
+
+func removeEdge(adjacency map[int64]map[int64][]int64, from int64, to int64, id int64) {
+	ids := adjacency[from][to]
+	for i, existingId := range ids {
+		if existingId == id {
+			ids = append(ids[:i:i], ids[i+1:]...)
+			break
+		}
+	}
+
+	setEdges(adjacency, from, to, ids)
+}
+
+// setEdges replaces the ids of the edges from a user to another one, removing the entries left empty.
+func setEdges(adjacency map[int64]map[int64][]int64, from int64, to int64, ids []int64) {
+	if len(ids) > 0 {
+		if adjacency[from] == nil {
+			adjacency[from] = map[int64][]int64{}
+		}
+		adjacency[from][to] = ids
+		return
+	}
+
+	delete(adjacency[from], to)
+	if len(adjacency[from]) == 0 {
+		delete(adjacency, from)
+	}
+}
+
+func sortIds(ids []int64) {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+}
+
+func containsId(ids []int64, id int64) bool {
+	for _, existingId := range ids {
+		if existingId == id {
+			return true
+		}
+	}
+
+	return false
+}
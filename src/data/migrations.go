@@ -0,0 +1,250 @@
+package data
+
+// migrations lists the schema changes in the order they are applied, each one with the statements of every SQL driver.
+// Never edit a released migration, append a new one instead.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "create_user",
+		Up: map[string][]string{
+			MySQL: {`
+				CREATE TABLE IF NOT EXISTS user (
+					Id int NOT NULL AUTO_INCREMENT,
+					Email varchar(24) DEFAULT NULL,
+					PRIMARY KEY (Id)
+				) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+			},
+			SQLite: {`
+				CREATE TABLE IF NOT EXISTS user (
+					Id INTEGER PRIMARY KEY AUTOINCREMENT,
+					Email varchar(24) DEFAULT NULL
+				)`,
+			},
+		},
+		Down: map[string][]string{
+			MySQL:  {`DROP TABLE IF EXISTS user`},
+			SQLite: {`DROP TABLE IF EXISTS user`},
+		},
+	},
+	{
+		Version: 2,
+		Name:    "create_relationship",
+		Up: map[string][]string{
+			MySQL: {`
+				CREATE TABLE IF NOT EXISTS relationship (
+					Id int NOT NULL AUTO_INCREMENT,
+					RequestUserId int NOT NULL,
+					TargetUserId int NOT NULL,
+					Status int NOT NULL DEFAULT '0',
+					PRIMARY KEY (Id),
+					KEY IX_Relationship_RequestUserId (RequestUserId),
+					KEY IX_Relationship_TargetUserId (TargetUserId),
+					CONSTRAINT FK_Relationship_User_RequestUserId FOREIGN KEY (RequestUserId) REFERENCES user (Id),
+					CONSTRAINT FK_Relationship_User_TargetUserId FOREIGN KEY (TargetUserId) REFERENCES user (Id)
+				) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+			},
+			SQLite: {`
+				CREATE TABLE IF NOT EXISTS relationship (
+					Id INTEGER PRIMARY KEY AUTOINCREMENT,
+					RequestUserId int NOT NULL REFERENCES user (Id),
+					TargetUserId int NOT NULL REFERENCES user (Id),
+					Status int NOT NULL DEFAULT 0
+				)`,
+				`CREATE INDEX IF NOT EXISTS IX_Relationship_RequestUserId ON relationship (RequestUserId)`,
+				`CREATE INDEX IF NOT EXISTS IX_Relationship_TargetUserId ON relationship (TargetUserId)`,
+			},
+		},
+		Down: map[string][]string{
+			MySQL:  {`DROP TABLE IF EXISTS relationship`},
+			SQLite: {`DROP TABLE IF EXISTS relationship`},
+		},
+	},
+	{
+		Version: 3,
+		Name:    "create_post",
+		Up: map[string][]string{
+			MySQL: {`
+				CREATE TABLE IF NOT EXISTS post (
+					Id int NOT NULL AUTO_INCREMENT,
+					SenderUserId int NOT NULL,
+					Text text NOT NULL,
+					CreatedAt datetime NOT NULL,
+					PRIMARY KEY (Id),
+					KEY IX_Post_SenderUserId (SenderUserId),
+					CONSTRAINT FK_Post_User_SenderUserId FOREIGN KEY (SenderUserId) REFERENCES user (Id)
+				) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+				`
+				CREATE TABLE IF NOT EXISTS post_mention (
+					PostId int NOT NULL,
+					UserId int NOT NULL,
+					PRIMARY KEY (PostId, UserId),
+					KEY IX_PostMention_UserId (UserId),
+					CONSTRAINT FK_PostMention_Post_PostId FOREIGN KEY (PostId) REFERENCES post (Id),
+					CONSTRAINT FK_PostMention_User_UserId FOREIGN KEY (UserId) REFERENCES user (Id)
+				) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4`,
+			},
+			SQLite: {`
+				CREATE TABLE IF NOT EXISTS post (
+					Id INTEGER PRIMARY KEY AUTOINCREMENT,
+					SenderUserId int NOT NULL REFERENCES user (Id),
+					Text text NOT NULL,
+					CreatedAt datetime NOT NULL
+				)`,
+				`CREATE INDEX IF NOT EXISTS IX_Post_SenderUserId ON post (SenderUserId)`,
+				`
+				CREATE TABLE IF NOT EXISTS post_mention (
+					PostId int NOT NULL REFERENCES post (Id),
+					UserId int NOT NULL REFERENCES user (Id),
+					PRIMARY KEY (PostId, UserId)
+				)`,
+				`CREATE INDEX IF NOT EXISTS IX_PostMention_UserId ON post_mention (UserId)`,
+			},
+		},
+		Down: map[string][]string{
+			MySQL:  {`DROP TABLE IF EXISTS post_mention`, `DROP TABLE IF EXISTS post`},
+			SQLite: {`DROP TABLE IF EXISTS post_mention`, `DROP TABLE IF EXISTS post`},
+		},
+	},
+	// Duplicated relationships are removed first, keeping the oldest one, so the unique index can be created.
+	{
+		Version: 4,
+		Name:    "unique_relationship",
+		Up: map[string][]string{
+			MySQL: {`
+				DELETE r FROM relationship r
+				INNER JOIN relationship k
+				ON r.RequestUserId = k.RequestUserId AND r.TargetUserId = k.TargetUserId AND r.Status = k.Status AND r.Id > k.Id`,
+				`ALTER TABLE relationship ADD UNIQUE KEY UX_Relationship_RequestUserId_TargetUserId_Status (RequestUserId, TargetUserId, Status)`,
+			},
+			SQLite: {`
+				DELETE FROM relationship
+				WHERE Id NOT IN (SELECT min(Id) FROM relationship GROUP BY RequestUserId, TargetUserId, Status)`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS UX_Relationship_RequestUserId_TargetUserId_Status ON relationship (RequestUserId, TargetUserId, Status)`,
+			},
+		},
+		Down: map[string][]string{
+			MySQL:  {`ALTER TABLE relationship DROP INDEX UX_Relationship_RequestUserId_TargetUserId_Status`},
+			SQLite: {`DROP INDEX IF EXISTS UX_Relationship_RequestUserId_TargetUserId_Status`},
+		},
+	},
+	// Relationships created before are left without a creation time. SQLite can't drop a column,
+	// so the table is copied without it to revert.
+	{
+		Version: 5,
+		Name:    "relationship_created_at",
+		Up: map[string][]string{
+			MySQL:  {`ALTER TABLE relationship ADD COLUMN CreatedAt datetime NULL`},
+			SQLite: {`ALTER TABLE relationship ADD COLUMN CreatedAt datetime NULL`},
+		},
+		Down: map[string][]string{
+			MySQL: {`ALTER TABLE relationship DROP COLUMN CreatedAt`},
+			SQLite: {`
+				CREATE TABLE relationship_down (
+					Id INTEGER PRIMARY KEY AUTOINCREMENT,
+					RequestUserId int NOT NULL REFERENCES user (Id),
+					TargetUserId int NOT NULL REFERENCES user (Id),
+					Status int NOT NULL DEFAULT 0
+				)`,
+				`INSERT INTO relationship_down (Id, RequestUserId, TargetUserId, Status) SELECT Id, RequestUserId, TargetUserId, Status FROM relationship`,
+				`DROP TABLE relationship`,
+				`ALTER TABLE relationship_down RENAME TO relationship`,
+				`CREATE INDEX IF NOT EXISTS IX_Relationship_RequestUserId ON relationship (RequestUserId)`,
+				`CREATE INDEX IF NOT EXISTS IX_Relationship_TargetUserId ON relationship (TargetUserId)`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS UX_Relationship_RequestUserId_TargetUserId_Status ON relationship (RequestUserId, TargetUserId, Status)`,
+			},
+		},
+	},
+	// Emails were limited to 24 characters. Users created before are active, without profile nor timestamps.
+	// To revert on SQLite, which can't drop a column, the users are copied aside and the table recreated: the foreign
+	// keys are checked on commit, once every referenced user is back.
+	{
+		Version: 6,
+		Name:    "user_profile",
+		Up: map[string][]string{
+			MySQL: {`
+				ALTER TABLE user
+				MODIFY Email varchar(254) DEFAULT NULL,
+				ADD COLUMN DisplayName varchar(64) NOT NULL DEFAULT '',
+				ADD COLUMN AvatarUrl varchar(2048) NOT NULL DEFAULT '',
+				ADD COLUMN Status varchar(16) NOT NULL DEFAULT 'active',
+				ADD COLUMN CreatedAt datetime NULL,
+				ADD COLUMN UpdatedAt datetime NULL`,
+			},
+			SQLite: {
+				`ALTER TABLE user ADD COLUMN DisplayName varchar(64) NOT NULL DEFAULT ''`,
+				`ALTER TABLE user ADD COLUMN AvatarUrl varchar(2048) NOT NULL DEFAULT ''`,
+				`ALTER TABLE user ADD COLUMN Status varchar(16) NOT NULL DEFAULT 'active'`,
+				`ALTER TABLE user ADD COLUMN CreatedAt datetime NULL`,
+				`ALTER TABLE user ADD COLUMN UpdatedAt datetime NULL`,
+			},
+		},
+		Down: map[string][]string{
+			MySQL: {`
+				ALTER TABLE user
+				DROP COLUMN DisplayName,
+				DROP COLUMN AvatarUrl,
+				DROP COLUMN Status,
+				DROP COLUMN CreatedAt,
+				DROP COLUMN UpdatedAt,
+				MODIFY Email varchar(24) DEFAULT NULL`,
+			},
+			SQLite: {
+				`PRAGMA defer_foreign_keys = ON`,
+				`CREATE TEMP TABLE user_down AS SELECT Id, Email FROM user`,
+				`DROP TABLE user`,
+				`
+				CREATE TABLE user (
+					Id INTEGER PRIMARY KEY AUTOINCREMENT,
+					Email varchar(24) DEFAULT NULL
+				)`,
+				`INSERT INTO user (Id, Email) SELECT Id, Email FROM user_down`,
+				`DROP TABLE user_down`,
+			},
+		},
+	},
+	// Users sharing an email make the index fail, they are listed by "emails report" to be resolved first, which can be
+	// run without applying the migrations by --auto-migrate=false. MySQL compares the emails regardless of case.
+	{
+		Version: 7,
+		Name:    "unique_user_email",
+		Up: map[string][]string{
+			MySQL:  {`ALTER TABLE user ADD UNIQUE KEY UX_User_Email (Email)`},
+			SQLite: {`CREATE UNIQUE INDEX IF NOT EXISTS UX_User_Email ON user (Email)`},
+		},
+		Down: map[string][]string{
+			MySQL:  {`ALTER TABLE user DROP INDEX UX_User_Email`},
+			SQLite: {`DROP INDEX IF EXISTS UX_User_Email`},
+		},
+	},
+	// A friendship is stored once whatever its direction: the reversed friend relationships are removed first, keeping
+	// the oldest one, and the index is on the ids of both users in ascending order. The other statuses keep a
+	// direction, so they are left out of the index.
+	{
+		Version: 8,
+		Name:    "unique_friendship",
+		Up: map[string][]string{
+			MySQL: {`
+				DELETE r FROM relationship r
+				INNER JOIN relationship k
+				ON r.RequestUserId = k.TargetUserId AND r.TargetUserId = k.RequestUserId AND r.Status = 1 AND k.Status = 1 AND r.Id > k.Id`,
+				`
+				ALTER TABLE relationship ADD UNIQUE KEY UX_Relationship_Friendship (
+					(IF(Status = 1, LEAST(RequestUserId, TargetUserId), NULL)),
+					(IF(Status = 1, GREATEST(RequestUserId, TargetUserId), NULL))
+				)`,
+			},
+			SQLite: {`
+				DELETE FROM relationship
+				WHERE Status = 1 AND EXISTS (
+					SELECT 1 FROM relationship k
+					WHERE k.RequestUserId = relationship.TargetUserId AND k.TargetUserId = relationship.RequestUserId AND k.Status = 1 AND k.Id < relationship.Id
+				)`,
+				`CREATE UNIQUE INDEX IF NOT EXISTS UX_Relationship_Friendship ON relationship (min(RequestUserId, TargetUserId), max(RequestUserId, TargetUserId)) WHERE Status = 1`,
+			},
+		},
+		Down: map[string][]string{
+			MySQL:  {`ALTER TABLE relationship DROP INDEX UX_Relationship_Friendship`},
+			SQLite: {`DROP INDEX IF EXISTS UX_Relationship_Friendship`},
+		},
+	},
+}
@@ -0,0 +1,154 @@
+package data
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Migration is a versioned schema change. Up and Down hold the statements to run for each SQL driver.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      map[string][]string
+	Down    map[string][]string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrator applies the migrations to a database and records the applied versions in the schema_migrations table.
+type Migrator struct {
+	DB     *sql.DB
+	Driver string
+}
+
+// Up applies every pending migration in order and returns the ones it applied.
+func (m Migrator) Up() ([]Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err := m.run(migration.Up[m.Driver], `INSERT INTO schema_migrations (Version, Name, AppliedAt) VALUES (?,?,?)`, migration.Version, migration.Name, time.Now().UTC())
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s: %v", migration.Version, migration.Name, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Down reverts the last applied migration, it returns nil if there is nothing to revert.
+func (m Migrator) Down() (*Migration, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		err := m.run(migration.Down[m.Driver], `DELETE FROM schema_migrations WHERE Version =?`, migration.Version)
+		if err != nil {
+			return nil, fmt.Errorf("migration %d %s: %v", migration.Version, migration.Name, err)
+		}
+
+		return &migration, nil
+	}
+
+	return nil, nil
+}
+
+// Status returns every known migration, AppliedAt is nil for the pending ones.
+func (m Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		statuses[i] = MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			appliedAt := appliedAt
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return statuses, nil
+}
+
+// run executes the statements of a migration then the bookkeeping statement in a single transaction.
+// MySQL commits the schema changes implicitly though, so a migration failing there can be partly applied without
+// being recorded: when it runs again, the MySQL statements failing because their change is already done are skipped.
+func (m Migrator) run(statements []string, bookkeeping string, args ...interface{}) error {
+	if statements == nil {
+		return fmt.Errorf("no statements for driver %q", m.Driver)
+	}
+
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil && !(m.Driver == MySQL && isAlreadyApplied(err)) {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(bookkeeping, args...); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m Migrator) appliedVersions() (map[int64]time.Time, error) {
+	query := `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			Version bigint NOT NULL PRIMARY KEY,
+			Name varchar(255) NOT NULL,
+			AppliedAt datetime NOT NULL
+		)
+	`
+
+	if _, err := m.DB.Exec(query); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.DB.Query(`SELECT Version, AppliedAt FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	return applied, rows.Err()
+}
@@ -0,0 +1,132 @@
+package data_test
+
+import (
+	"database/sql"
+	"friendMgmt/data"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newSQLiteDB(t *testing.T) *sql.DB {
+	db, err := data.InitDB(data.DBConfig{Driver: data.SQLite, Path: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestMigratorUpAndDown(t *testing.T) {
+	db := newSQLiteDB(t)
+	migrator := data.Migrator{DB: db, Driver: data.SQLite}
+
+	statuses, err := migrator.Status()
+	assert.Nil(t, err)
+	for _, status := range statuses {
+		assert.Nil(t, status.AppliedAt)
+	}
+
+	applied, err := migrator.Up()
+	assert.Nil(t, err)
+	assert.Equal(t, len(statuses), len(applied))
+
+	applied, err = migrator.Up()
+	assert.Nil(t, err)
+	assert.Empty(t, applied)
+
+	_, err = db.Exec(`INSERT INTO user (Email) VALUES ('johndoe@gmail.com')`)
+	assert.Nil(t, err)
+
+	reverted, err := migrator.Down()
+	assert.Nil(t, err)
+	assert.Equal(t, statuses[len(statuses)-1].Version, reverted.Version)
+
+	statuses, err = migrator.Status()
+	assert.Nil(t, err)
+	assert.NotNil(t, statuses[0].AppliedAt)
+	assert.Nil(t, statuses[len(statuses)-1].AppliedAt)
+
+	applied, err = migrator.Up()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(applied))
+
+	var count int
+	db.QueryRow(`SELECT count(*) FROM user`).Scan(&count)
+	assert.Equal(t, 1, count)
+}
+
+func TestMigratorDownWithoutMigrations(t *testing.T) {
+	migrator := data.Migrator{DB: newSQLiteDB(t), Driver: data.SQLite}
+
+	reverted, err := migrator.Down()
+
+	assert.Nil(t, err)
+	assert.Nil(t, reverted)
+}
+
+func TestMigratorUniqueEmailFailsOnDuplicates(t *testing.T) {
+	db := newSQLiteDB(t)
+	migrator := data.Migrator{DB: db, Driver: data.SQLite}
+	migrator.Up()
+
+	reverted, err := migrator.Down()
+	assert.Nil(t, err)
+	assert.Equal(t, "unique_friendship", reverted.Name)
+	reverted, err = migrator.Down()
+	assert.Nil(t, err)
+	assert.Equal(t, "unique_user_email", reverted.Name)
+
+	_, err = db.Exec(`INSERT INTO user (Email) VALUES ('johndoe@gmail.com'), ('johndoe@gmail.com')`)
+	assert.Nil(t, err)
+
+	applied, err := migrator.Up()
+	assert.Error(t, err)
+	assert.Empty(t, applied)
+
+	_, err = db.Exec(`DELETE FROM user WHERE Id = 2`)
+	assert.Nil(t, err)
+
+	applied, err = migrator.Up()
+	assert.Nil(t, err)
+	assert.Len(t, applied, 2)
+
+	_, err = db.Exec(`INSERT INTO user (Email) VALUES ('johndoe@gmail.com')`)
+	assert.Error(t, err)
+}
+
+func TestMigratorUniqueFriendshipRemovesReversedFriends(t *testing.T) {
+	db := newSQLiteDB(t)
+	migrator := data.Migrator{DB: db, Driver: data.SQLite}
+	migrator.Up()
+
+	reverted, err := migrator.Down()
+	assert.Nil(t, err)
+	assert.Equal(t, "unique_friendship", reverted.Name)
+
+	_, err = db.Exec(`INSERT INTO user (Email) VALUES ('johndoe@gmail.com'), ('janedoe@gmail.com')`)
+	assert.Nil(t, err)
+	_, err = db.Exec(`INSERT INTO relationship (RequestUserId, TargetUserId, Status) VALUES (2, 1, 1), (1, 2, 1), (1, 2, 2)`)
+	assert.Nil(t, err)
+
+	applied, err := migrator.Up()
+	assert.Nil(t, err)
+	assert.Len(t, applied, 1)
+
+	var ids []int64
+	rows, err := db.Query(`SELECT Id FROM relationship ORDER BY Id`)
+	assert.Nil(t, err)
+	for rows.Next() {
+		var id int64
+		rows.Scan(&id)
+		ids = append(ids, id)
+	}
+	rows.Close()
+	assert.Equal(t, []int64{1, 3}, ids)
+
+	_, err = db.Exec(`INSERT INTO relationship (RequestUserId, TargetUserId, Status) VALUES (1, 2, 1)`)
+	assert.Error(t, err)
+	_, err = db.Exec(`INSERT INTO relationship (RequestUserId, TargetUserId, Status) VALUES (2, 1, 2)`)
+	assert.Nil(t, err)
+}
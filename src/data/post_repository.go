@@ -0,0 +1,244 @@
+package data
+
+import (
+	"database/sql"
+	"friendMgmt/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type IPostRepository interface {
+	CreatePost(senderId int64, text string, mentionIds []int64, createdAt time.Time) (int64, error)
+	GetFeed(userId int64, page models.Page) ([]models.Post, int64, error)
+	GetPostAudience(postId int64) (int64, []int64, error)
+	DeleteUserPosts(userId int64) (int, int, error)
+	MoveUserPosts(fromUserId int64, toUserId int64) (int, int, error)
+}
+
+type PostRepository struct {
+	DB DBTX
+}
+
+func (repo PostRepository) CreatePost(senderId int64, text string, mentionIds []int64, createdAt time.Time) (int64, error) {
+	var postId int64
+	err := withTx(repo.DB, func(tx DBTX) error {
+		res, err := tx.Exec(`INSERT INTO post (SenderUserId, Text, CreatedAt) VALUES (?,?,?)`, senderId, text, createdAt)
+		if err != nil {
+			return err
+		}
+
+		postId, err = res.LastInsertId()
+		if err != nil {
+			return err
+		}
+
+		for _, mentionId := range distinctIds(mentionIds) {
+			if _, err := tx.Exec(`INSERT INTO post_mention (PostId, UserId) VALUES (?,?)`, postId, mentionId); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return postId, nil
+}
+
+// GetFeed returns a page of the posts userId is allowed to see, newest first, so the page continues with the posts
+// older than page.After. Like GetValidUsersCanReceiveUpdates, a post reaches the friends and subscribers of its sender
+// and the users it mentions, unless they blocked the sender or the sender isn't active anymore.
+func (repo PostRepository) GetFeed(userId int64, page models.Page) ([]models.Post, int64, error) {
+	query := `
+		select p.id, u.email, p.text, p.createdat
+		from post p inner join user u on u.id = p.senderuserid
+		where p.senderuserid <> ?
+		and (p.senderuserid in (
+		select TargetUserId id from relationship
+		where RequestUserId =? and status in (?,?)
+		union
+		select RequestUserId id from relationship
+		where TargetUserId =? and status = ?)
+		or p.id in (
+		select PostId from post_mention
+		where UserId =?))
+		and p.senderuserid not in (
+		select TargetUserId from relationship
+		where RequestUserId =? and status = ?)
+		and u.Status = ?
+		and (? = 0 or p.id < ?)
+		order by p.id desc
+		limit ?
+	`
+
+	rows, err := repo.DB.Query(query, userId, userId, models.RelationshipFriend, models.RelationshipSubscribed, userId, models.RelationshipFriend, userId, userId, models.RelationshipBlocked, models.UserActive, page.After, page.After, page.Limit+1)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var keys []int64
+	var posts []models.Post
+	for rows.Next() {
+		var post models.Post
+		if err := rows.Scan(&post.ID, &post.Sender, &post.Text, &post.CreatedAt); err != nil {
+			return nil, 0, err
+		}
+		keys = append(keys, post.ID)
+		posts = append(posts, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	size, next := pageEnd(keys, page.Limit)
+	posts = posts[:size]
+
+	if err := repo.loadMentions(posts); err != nil {
+		return nil, 0, err
+	}
+
+	return posts, next, nil
+}
+
+// GetPostAudience returns the sender of a post and the users it mentions, or a NotFoundError.
+func (repo PostRepository) GetPostAudience(postId int64) (int64, []int64, error) {
+	var senderId int64
+	err := repo.DB.QueryRow(`SELECT SenderUserId FROM post WHERE Id = ?`, postId).Scan(&senderId)
+	if err == sql.ErrNoRows {
+		return 0, nil, missingPost(postId)
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+
+	mentionIds, err := queryIds(repo.DB, `SELECT UserId FROM post_mention WHERE PostId = ? ORDER BY UserId`, postId)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return senderId, mentionIds, nil
+}
+
+// missingPost is the error of reading an unknown post.
+func missingPost(id int64) error {
+	return &NotFoundError{Entity: "post", Key: strconv.FormatInt(id, 10)}
+}
+
+func (repo PostRepository) loadMentions(posts []models.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, len(posts))
+	indexes := make(map[int64]int, len(posts))
+	for i, post := range posts {
+		args[i] = post.ID
+		indexes[post.ID] = i
+	}
+
+	query := `
+		select pm.postid, u.email
+		from post_mention pm inner join user u on u.id = pm.userid
+		where pm.postid in (?` + strings.Repeat(",?", len(args)-1) + `)
+		order by u.id
+	`
+
+	rows, err := repo.DB.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postId int64
+		var email string
+		if err := rows.Scan(&postId, &email); err != nil {
+			return err
+		}
+		posts[indexes[postId]].Mentions = append(posts[indexes[postId]].Mentions, email)
+	}
+
+	return rows.Err()
+}
+
+// DeleteUserPosts deletes the posts of an user with their mentions, and the mentions of the user in other posts.
+// It returns how many posts and mentions were deleted.
+func (repo PostRepository) DeleteUserPosts(userId int64) (int, int, error) {
+	var posts, mentions int64
+	err := withTx(repo.DB, func(tx DBTX) error {
+		res, err := tx.Exec(`DELETE FROM post_mention WHERE UserId = ? OR PostId IN (SELECT Id FROM post WHERE SenderUserId = ?)`, userId, userId)
+		if err != nil {
+			return err
+		}
+		if mentions, err = res.RowsAffected(); err != nil {
+			return err
+		}
+
+		res, err = tx.Exec(`DELETE FROM post WHERE SenderUserId = ?`, userId)
+		if err != nil {
+			return err
+		}
+		posts, err = res.RowsAffected()
+
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int(posts), int(mentions), nil
+}
+
+// MoveUserPosts gives the posts and the mentions of an user to another one and returns how many posts and mentions
+// were moved. A mention of both users in the same post is kept once.
+func (repo PostRepository) MoveUserPosts(fromUserId int64, toUserId int64) (int, int, error) {
+	var posts, mentions int64
+	err := withTx(repo.DB, func(tx DBTX) error {
+		// MySQL can't read the table a delete changes but from a derived table.
+		_, err := tx.Exec(`
+			DELETE FROM post_mention
+			WHERE UserId = ? AND PostId IN (SELECT PostId FROM (SELECT PostId FROM post_mention WHERE UserId = ?) m)`, fromUserId, toUserId)
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.Exec(`UPDATE post_mention SET UserId = ? WHERE UserId = ?`, toUserId, fromUserId)
+		if err != nil {
+			return err
+		}
+		if mentions, err = res.RowsAffected(); err != nil {
+			return err
+		}
+
+		res, err = tx.Exec(`UPDATE post SET SenderUserId = ? WHERE SenderUserId = ?`, toUserId, fromUserId)
+		if err != nil {
+			return err
+		}
+		posts, err = res.RowsAffected()
+
+		return err
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return int(posts), int(mentions), nil
+}
+
+func distinctIds(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+
+	var result []int64
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,167 @@
+package data
+
+import (
+	"friendMgmt/models"
+	"time"
+)
+
+// PostRepositoryMemory implements IPostRepository on top of a MemoryStore.
+type PostRepositoryMemory struct {
+	Store *MemoryStore
+}
+
+func (repo PostRepositoryMemory) CreatePost(senderId int64, text string, mentionIds []int64, createdAt time.Time) (int64, error) {
+	repo.Store.mu.Lock()
+	defer repo.Store.mu.Unlock()
+
+	if _, ok := repo.Store.users[senderId]; !ok {
+		return 0, missingUser(senderId)
+	}
+
+	mentionIds = distinctIds(mentionIds)
+	for _, mentionId := range mentionIds {
+		if _, ok := repo.Store.users[mentionId]; !ok {
+			return 0, missingUser(mentionId)
+		}
+	}
+
+	repo.Store.lastPostId++
+	repo.Store.posts = append(repo.Store.posts, memoryPost{
+		ID:           repo.Store.lastPostId,
+		SenderUserId: senderId,
+		Text:         text,
+		MentionIds:   mentionIds,
+		CreatedAt:    createdAt,
+	})
+
+	return repo.Store.lastPostId, nil
+}
+
+func (repo PostRepositoryMemory) DeleteUserPosts(userId int64) (int, int, error) {
+	repo.Store.mu.Lock()
+	defer repo.Store.mu.Unlock()
+
+	var posts, mentions int
+	kept := make([]memoryPost, 0, len(repo.Store.posts))
+	for _, post := range repo.Store.posts {
+		if post.SenderUserId == userId {
+			posts++
+			mentions += len(post.MentionIds)
+			continue
+		}
+		if containsId(post.MentionIds, userId) {
+			// Stored posts are shared with the copies of the store, the mentions are replaced rather than changed.
+			mentionIds := make([]int64, 0, len(post.MentionIds)-1)
+			for _, mentionId := range post.MentionIds {
+				if mentionId != userId {
+					mentionIds = append(mentionIds, mentionId)
+				}
+			}
+			post.MentionIds = mentionIds
+			mentions++
+		}
+		kept = append(kept, post)
+	}
+	repo.Store.posts = kept
+
+	return posts, mentions, nil
+}
+
+func (repo PostRepositoryMemory) MoveUserPosts(fromUserId int64, toUserId int64) (int, int, error) {
+	repo.Store.mu.Lock()
+	defer repo.Store.mu.Unlock()
+
+	var posts, mentions int
+	moved := make([]memoryPost, 0, len(repo.Store.posts))
+	for _, post := range repo.Store.posts {
+		if post.SenderUserId == fromUserId {
+			post.SenderUserId = toUserId
+			posts++
+		}
+		if containsId(post.MentionIds, fromUserId) {
+			// Stored posts are shared with the copies of the store, the mentions are replaced rather than changed.
+			var mentionIds []int64
+			for _, mentionId := range post.MentionIds {
+				if mentionId == fromUserId {
+					mentionId = toUserId
+				}
+				mentionIds = append(mentionIds, mentionId)
+			}
+			if !containsId(post.MentionIds, toUserId) {
+				mentions++
+			}
+			post.MentionIds = distinctIds(mentionIds)
+		}
+		moved = append(moved, post)
+	}
+	repo.Store.posts = moved
+
+	return posts, mentions, nil
+}
+
+func (repo PostRepositoryMemory) GetFeed(userId int64, page models.Page) ([]models.Post, int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	friendIds := repo.Store.friendIds(userId)
+	subscribedIds := repo.Store.targets(userId, models.RelationshipSubscribed)
+	blockedIds := repo.Store.targets(userId, models.RelationshipBlocked)
+
+	var keys []int64
+	var posts []models.Post
+	for i := len(repo.Store.posts) - 1; i >= 0 && len(posts) <= page.Limit; i-- {
+		post := repo.Store.posts[i]
+		if page.After > 0 && post.ID >= page.After {
+			continue
+		}
+		if post.SenderUserId == userId || blockedIds[post.SenderUserId] || !repo.Store.isActive(post.SenderUserId) {
+			continue
+		}
+
+		mentioned := false
+		for _, mentionId := range post.MentionIds {
+			if mentionId == userId {
+				mentioned = true
+				break
+			}
+		}
+
+		if !friendIds[post.SenderUserId] && !subscribedIds[post.SenderUserId] && !mentioned {
+			continue
+		}
+
+		mentionIds := map[int64]bool{}
+		for _, mentionId := range post.MentionIds {
+			mentionIds[mentionId] = true
+		}
+
+		posts = append(posts, models.Post{
+			ID:        post.ID,
+			Sender:    repo.Store.users[post.SenderUserId].Email,
+			Text:      post.Text,
+			Mentions:  repo.Store.emails(mentionIds),
+			CreatedAt: post.CreatedAt,
+		})
+		keys = append(keys, post.ID)
+	}
+
+	size, next := pageEnd(keys, page.Limit)
+
+	return posts[:size], next, nil
+}
+
+func (repo PostRepositoryMemory) GetPostAudience(postId int64) (int64, []int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	for _, post := range repo.Store.posts {
+		if post.ID == postId {
+			mentionIds := append([]int64(nil), post.MentionIds...)
+			sortIds(mentionIds)
+
+			return post.SenderUserId, mentionIds, nil
+		}
+	}
+
+	return 0, nil, missingPost(postId)
+}
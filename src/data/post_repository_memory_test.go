@@ -0,0 +1,142 @@
+package data_test
+
+import (
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func feedTexts(posts []models.Post, next int64, err error) []string {
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var texts []string
+	for _, post := range posts {
+		texts = append(texts, post.Text)
+	}
+
+	return texts
+}
+
+func TestMemoryGetFeed(t *testing.T) {
+	createdAt := time.Date(2020, 4, 13, 11, 46, 35, 0, time.UTC)
+
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IPostRepository
+
+		assert.Equal(t, int64(1), noErr(repo.CreatePost(1, "from a", nil, createdAt)), name)
+		assert.Equal(t, int64(2), noErr(repo.CreatePost(4, "from d to a and f", []int64{1, 6, 6}, createdAt)), name)
+		assert.Equal(t, int64(3), noErr(repo.CreatePost(2, "from b to e", []int64{5}, createdAt)), name)
+		assert.Equal(t, int64(4), noErr(repo.CreatePost(1, "from a to f", []int64{6}, createdAt)), name)
+
+		assert.Equal(t, []string{"from b to e", "from d to a and f"}, feedTexts(repo.GetFeed(1, models.Page{Limit: 10})), name)
+		assert.Equal(t, []string{"from a to f", "from a"}, feedTexts(repo.GetFeed(2, models.Page{Limit: 10})), name)
+		assert.Equal(t, []string{"from a to f", "from b to e", "from a"}, feedTexts(repo.GetFeed(5, models.Page{Limit: 10})), name)
+		assert.Equal(t, []string{"from d to a and f"}, feedTexts(repo.GetFeed(6, models.Page{Limit: 10})), name)
+		assert.Equal(t, []string{"from b to e"}, feedTexts(repo.GetFeed(5, models.Page{After: 4, Limit: 1})), name)
+
+		_, next, err := repo.GetFeed(5, models.Page{Limit: 2})
+		assert.NoError(t, err, name)
+		assert.Equal(t, int64(3), next, name)
+		assert.Equal(t, []string{"from a"}, feedTexts(repo.GetFeed(5, models.Page{After: next, Limit: 2})), name)
+
+		senderId, mentionIds, err := repo.GetPostAudience(2)
+		assert.NoError(t, err, name)
+		assert.Equal(t, int64(4), senderId, name)
+		assert.Equal(t, []int64{1, 6}, mentionIds, name)
+		_, _, err = repo.GetPostAudience(9)
+		assert.True(t, data.IsNotFound(err), name)
+
+		feed, _, err := repo.GetFeed(6, models.Page{Limit: 10})
+		assert.NoError(t, err, name)
+		assert.Equal(t, int64(2), feed[0].ID, name)
+		assert.Equal(t, "d@email.com", feed[0].Sender, name)
+		assert.Equal(t, []string{"a@email.com", "f@email.com"}, feed[0].Mentions, name)
+		assert.True(t, createdAt.Equal(feed[0].CreatedAt), name)
+	}
+}
+
+func TestMemoryGetFeedWithInactiveSender(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repositories.IPostRepository.CreatePost(2, "from b", nil, createdAt)
+		repositories.IPostRepository.CreatePost(3, "from c", nil, createdAt)
+		suspend(t, repositories, "c@email.com")
+
+		assert.Equal(t, []string{"from b"}, feedTexts(repositories.IPostRepository.GetFeed(1, all)), name)
+	}
+}
+
+func TestMemoryDeleteUser(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repositories.IPostRepository.CreatePost(1, "from a to b", []int64{2}, createdAt)
+		repositories.IPostRepository.CreatePost(2, "from b to a and c", []int64{1, 3}, createdAt)
+
+		var relationships, posts, mentions int
+		err := repositories.IUnitOfWork.Do(func(tx data.Repositories) error {
+			var err error
+			if relationships, err = tx.IRelationshipRepository.DeleteUserRelationships(1); err != nil {
+				return err
+			}
+			if posts, mentions, err = tx.IPostRepository.DeleteUserPosts(1); err != nil {
+				return err
+			}
+
+			return tx.IUserRepository.DeleteUser(1)
+		})
+
+		assert.Nil(t, err, name)
+		assert.Equal(t, []int{5, 1, 2}, []int{relationships, posts, mentions}, name)
+		_, err = repositories.IUserRepository.CheckUserExist("a@email.com")
+		assert.True(t, data.IsNotFound(err), name)
+		assert.Equal(t, []string{"c@email.com"}, noErrPage(repositories.IRelationshipRepository.GetFriendList(2, all)), name)
+		assert.Equal(t, []string{"from b to a and c"}, feedTexts(repositories.IPostRepository.GetFeed(3, all)), name)
+
+		senderId, mentionIds, err := repositories.IPostRepository.GetPostAudience(2)
+		assert.Nil(t, err, name)
+		assert.Equal(t, int64(2), senderId, name)
+		assert.Equal(t, []int64{3}, mentionIds, name)
+	}
+}
+
+func TestMemoryMoveUserPosts(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repositories.IPostRepository.CreatePost(5, "from e to a", []int64{1}, createdAt)
+		repositories.IPostRepository.CreatePost(2, "from b to e and d", []int64{5, 4}, createdAt)
+		repositories.IPostRepository.CreatePost(3, "from c to e", []int64{5}, createdAt)
+
+		posts, mentions, err := repositories.IPostRepository.MoveUserPosts(5, 4)
+
+		assert.Nil(t, err, name)
+		assert.Equal(t, []int{1, 1}, []int{posts, mentions}, name)
+
+		senderId, mentionIds, err := repositories.IPostRepository.GetPostAudience(1)
+		assert.Nil(t, err, name)
+		assert.Equal(t, int64(4), senderId, name)
+		assert.Equal(t, []int64{1}, mentionIds, name)
+
+		_, mentionIds, err = repositories.IPostRepository.GetPostAudience(2)
+		assert.Nil(t, err, name)
+		assert.Equal(t, []int64{4}, mentionIds, name)
+
+		_, mentionIds, err = repositories.IPostRepository.GetPostAudience(3)
+		assert.Nil(t, err, name)
+		assert.Equal(t, []int64{4}, mentionIds, name)
+	}
+}
+
+func TestMemoryDeleteReferencedUser(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+
+		assert.NotNil(t, repositories.IUserRepository.DeleteUser(1), name)
+		assert.Equal(t, int64(1), noErr(repositories.IUserRepository.CheckUserExist("a@email.com")), name)
+	}
+}
@@ -0,0 +1,42 @@
+package data
+
+import (
+	"friendMgmt/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PostRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *PostRepositoryMock) CreatePost(senderId int64, text string, mentionIds []int64, createdAt time.Time) (int64, error) {
+	args := m.Called(senderId, text, mentionIds, createdAt)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *PostRepositoryMock) GetFeed(userId int64, page models.Page) ([]models.Post, int64, error) {
+	args := m.Called(userId, page)
+
+	return args.Get(0).([]models.Post), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *PostRepositoryMock) GetPostAudience(postId int64) (int64, []int64, error) {
+	args := m.Called(postId)
+
+	return args.Get(0).(int64), args.Get(1).([]int64), args.Error(2)
+}
+
+func (m *PostRepositoryMock) DeleteUserPosts(userId int64) (int, int, error) {
+	args := m.Called(userId)
+
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func (m *PostRepositoryMock) MoveUserPosts(fromUserId int64, toUserId int64) (int, int, error) {
+	args := m.Called(fromUserId, toUserId)
+
+	return args.Int(0), args.Int(1), args.Error(2)
+}
@@ -0,0 +1,82 @@
+package data
+
+import "friendMgmt/models"
+
+// queryEmails runs a query selecting a single email column.
+func queryEmails(db DBTX, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var emails []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		emails = append(emails, email)
+	}
+
+	return emails, rows.Err()
+}
+
+// queryEmailPage runs a query selecting a key and an email column, ordered by the key and ending with a limit
+// placeholder. It returns the emails of the page and the key to continue from, 0 when the page is the last one.
+func queryEmailPage(db DBTX, query string, page models.Page, args ...interface{}) ([]string, int64, error) {
+	rows, err := db.Query(query, append(args, page.Limit+1)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var keys []int64
+	var emails []string
+	for rows.Next() {
+		var key int64
+		var email string
+		if err := rows.Scan(&key, &email); err != nil {
+			return nil, 0, err
+		}
+		keys = append(keys, key)
+		emails = append(emails, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	size, next := pageEnd(keys, page.Limit)
+
+	return emails[:size], next, nil
+}
+
+// pageEnd tells how many of the items read for a page, one more than the limit at most, belong to it
+// and the key to continue from, 0 when no item follows.
+func pageEnd(keys []int64, limit int) (int, int64) {
+	if len(keys) <= limit {
+		return len(keys), 0
+	}
+
+	return limit, keys[limit-1]
+}
+
+// queryIds runs a query selecting a single id column.
+func queryIds(db DBTX, query string, args ...interface{}) ([]int64, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
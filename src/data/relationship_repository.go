@@ -1,244 +1,590 @@
 package data
 
 import (
-	"database/sql"
 	"fmt"
 	"friendMgmt/models"
-	"strconv"
 	"strings"
 )
 
 type IRelationshipRepository interface {
-	CreateRelationship(relationship *models.Relationship) int64
-	DeleteRelationships(ids []int64) bool
-	GetFriendList(id int64) []string
-	GetCommonFriendList(id int64, withId int64) []string
-	GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64) []string
-	CheckRelationshipTwoWay(requestUserId int64, targetUserId int64, status int64) []int64
-	CheckRelationshipOneWay(requestUserId int64, targetUserId int64, status int64) []int64
+	CreateRelationship(relationship *models.Relationship) (int64, error)
+	DeleteRelationships(ids []int64) error
+	DeleteUserRelationships(id int64) (int, error)
+	GetUserRelationships(id int64) ([]models.Relationship, error)
+	GetRelationships(page models.Page) ([]models.Relationship, int64, error)
+	GetFriendList(id int64, page models.Page) ([]string, int64, error)
+	GetCommonFriendList(ids []int64, page models.Page) ([]string, int64, error)
+	CountCommonFriends(ids []int64) (map[int64]map[int64]int, error)
+	GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64, page models.Page) ([]string, int64, error)
+	CheckRelationshipTwoWay(requestUserId int64, targetUserId int64, status models.RelationshipStatus) ([]int64, error)
+	CheckRelationshipOneWay(requestUserId int64, targetUserId int64, status models.RelationshipStatus) ([]int64, error)
+	GetIncomingFriendRequests(id int64, page models.Page) ([]string, int64, error)
+	GetOutgoingFriendRequests(id int64, page models.Page) ([]string, int64, error)
+	GetFriendSuggestions(id int64, limit int) ([]models.SuggestedFriend, error)
+	GetFriendIds(ids []int64) (map[int64][]int64, error)
+	GetBlockedUsers(id int64, page models.Page) ([]models.BlockedUser, int64, error)
+	GetFollowers(id int64, page models.Page) ([]string, int64, error)
+	GetFollowing(id int64, page models.Page) ([]string, int64, error)
+	CountRelationships(id int64) (models.UserSummary, error)
 }
 
 type RelationshipRepository struct {
-	DB *sql.DB
+	DB DBTX
 }
 
-func (repo RelationshipRepository) GetFriendList(id int64) []string {
+// GetFriendList returns a page of the active friends of an user ordered by id, except the ones who blocked the user.
+func (repo RelationshipRepository) GetFriendList(id int64, page models.Page) ([]string, int64, error) {
 	query := `
-		select u.email
+		select u.id, u.email
 		from user u inner join 
 		(select TargetUserId id from relationship
-		where RequestUserId =? and status = 1
+		where RequestUserId =? and status = ?
 		union
 		select RequestUserId id from relationship
-		where TargetUserId =? and status = 1) ids
-		on u.id = ids.id;
+		where TargetUserId =? and status = ?) ids
+		on u.id = ids.id
+		where u.id > ? and u.Status = ? and u.id not in (
+		select RequestUserId from relationship
+		where TargetUserId =? and status = ?)
+		order by u.id
+		limit ?;
 	`
 
-	rows, err := repo.DB.Query(query, id, id)
-	if err != nil {
-		fmt.Println(err)
-	}
+	return queryEmailPage(repo.DB, query, page, id, models.RelationshipFriend, id, models.RelationshipFriend, page.After, models.UserActive, id, models.RelationshipBlocked)
+}
 
-	var emails []string
-	for rows.Next() {
-		var email string
-		rows.Scan(&email)
-		emails = append(emails, email)
+// GetCommonFriendList returns a page of the active friends all the users have ordered by id, except the ones who
+// blocked the first user.
+func (repo RelationshipRepository) GetCommonFriendList(ids []int64, page models.Page) ([]string, int64, error) {
+	if len(ids) == 0 {
+		return nil, 0, nil
 	}
 
-	return emails
+	friends, friendArgs := friendOwnersQuery(ids)
+	query := `
+	select u.id, u.email
+	from user u inner join
+	(select f.id from ` + friends + ` f
+	group by f.id
+	having count(distinct f.owner) = ?) c
+	on u.id = c.id
+	where u.id > ? and u.Status = ? and u.id not in (
+	select RequestUserId from relationship
+	where TargetUserId =? and status = ?)
+	order by u.id
+	limit ?;
+	`
+
+	args := append(friendArgs, len(distinctIds(ids)), page.After, models.UserActive, ids[0], models.RelationshipBlocked)
+
+	return queryEmailPage(repo.DB, query, page, args...)
 }
 
-func (repo RelationshipRepository) GetCommonFriendList(id int64, withId int64) []string {
+// CountCommonFriends returns the number of common friends of every pair of the users in a single query, keyed by the
+// id of each user then by the id of the other one. The common friends who blocked the first user of a pair or who
+// aren't active are left out like GetCommonFriendList does, and the pairs without common friend are omitted.
+func (repo RelationshipRepository) CountCommonFriends(ids []int64) (map[int64]map[int64]int, error) {
+	counts := make(map[int64]map[int64]int, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	friends, friendArgs := friendOwnersQuery(ids)
 	query := `
-	select u.email
-	from user u inner join
-	(select l.id from
-	(select TargetUserId id from relationship
-	where RequestUserId =? and status = 1
-	union
-	select RequestUserId id from relationship
-	where TargetUserId =? and status = 1) l
-	inner join
-	(select TargetUserId id from relationship
-	where RequestUserId =? and status = 1
-	union
-	select RequestUserId id from relationship
-	where TargetUserId =? and status = 1) r
-	on l.id = r.id) c
-	on u.id = c.id;
+	select l.owner, r.owner, count(*)
+	from ` + friends + ` l
+	inner join ` + friends + ` r
+	on l.id = r.id and l.owner <> r.owner
+	inner join user u
+	on u.id = l.id
+	where u.Status = ? and not exists (
+	select 1 from relationship b
+	where b.RequestUserId = l.id and b.TargetUserId = l.owner and b.status = ?)
+	group by l.owner, r.owner
 	`
 
-	rows, err := repo.DB.Query(query, id, id, withId, withId)
+	args := append(append(friendArgs, friendArgs...), models.UserActive, models.RelationshipBlocked)
+
+	rows, err := repo.DB.Query(query, args...)
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
+	defer rows.Close()
 
-	var emails []string
 	for rows.Next() {
-		var email string
-		rows.Scan(&email)
-		emails = append(emails, email)
+		var id, withId int64
+		var count int
+		if err := rows.Scan(&id, &withId, &count); err != nil {
+			return nil, err
+		}
+		if counts[id] == nil {
+			counts[id] = map[int64]int{}
+		}
+		counts[id][withId] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return emails
+	return counts, nil
 }
 
-func (repo RelationshipRepository) CreateRelationship(relationship *models.Relationship) int64 {
+// friendOwnersQuery returns a derived table of the friends of the users, with the owner and the id of every friend,
+// along with its arguments.
+func friendOwnersQuery(ids []int64) (string, []interface{}) {
+	placeholders := `(?` + strings.Repeat(",?", len(ids)-1) + `)`
 	query := `
-		INSERT INTO relationship (RequestUserId, TargetUserId, Status)
-		VALUES (?,?,?)
-	`
+	(select RequestUserId owner, TargetUserId id from relationship
+	where status = ? and RequestUserId in ` + placeholders + `
+	union
+	select TargetUserId owner, RequestUserId id from relationship
+	where status = ? and TargetUserId in ` + placeholders + `)`
 
-	rows, err := repo.DB.Prepare(query)
-	if err != nil {
-		return -1
+	args := []interface{}{models.RelationshipFriend}
+	for _, id := range ids {
+		args = append(args, id)
 	}
-
-	res, err := rows.Exec(relationship.RequestUserId, relationship.TargetUserId, relationship.Status)
-	if err != nil {
-		return -1
+	args = append(args, models.RelationshipFriend)
+	for _, id := range ids {
+		args = append(args, id)
 	}
 
-	insertedId, err := res.LastInsertId()
+	return query, args
+}
 
+func (repo RelationshipRepository) CreateRelationship(relationship *models.Relationship) (int64, error) {
+	query := `
+		INSERT INTO relationship (RequestUserId, TargetUserId, Status, CreatedAt)
+		VALUES (?,?,?,?)
+	`
+
+	res, err := repo.DB.Exec(query, relationship.RequestUserId, relationship.TargetUserId, relationship.Status, relationship.CreatedAt)
+	if isUniqueViolation(err) {
+		return 0, duplicateRelationship(relationship)
+	}
 	if err != nil {
-		return -1
+		return 0, err
 	}
 
-	return insertedId
+	return res.LastInsertId()
 }
 
-func (repo RelationshipRepository) DeleteRelationships(ids []int64) bool {
+// duplicateRelationship is the error of creating a relationship which already exists with the same status.
+func duplicateRelationship(relationship *models.Relationship) error {
+	key := fmt.Sprintf("%d-%d with status %s", relationship.RequestUserId, relationship.TargetUserId, relationship.Status)
+	return &DuplicateError{Entity: "relationship", Key: key}
+}
+
+func (repo RelationshipRepository) DeleteRelationships(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
 
 	args := make([]interface{}, len(ids))
 	for i, id := range ids {
 		args[i] = id
 	}
 	stmt := `DELETE FROM relationship WHERE id in (?` + strings.Repeat(",?", len(args)-1) + `)`
-	// rows, err := p.DB.Exec(stmt, args...)
-	rows, err := repo.DB.Prepare(stmt)
 
+	_, err := repo.DB.Exec(stmt, args...)
+
+	return err
+}
+
+// DeleteUserRelationships deletes the relationships from and to an user and returns how many there were.
+func (repo RelationshipRepository) DeleteUserRelationships(id int64) (int, error) {
+	res, err := repo.DB.Exec(`DELETE FROM relationship WHERE RequestUserId = ? OR TargetUserId = ?`, id, id)
 	if err != nil {
-		return false
+		return 0, err
 	}
 
-	rows.Exec(args...)
+	deleted, err := res.RowsAffected()
 
-	return true
+	return int(deleted), err
 }
 
-func (repo RelationshipRepository) CheckRelationshipTwoWay(requestUserId int64, targetUserId int64, status int64) []int64 {
+// GetUserRelationships returns the relationships from and to an user ordered by id.
+func (repo RelationshipRepository) GetUserRelationships(id int64) ([]models.Relationship, error) {
 	query := `
-	SELECT id
-	FROM relationship
-	where (requestuserid =? and targetuserid =? and status =?)
-	OR (targetuserid =? and requestuserid =? and status =?)
+		SELECT Id, RequestUserId, TargetUserId, Status, CreatedAt
+		FROM relationship
+		WHERE RequestUserId = ? OR TargetUserId = ?
+		ORDER BY Id
 	`
 
-	rows, err := repo.DB.Query(query, requestUserId, targetUserId, status, requestUserId, targetUserId, status)
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	var ids []int64
-	for rows.Next() {
-		var id int64
-		rows.Scan(&id)
-		ids = append(ids, id)
-	}
-
-	return ids
+	return queryRelationships(repo.DB, query, id, id)
 }
 
-func (repo RelationshipRepository) CheckRelationshipOneWay(requestUserId int64, targetUserId int64, status int64) []int64 {
-	stmt := `
-		SELECT id
+// GetRelationships returns a page of all the relationships ordered by id.
+func (repo RelationshipRepository) GetRelationships(page models.Page) ([]models.Relationship, int64, error) {
+	query := `
+		SELECT Id, RequestUserId, TargetUserId, Status, CreatedAt
 		FROM relationship
-		where requestuserid = %s and targetuserid = %s AND status = %s
+		WHERE Id > ?
+		ORDER BY Id
+		LIMIT ?
 	`
 
-	query := fmt.Sprintf(
-		stmt,
-		strconv.FormatInt(requestUserId, 10),
-		strconv.FormatInt(targetUserId, 10),
-		strconv.FormatInt(status, 10))
+	relationships, err := queryRelationships(repo.DB, query, page.After, page.Limit+1)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	fmt.Printf(query)
+	keys := make([]int64, len(relationships))
+	for i, relationship := range relationships {
+		keys[i] = relationship.ID
+	}
+	size, next := pageEnd(keys, page.Limit)
+
+	return relationships[:size], next, nil
+}
 
-	rows, _ := repo.DB.Query(query)
+// queryRelationships runs a query selecting the id, users, status and creation time of relationships.
+func queryRelationships(db DBTX, query string, args ...interface{}) ([]models.Relationship, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	var ids []int64
+	var relationships []models.Relationship
 	for rows.Next() {
-		var id int64
-		rows.Scan(&id)
-		ids = append(ids, id)
+		var relationship models.Relationship
+		if err := rows.Scan(&relationship.ID, &relationship.RequestUserId, &relationship.TargetUserId, &relationship.Status, &relationship.CreatedAt); err != nil {
+			return nil, err
+		}
+		relationships = append(relationships, relationship)
 	}
 
-	fmt.Println(len(ids))
+	return relationships, rows.Err()
+}
+
+func (repo RelationshipRepository) CheckRelationshipTwoWay(requestUserId int64, targetUserId int64, status models.RelationshipStatus) ([]int64, error) {
+	query := `
+	SELECT id
+	FROM relationship
+	where (requestuserid =? and targetuserid =? and status =?)
+	OR (targetuserid =? and requestuserid =? and status =?)
+	`
 
-	return ids
+	return queryIds(repo.DB, query, requestUserId, targetUserId, status, requestUserId, targetUserId, status)
 }
 
-func (repo RelationshipRepository) GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64) []string {
-	strSenderId := strconv.FormatInt(senderId, 10)
+func (repo RelationshipRepository) CheckRelationshipOneWay(requestUserId int64, targetUserId int64, status models.RelationshipStatus) ([]int64, error) {
+	query := `
+		SELECT id
+		FROM relationship
+		where requestuserid =? and targetuserid =? AND status =?
+	`
 
-	var stmt string
+	return queryIds(repo.DB, query, requestUserId, targetUserId, status)
+}
+
+// GetValidUsersCanReceiveUpdates returns a page of the active users receiving the posts of the sender ordered by id.
+func (repo RelationshipRepository) GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64, page models.Page) ([]string, int64, error) {
 	var query string
+	var args []interface{}
 
 	if len(mentionIds) > 0 {
-		strMentionedIds := make([]string, len(mentionIds))
+		mentionArgs := make([]interface{}, len(mentionIds))
 		for i, id := range mentionIds {
-			strMentionedIds[i] = strconv.FormatInt(id, 10)
+			mentionArgs[i] = id
 		}
+		mentionPlaceholders := `?` + strings.Repeat(",?", len(mentionArgs)-1)
 
-		stmt = `
-			select u.email from user u
+		query = `
+			select u.id, u.email from user u
 			inner join (
 			select rs.id from
 			(select TargetUserId id from relationship
-			where RequestUserId = %s and status = 1
+			where RequestUserId =? and status = ?
 			union
 			select RequestUserId id from relationship
-			where TargetUserId = %s and status in (1,2)
+			where TargetUserId =? and status in (?,?)
 			union
 			select id from user
-			where id in (%s)) rs
+			where id in (` + mentionPlaceholders + `)) rs
 			where rs.id not in (
 			select RequestUserId id
 			from relationship
-			where RequestUserId in (%s)
-			and TargetUserId = %s
-			and status = 3
+			where RequestUserId in (` + mentionPlaceholders + `)
+			and TargetUserId =?
+			and status = ?
 			)) ids on u.id = ids.id
+			where u.id > ? and u.Status = ?
+			order by u.id
+			limit ?
 		`
 
-		query = fmt.Sprintf(stmt, strSenderId, strSenderId, strings.Join(strMentionedIds, ","), strings.Join(strMentionedIds, ","), strSenderId)
+		args = append(args, senderId, models.RelationshipFriend, senderId, models.RelationshipFriend, models.RelationshipSubscribed)
+		args = append(args, mentionArgs...)
+		args = append(args, mentionArgs...)
+		args = append(args, senderId, models.RelationshipBlocked)
 	} else {
-		stmt = `
-			select u.email from user u
+		query = `
+			select u.id, u.email from user u
 			inner join (
 			select TargetUserId id from relationship
-			where RequestUserId = %s and status = 1
+			where RequestUserId =? and status = ?
 			union
 			select RequestUserId id from relationship
-			where TargetUserId = %s and status in (1,2)
+			where TargetUserId =? and status in (?,?)
 			) ids on u.id = ids.id
+			where u.id > ? and u.Status = ?
+			order by u.id
+			limit ?
 		`
-		query = fmt.Sprintf(stmt, strSenderId, strSenderId)
+
+		args = append(args, senderId, models.RelationshipFriend, senderId, models.RelationshipFriend, models.RelationshipSubscribed)
 	}
+	args = append(args, page.After, models.UserActive)
+
+	return queryEmailPage(repo.DB, query, page, args...)
+}
 
-	rows, err := repo.DB.Query(query)
+// GetIncomingFriendRequests returns a page of the active users who sent a friend request to an user, oldest request first.
+func (repo RelationshipRepository) GetIncomingFriendRequests(id int64, page models.Page) ([]string, int64, error) {
+	query := `
+		select r.id, u.email
+		from user u inner join relationship r
+		on u.id = r.RequestUserId
+		where r.TargetUserId =? and r.status = ? and u.Status = ? and r.id > ?
+		order by r.id
+		limit ?;
+	`
+
+	return queryEmailPage(repo.DB, query, page, id, models.RelationshipPending, models.UserActive, page.After)
+}
+
+// GetOutgoingFriendRequests returns a page of the active users an user sent a friend request to, oldest request first.
+func (repo RelationshipRepository) GetOutgoingFriendRequests(id int64, page models.Page) ([]string, int64, error) {
+	query := `
+		select r.id, u.email
+		from user u inner join relationship r
+		on u.id = r.TargetUserId
+		where r.RequestUserId =? and r.status = ? and u.Status = ? and r.id > ?
+		order by r.id
+		limit ?;
+	`
+
+	return queryEmailPage(repo.DB, query, page, id, models.RelationshipPending, models.UserActive, page.After)
+}
+
+// GetBlockedUsers returns a page of the users an user blocked, oldest block first.
+func (repo RelationshipRepository) GetBlockedUsers(id int64, page models.Page) ([]models.BlockedUser, int64, error) {
+	query := `
+		select r.id, u.email, r.CreatedAt
+		from user u inner join relationship r
+		on u.id = r.TargetUserId
+		where r.RequestUserId =? and r.status = ? and r.id > ?
+		order by r.id
+		limit ?;
+	`
+
+	rows, err := repo.DB.Query(query, id, models.RelationshipBlocked, page.After, page.Limit+1)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var keys []int64
+	var blockedUsers []models.BlockedUser
+	for rows.Next() {
+		var key int64
+		var blockedUser models.BlockedUser
+		if err := rows.Scan(&key, &blockedUser.Email, &blockedUser.BlockedAt); err != nil {
+			return nil, 0, err
+		}
+		keys = append(keys, key)
+		blockedUsers = append(blockedUsers, blockedUser)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	size, next := pageEnd(keys, page.Limit)
+
+	return blockedUsers[:size], next, nil
+}
+
+// GetFollowers returns a page of the active users subscribing to an user, oldest subscription first.
+func (repo RelationshipRepository) GetFollowers(id int64, page models.Page) ([]string, int64, error) {
+	query := `
+		select r.id, u.email
+		from user u inner join relationship r
+		on u.id = r.RequestUserId
+		where r.TargetUserId =? and r.status = ? and u.Status = ? and r.id > ?
+		order by r.id
+		limit ?;
+	`
+
+	return queryEmailPage(repo.DB, query, page, id, models.RelationshipSubscribed, models.UserActive, page.After)
+}
+
+// GetFollowing returns a page of the active users an user subscribes to, oldest subscription first.
+func (repo RelationshipRepository) GetFollowing(id int64, page models.Page) ([]string, int64, error) {
+	query := `
+		select r.id, u.email
+		from user u inner join relationship r
+		on u.id = r.TargetUserId
+		where r.RequestUserId =? and r.status = ? and u.Status = ? and r.id > ?
+		order by r.id
+		limit ?;
+	`
+
+	return queryEmailPage(repo.DB, query, page, id, models.RelationshipSubscribed, models.UserActive, page.After)
+}
+
+// CountRelationships counts the followers, the followed users and the friends of an user, the same way GetFollowers,
+// GetFollowing and GetFriendList list them.
+func (repo RelationshipRepository) CountRelationships(id int64) (models.UserSummary, error) {
+	query := `
+		select
+		(select count(*)
+		from user u inner join relationship r
+		on u.id = r.RequestUserId
+		where r.TargetUserId =? and r.status = ? and u.Status = ?),
+		(select count(*)
+		from user u inner join relationship r
+		on u.id = r.TargetUserId
+		where r.RequestUserId =? and r.status = ? and u.Status = ?),
+		(select count(*)
+		from user u inner join
+		(select TargetUserId id from relationship
+		where RequestUserId =? and status = ?
+		union
+		select RequestUserId id from relationship
+		where TargetUserId =? and status = ?) ids
+		on u.id = ids.id
+		where u.Status = ? and u.id not in (
+		select RequestUserId from relationship
+		where TargetUserId =? and status = ?));
+	`
+
+	var summary models.UserSummary
+	row := repo.DB.QueryRow(query, id, models.RelationshipSubscribed, models.UserActive, id, models.RelationshipSubscribed, models.UserActive,
+		id, models.RelationshipFriend, id, models.RelationshipFriend, models.UserActive, id, models.RelationshipBlocked)
+	if err := row.Scan(&summary.Followers, &summary.Following, &summary.Friends); err != nil {
+		return models.UserSummary{}, err
+	}
+
+	return summary, nil
+}
+
+// GetFriendSuggestions ranks the friends of friends of an user by their number of mutual friends, counting and
+// suggesting active users only. Users already connected, blocked or holding a pending request with the user in either
+// direction are left out.
+func (repo RelationshipRepository) GetFriendSuggestions(id int64, limit int) ([]models.SuggestedFriend, error) {
+	query := `
+	select u.email, count(distinct c.via) mutual
+	from user u inner join
+	(select fof.friendId id, f.id via from
+	(select TargetUserId id from relationship
+	where RequestUserId =? and status = ?
+	union
+	select RequestUserId id from relationship
+	where TargetUserId =? and status = ?) f
+	inner join
+	(select RequestUserId id, TargetUserId friendId from relationship
+	where status = ?
+	union
+	select TargetUserId id, RequestUserId friendId from relationship
+	where status = ?) fof
+	on f.id = fof.id
+	inner join user v
+	on v.id = f.id
+	where v.Status = ?) c
+	on u.id = c.id
+	where u.id <> ? and u.Status = ?
+	and u.id not in (
+	select TargetUserId id from relationship
+	where RequestUserId =? and status in (?,?,?)
+	union
+	select RequestUserId id from relationship
+	where TargetUserId =? and status in (?,?,?))
+	group by u.id, u.email
+	order by mutual desc, u.id
+	limit ?
+	`
+
+	rows, err := repo.DB.Query(query, id, models.RelationshipFriend, id, models.RelationshipFriend, models.RelationshipFriend, models.RelationshipFriend, models.UserActive, id, models.UserActive, id, models.RelationshipFriend, models.RelationshipBlocked, models.RelationshipPending, id, models.RelationshipFriend, models.RelationshipBlocked, models.RelationshipPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var suggestions []models.SuggestedFriend
+	for rows.Next() {
+		var suggestion models.SuggestedFriend
+		if err := rows.Scan(&suggestion.Email, &suggestion.MutualFriends); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	return suggestions, rows.Err()
+}
+
+// GetFriendIds returns the friends of every given user in a single query, keyed by user id.
+// A friendship between two users is left out when one of them blocks the other or isn't active.
+func (repo RelationshipRepository) GetFriendIds(ids []int64) (map[int64][]int64, error) {
+	friendIds := make(map[int64][]int64, len(ids))
+	if len(ids) == 0 {
+		return friendIds, nil
+	}
+
+	wanted := make(map[int64]bool, len(ids))
+	idArgs := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+		idArgs = append(idArgs, id)
+	}
+	args := []interface{}{models.RelationshipFriend, models.UserActive, models.UserActive}
+	args = append(args, idArgs...)
+	args = append(args, idArgs...)
+	args = append(args, models.RelationshipBlocked)
+
+	placeholders := `(?` + strings.Repeat(",?", len(ids)-1) + `)`
+	query := `
+	select r.RequestUserId, r.TargetUserId from relationship r
+	inner join user ru on ru.id = r.RequestUserId
+	inner join user tu on tu.id = r.TargetUserId
+	where r.status = ? and ru.Status = ? and tu.Status = ?
+	and (r.RequestUserId in ` + placeholders + ` or r.TargetUserId in ` + placeholders + `)
+	and not exists (
+	select 1 from relationship b
+	where b.status = ?
+	and ((b.RequestUserId = r.RequestUserId and b.TargetUserId = r.TargetUserId)
+	or (b.RequestUserId = r.TargetUserId and b.TargetUserId = r.RequestUserId)))
+	`
+
+	rows, err := repo.DB.Query(query, args...)
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
+	defer rows.Close()
 
-	var emails []string
+	seen := map[[2]int64]bool{}
+	add := func(id int64, friendId int64) {
+		if wanted[id] && !seen[[2]int64{id, friendId}] {
+			seen[[2]int64{id, friendId}] = true
+			friendIds[id] = append(friendIds[id], friendId)
+		}
+	}
 	for rows.Next() {
-		var email string
-		rows.Scan(&email)
-		emails = append(emails, email)
+		var requestUserId, targetUserId int64
+		if err := rows.Scan(&requestUserId, &targetUserId); err != nil {
+			return nil, err
+		}
+		add(requestUserId, targetUserId)
+		add(targetUserId, requestUserId)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, ids := range friendIds {
+		sortIds(ids)
 	}
 
-	return emails
+	return friendIds, nil
 }
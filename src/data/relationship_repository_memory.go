@@ -0,0 +1,390 @@
+package data
+
+import (
+	"friendMgmt/models"
+	"sort"
+)
+
+// RelationshipRepositoryMemory implements IRelationshipRepository on top of a MemoryStore,
+// following the same rules as the SQL queries of RelationshipRepository.
+type RelationshipRepositoryMemory struct {
+	Store *MemoryStore
+}
+
+func (repo RelationshipRepositoryMemory) GetFriendList(id int64, page models.Page) ([]string, int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	emails, next := repo.Store.emailPage(repo.Store.visibleFriendIds(id), page)
+
+	return emails, next, nil
+}
+
+func (repo RelationshipRepositoryMemory) GetCommonFriendList(ids []int64, page models.Page) ([]string, int64, error) {
+	if len(ids) == 0 {
+		return nil, 0, nil
+	}
+
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	commonIds := repo.Store.visibleFriendIds(ids[0])
+	for _, withId := range ids[1:] {
+		withFriendIds := repo.Store.friendIds(withId)
+		for friendId := range commonIds {
+			if !withFriendIds[friendId] {
+				delete(commonIds, friendId)
+			}
+		}
+	}
+
+	emails, next := repo.Store.emailPage(commonIds, page)
+
+	return emails, next, nil
+}
+
+func (repo RelationshipRepositoryMemory) CountCommonFriends(ids []int64) (map[int64]map[int64]int, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	counts := make(map[int64]map[int64]int, len(ids))
+	for _, id := range ids {
+		visibleFriendIds := repo.Store.visibleFriendIds(id)
+		for _, withId := range ids {
+			if withId == id {
+				continue
+			}
+
+			count := 0
+			for friendId := range repo.Store.friendIds(withId) {
+				if visibleFriendIds[friendId] {
+					count++
+				}
+			}
+			if count == 0 {
+				continue
+			}
+
+			if counts[id] == nil {
+				counts[id] = map[int64]int{}
+			}
+			counts[id][withId] = count
+		}
+	}
+
+	return counts, nil
+}
+
+func (repo RelationshipRepositoryMemory) CreateRelationship(relationship *models.Relationship) (int64, error) {
+	repo.Store.mu.Lock()
+	defer repo.Store.mu.Unlock()
+
+	// Same check as the SQL driver does when storing the status.
+	if _, err := relationship.Status.Value(); err != nil {
+		return 0, err
+	}
+	for _, id := range []int64{relationship.RequestUserId, relationship.TargetUserId} {
+		if _, ok := repo.Store.users[id]; !ok {
+			return 0, missingUser(id)
+		}
+	}
+	// Same check as the unique indexes do, a friendship being the same in both directions.
+	if repo.Store.hasRelationship(relationship.RequestUserId, relationship.TargetUserId, relationship.Status) ||
+		relationship.Status == models.RelationshipFriend && repo.Store.hasRelationship(relationship.TargetUserId, relationship.RequestUserId, relationship.Status) {
+		return 0, duplicateRelationship(relationship)
+	}
+
+	repo.Store.lastRelationshipId++
+
+	inserted := *relationship
+	inserted.ID = repo.Store.lastRelationshipId
+	repo.Store.addRelationship(inserted)
+
+	return inserted.ID, nil
+}
+
+func (repo RelationshipRepositoryMemory) DeleteRelationships(ids []int64) error {
+	repo.Store.mu.Lock()
+	defer repo.Store.mu.Unlock()
+
+	for _, id := range ids {
+		repo.Store.removeRelationship(id)
+	}
+
+	return nil
+}
+
+func (repo RelationshipRepositoryMemory) DeleteUserRelationships(id int64) (int, error) {
+	repo.Store.mu.Lock()
+	defer repo.Store.mu.Unlock()
+
+	var ids []int64
+	for _, adjacency := range []map[int64]map[int64][]int64{repo.Store.outgoing, repo.Store.incoming} {
+		for _, edgeIds := range adjacency[id] {
+			ids = append(ids, edgeIds...)
+		}
+	}
+	for _, relationshipId := range ids {
+		repo.Store.removeRelationship(relationshipId)
+	}
+
+	return len(ids), nil
+}
+
+func (repo RelationshipRepositoryMemory) GetUserRelationships(id int64) ([]models.Relationship, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	var ids []int64
+	for _, adjacency := range []map[int64]map[int64][]int64{repo.Store.outgoing, repo.Store.incoming} {
+		for _, edgeIds := range adjacency[id] {
+			ids = append(ids, edgeIds...)
+		}
+	}
+	sortIds(ids)
+
+	var relationships []models.Relationship
+	for _, relationshipId := range ids {
+		relationships = append(relationships, repo.Store.relationships[relationshipId])
+	}
+
+	return relationships, nil
+}
+
+func (repo RelationshipRepositoryMemory) GetRelationships(page models.Page) ([]models.Relationship, int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	ids := make([]int64, 0, len(repo.Store.relationships))
+	for id := range repo.Store.relationships {
+		ids = append(ids, id)
+	}
+	sortIds(ids)
+
+	ids, next := pageKeys(ids, page)
+
+	var relationships []models.Relationship
+	for _, id := range ids {
+		relationships = append(relationships, repo.Store.relationships[id])
+	}
+
+	return relationships, next, nil
+}
+
+func (repo RelationshipRepositoryMemory) CheckRelationshipTwoWay(requestUserId int64, targetUserId int64, status models.RelationshipStatus) ([]int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	ids := append(
+		repo.Store.relationshipIds(requestUserId, targetUserId, status),
+		repo.Store.relationshipIds(targetUserId, requestUserId, status)...)
+	sortIds(ids)
+
+	return ids, nil
+}
+
+func (repo RelationshipRepositoryMemory) CheckRelationshipOneWay(requestUserId int64, targetUserId int64, status models.RelationshipStatus) ([]int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	return repo.Store.relationshipIds(requestUserId, targetUserId, status), nil
+}
+
+func (repo RelationshipRepositoryMemory) GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64, page models.Page) ([]string, int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	recipientIds := repo.Store.targets(senderId, models.RelationshipFriend)
+	for id := range repo.Store.requestors(senderId, models.RelationshipFriend) {
+		recipientIds[id] = true
+	}
+	for id := range repo.Store.requestors(senderId, models.RelationshipSubscribed) {
+		recipientIds[id] = true
+	}
+
+	for _, id := range mentionIds {
+		recipientIds[id] = true
+	}
+
+	// Like the SQL query, only mentioned users who blocked the sender are excluded.
+	for _, id := range mentionIds {
+		if repo.Store.hasRelationship(id, senderId, models.RelationshipBlocked) {
+			delete(recipientIds, id)
+		}
+	}
+
+	emails, next := repo.Store.emailPage(repo.Store.activeIds(recipientIds), page)
+
+	return emails, next, nil
+}
+
+func (repo RelationshipRepositoryMemory) GetIncomingFriendRequests(id int64, page models.Page) ([]string, int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	emails, next := repo.relationshipEmails(repo.Store.incoming[id], models.RelationshipPending, page)
+
+	return emails, next, nil
+}
+
+func (repo RelationshipRepositoryMemory) GetOutgoingFriendRequests(id int64, page models.Page) ([]string, int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	emails, next := repo.relationshipEmails(repo.Store.outgoing[id], models.RelationshipPending, page)
+
+	return emails, next, nil
+}
+
+func (repo RelationshipRepositoryMemory) GetBlockedUsers(id int64, page models.Page) ([]models.BlockedUser, int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	ids, next := pageKeys(repo.relationshipKeys(repo.Store.outgoing[id], models.RelationshipBlocked), page)
+
+	var blockedUsers []models.BlockedUser
+	for _, relationshipId := range ids {
+		relationship := repo.Store.relationships[relationshipId]
+		blockedUsers = append(blockedUsers, models.BlockedUser{Email: repo.Store.users[relationship.TargetUserId].Email, BlockedAt: relationship.CreatedAt})
+	}
+
+	return blockedUsers, next, nil
+}
+
+func (repo RelationshipRepositoryMemory) GetFollowers(id int64, page models.Page) ([]string, int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	emails, next := repo.relationshipEmails(repo.Store.incoming[id], models.RelationshipSubscribed, page)
+
+	return emails, next, nil
+}
+
+func (repo RelationshipRepositoryMemory) GetFollowing(id int64, page models.Page) ([]string, int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	emails, next := repo.relationshipEmails(repo.Store.outgoing[id], models.RelationshipSubscribed, page)
+
+	return emails, next, nil
+}
+
+func (repo RelationshipRepositoryMemory) CountRelationships(id int64) (models.UserSummary, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	return models.UserSummary{
+		Followers: len(repo.Store.activeIds(repo.Store.requestors(id, models.RelationshipSubscribed))),
+		Following: len(repo.Store.activeIds(repo.Store.targets(id, models.RelationshipSubscribed))),
+		Friends:   len(repo.Store.visibleFriendIds(id)),
+	}, nil
+}
+
+// relationshipEmails returns a page of the emails of the active neighbours holding a relationship with the status in
+// the given edges, oldest relationship first.
+func (repo RelationshipRepositoryMemory) relationshipEmails(edges map[int64][]int64, status models.RelationshipStatus, page models.Page) ([]string, int64) {
+	neighbourIds := map[int64]int64{}
+	activeEdges := map[int64][]int64{}
+	for neighbourId, relationshipIds := range edges {
+		for _, id := range relationshipIds {
+			neighbourIds[id] = neighbourId
+		}
+		if repo.Store.isActive(neighbourId) {
+			activeEdges[neighbourId] = relationshipIds
+		}
+	}
+
+	ids, next := pageKeys(repo.relationshipKeys(activeEdges, status), page)
+
+	var emails []string
+	for _, id := range ids {
+		emails = append(emails, repo.Store.users[neighbourIds[id]].Email)
+	}
+
+	return emails, next
+}
+
+// relationshipKeys returns the ids of the relationships with the status in the given edges in ascending order,
+// leaving out the ones of unknown neighbours like an inner join would.
+func (repo RelationshipRepositoryMemory) relationshipKeys(edges map[int64][]int64, status models.RelationshipStatus) []int64 {
+	var ids []int64
+	for neighbourId, relationshipIds := range edges {
+		if _, ok := repo.Store.users[neighbourId]; !ok {
+			continue
+		}
+		for _, id := range relationshipIds {
+			if repo.Store.relationships[id].Status == status {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sortIds(ids)
+
+	return ids
+}
+
+func (repo RelationshipRepositoryMemory) GetFriendSuggestions(id int64, limit int) ([]models.SuggestedFriend, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	excludedIds := repo.Store.friendIds(id)
+	excludedIds[id] = true
+	for _, status := range []models.RelationshipStatus{models.RelationshipBlocked, models.RelationshipPending} {
+		for userId := range repo.Store.targets(id, status) {
+			excludedIds[userId] = true
+		}
+		for userId := range repo.Store.requestors(id, status) {
+			excludedIds[userId] = true
+		}
+	}
+
+	mutualFriends := map[int64]int{}
+	for friendId := range repo.Store.activeIds(repo.Store.friendIds(id)) {
+		for candidateId := range repo.Store.friendIds(friendId) {
+			if repo.Store.isActive(candidateId) && !excludedIds[candidateId] {
+				mutualFriends[candidateId]++
+			}
+		}
+	}
+
+	candidateIds := make([]int64, 0, len(mutualFriends))
+	for candidateId := range mutualFriends {
+		candidateIds = append(candidateIds, candidateId)
+	}
+	sort.Slice(candidateIds, func(i, j int) bool {
+		if mutualFriends[candidateIds[i]] != mutualFriends[candidateIds[j]] {
+			return mutualFriends[candidateIds[i]] > mutualFriends[candidateIds[j]]
+		}
+		return candidateIds[i] < candidateIds[j]
+	})
+
+	var suggestions []models.SuggestedFriend
+	for _, candidateId := range candidateIds {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestions = append(suggestions, models.SuggestedFriend{Email: repo.Store.users[candidateId].Email, MutualFriends: mutualFriends[candidateId]})
+	}
+
+	return suggestions, nil
+}
+
+func (repo RelationshipRepositoryMemory) GetFriendIds(ids []int64) (map[int64][]int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	friendIds := make(map[int64][]int64, len(ids))
+	for _, id := range ids {
+		if !repo.Store.isActive(id) {
+			continue
+		}
+		for friendId := range repo.Store.activeIds(repo.Store.friendIds(id)) {
+			if !repo.Store.hasRelationship(id, friendId, models.RelationshipBlocked) && !repo.Store.hasRelationship(friendId, id, models.RelationshipBlocked) {
+				friendIds[id] = append(friendIds[id], friendId)
+			}
+		}
+		sortIds(friendIds[id])
+	}
+
+	return friendIds, nil
+}
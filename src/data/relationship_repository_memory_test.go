@@ -0,0 +1,351 @@
+package data_test
+
+import (
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newRepositories returns both the memory repositories and the repositories of an in-memory SQLite database,
+// so every scenario checks the memory implementation against the SQL queries.
+func newRepositories(t *testing.T) map[string]data.Repositories {
+	db := newSQLiteDB(t)
+	if _, err := (data.Migrator{DB: db, Driver: data.SQLite}).Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	return map[string]data.Repositories{
+		data.Memory: data.NewMemoryRepositories(data.NewMemoryStore()),
+		data.SQLite: data.NewSQLRepositories(db),
+	}
+}
+
+// createdAt is the creation time of the users of the seed.
+var createdAt = time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+
+// seed creates users a@email.com to f@email.com (ids 1 to 6) with:
+// a-b, a-c, b-c, c-d friends, e subscribes to a, f blocks a, d sent a friend request to a.
+func seed(repositories data.Repositories) {
+	for _, email := range []string{"a@email.com", "b@email.com", "c@email.com", "d@email.com", "e@email.com", "f@email.com"} {
+		repositories.IUserRepository.Create(email, createdAt)
+	}
+
+	for _, relationship := range []models.Relationship{
+		{RequestUserId: 1, TargetUserId: 2, Status: 1},
+		{RequestUserId: 3, TargetUserId: 1, Status: 1},
+		{RequestUserId: 2, TargetUserId: 3, Status: 1},
+		{RequestUserId: 3, TargetUserId: 4, Status: 1},
+		{RequestUserId: 5, TargetUserId: 1, Status: 2},
+		{RequestUserId: 6, TargetUserId: 1, Status: 3},
+		{RequestUserId: 4, TargetUserId: 1, Status: 4},
+	} {
+		relationship := relationship
+		repositories.IRelationshipRepository.CreateRelationship(&relationship)
+	}
+}
+
+// noErr returns the value of a repository call, or its error so that the assertion fails with it.
+func noErr(value interface{}, err error) interface{} {
+	if err != nil {
+		return err
+	}
+
+	return value
+}
+
+// all is a page holding every item of the lists of the seed.
+var all = models.Page{Limit: 100}
+
+// noErrPage returns the items of a page read by a repository call, or its error so that the assertion fails with it.
+func noErrPage(value interface{}, next int64, err error) interface{} {
+	return noErr(value, err)
+}
+
+// noErrEmails returns the emails of a page of users read by a repository call, or its error so that the assertion fails with it.
+func noErrEmails(users []models.User, next int64, err error) interface{} {
+	if err != nil {
+		return err
+	}
+
+	var emails []string
+	for _, user := range users {
+		emails = append(emails, user.Email)
+	}
+
+	return emails
+}
+
+func TestMemoryGetUserRelationships(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+
+		relationships, err := repositories.IRelationshipRepository.GetUserRelationships(3)
+		assert.Nil(t, err, name)
+
+		var ids []int64
+		for _, relationship := range relationships {
+			ids = append(ids, relationship.ID)
+		}
+		assert.Equal(t, []int64{2, 3, 4}, ids, name)
+		assert.Equal(t, int64(3), relationships[0].RequestUserId, name)
+		assert.Equal(t, int64(1), relationships[0].TargetUserId, name)
+		assert.Equal(t, models.RelationshipFriend, relationships[0].Status, name)
+	}
+}
+
+func TestMemoryGetRelationships(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IRelationshipRepository
+
+		relationships, next, err := repo.GetRelationships(models.Page{Limit: 4})
+		assert.Nil(t, err, name)
+		assert.Len(t, relationships, 4, name)
+		assert.Equal(t, int64(4), next, name)
+		assert.Equal(t, models.Relationship{ID: 1, RequestUserId: 1, TargetUserId: 2, Status: models.RelationshipFriend}, relationships[0], name)
+
+		relationships, next, err = repo.GetRelationships(models.Page{After: next, Limit: 4})
+		assert.Nil(t, err, name)
+		assert.Equal(t, int64(0), next, name)
+		assert.Equal(t, []int64{5, 6, 7}, []int64{relationships[0].ID, relationships[1].ID, relationships[2].ID}, name)
+		assert.Equal(t, models.RelationshipPending, relationships[2].Status, name)
+	}
+}
+
+func TestMemoryFriendLists(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IRelationshipRepository
+
+		assert.Equal(t, []string{"b@email.com", "c@email.com"}, noErrPage(repo.GetFriendList(1, all)), name)
+		assert.Equal(t, []string{"a@email.com", "b@email.com", "d@email.com"}, noErrPage(repo.GetFriendList(3, all)), name)
+		assert.Equal(t, []string{"c@email.com"}, noErrPage(repo.GetCommonFriendList([]int64{1, 2}, all)), name)
+		assert.Equal(t, []string{"b@email.com"}, noErrPage(repo.GetCommonFriendList([]int64{1, 3}, all)), name)
+		assert.Empty(t, noErrPage(repo.GetCommonFriendList([]int64{5, 6}, all)), name)
+		assert.Equal(t, []string{"c@email.com"}, noErrPage(repo.GetCommonFriendList([]int64{1, 2, 4}, all)), name)
+		assert.Empty(t, noErrPage(repo.GetCommonFriendList([]int64{1, 2, 3}, all)), name)
+		assert.Equal(t, []string{"d@email.com"}, noErrPage(repo.GetIncomingFriendRequests(1, all)), name)
+		assert.Equal(t, []string{"a@email.com"}, noErrPage(repo.GetOutgoingFriendRequests(4, all)), name)
+	}
+}
+
+func TestMemoryCountCommonFriends(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+
+		counts, err := repositories.IRelationshipRepository.CountCommonFriends([]int64{1, 2, 3, 4, 5})
+		assert.NoError(t, err, name)
+		assert.Equal(t, map[int64]map[int64]int{
+			1: {2: 1, 3: 1, 4: 1},
+			2: {1: 1, 3: 1, 4: 1},
+			3: {1: 1, 2: 1},
+			4: {1: 1, 2: 1},
+		}, counts, name)
+	}
+}
+
+func TestMemoryFollowers(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IRelationshipRepository
+		repo.CreateRelationship(&models.Relationship{RequestUserId: 2, TargetUserId: 1, Status: models.RelationshipSubscribed})
+		repo.CreateRelationship(&models.Relationship{RequestUserId: 5, TargetUserId: 3, Status: models.RelationshipSubscribed})
+
+		assert.Equal(t, []string{"e@email.com", "b@email.com"}, noErrPage(repo.GetFollowers(1, all)), name)
+		assert.Equal(t, []string{"a@email.com", "c@email.com"}, noErrPage(repo.GetFollowing(5, all)), name)
+		assert.Equal(t, models.UserSummary{Friends: 2, Followers: 2}, noErr(repo.CountRelationships(1)), name)
+		assert.Equal(t, models.UserSummary{Following: 2}, noErr(repo.CountRelationships(5)), name)
+
+		// f blocked a, so a doesn't count f as a friend as f does.
+		repo.CreateRelationship(&models.Relationship{RequestUserId: 1, TargetUserId: 6, Status: models.RelationshipFriend})
+		assert.Equal(t, []string{"b@email.com", "c@email.com"}, noErrPage(repo.GetFriendList(1, all)), name)
+		assert.Equal(t, models.UserSummary{Friends: 2, Followers: 2}, noErr(repo.CountRelationships(1)), name)
+		assert.Equal(t, []string{"a@email.com"}, noErrPage(repo.GetFriendList(6, all)), name)
+		assert.Equal(t, models.UserSummary{Friends: 1}, noErr(repo.CountRelationships(6)), name)
+	}
+}
+
+func TestMemoryPages(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IRelationshipRepository
+
+		emails, next, err := repo.GetFriendList(3, models.Page{Limit: 2})
+		assert.Nil(t, err, name)
+		assert.Equal(t, []string{"a@email.com", "b@email.com"}, emails, name)
+		assert.Equal(t, []string{"d@email.com"}, noErrPage(repo.GetFriendList(3, models.Page{After: next, Limit: 2})), name)
+
+		// A page filled up exactly is the last one when nothing follows.
+		emails, next, err = repo.GetFriendList(1, models.Page{Limit: 2})
+		assert.Nil(t, err, name)
+		assert.Equal(t, []string{"b@email.com", "c@email.com"}, emails, name)
+		assert.Equal(t, int64(0), next, name)
+
+		repo.CreateRelationship(&models.Relationship{RequestUserId: 2, TargetUserId: 1, Status: models.RelationshipSubscribed})
+		emails, next, err = repo.GetFollowers(1, models.Page{Limit: 1})
+		assert.Nil(t, err, name)
+		assert.Equal(t, []string{"e@email.com"}, emails, name)
+		assert.Equal(t, []string{"b@email.com"}, noErrPage(repo.GetFollowers(1, models.Page{After: next, Limit: 1})), name)
+	}
+}
+
+func TestMemoryBlocks(t *testing.T) {
+	blockedAt := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IRelationshipRepository
+		repo.CreateRelationship(&models.Relationship{RequestUserId: 1, TargetUserId: 5, Status: models.RelationshipBlocked, CreatedAt: &blockedAt})
+		repo.CreateRelationship(&models.Relationship{RequestUserId: 3, TargetUserId: 1, Status: models.RelationshipBlocked, CreatedAt: &blockedAt})
+
+		blockedUsers, _, err := repo.GetBlockedUsers(1, all)
+		assert.Nil(t, err, name)
+		if assert.Len(t, blockedUsers, 1, name) {
+			assert.Equal(t, "e@email.com", blockedUsers[0].Email, name)
+			assert.True(t, blockedAt.Equal(*blockedUsers[0].BlockedAt), name)
+		}
+		assert.Equal(t, []models.BlockedUser{{Email: "a@email.com"}}, noErrPage(repo.GetBlockedUsers(6, all)), name)
+
+		assert.Equal(t, []string{"b@email.com"}, noErrPage(repo.GetFriendList(1, all)), name)
+		assert.Equal(t, []string{"a@email.com", "b@email.com", "d@email.com"}, noErrPage(repo.GetFriendList(3, all)), name)
+		assert.Empty(t, noErrPage(repo.GetCommonFriendList([]int64{1, 2}, all)), name)
+		assert.Equal(t, []string{"c@email.com"}, noErrPage(repo.GetCommonFriendList([]int64{2, 1}, all)), name)
+
+		counts, err := repo.CountCommonFriends([]int64{1, 2})
+		assert.NoError(t, err, name)
+		assert.Equal(t, map[int64]map[int64]int{2: {1: 1}}, counts, name)
+	}
+}
+
+func TestMemoryGetFriendSuggestions(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IRelationshipRepository
+
+		assert.Empty(t, noErr(repo.GetFriendSuggestions(1, 10)), name)
+		assert.Equal(t, []models.SuggestedFriend{{Email: "d@email.com", MutualFriends: 1}}, noErr(repo.GetFriendSuggestions(2, 10)), name)
+
+		repo.CreateRelationship(&models.Relationship{RequestUserId: 5, TargetUserId: 2, Status: 1})
+		repo.CreateRelationship(&models.Relationship{RequestUserId: 3, TargetUserId: 5, Status: 1})
+
+		assert.Equal(t, []models.SuggestedFriend{{Email: "a@email.com", MutualFriends: 2}, {Email: "d@email.com", MutualFriends: 1}}, noErr(repo.GetFriendSuggestions(5, 10)), name)
+		assert.Equal(t, []models.SuggestedFriend{{Email: "a@email.com", MutualFriends: 2}}, noErr(repo.GetFriendSuggestions(5, 1)), name)
+	}
+}
+
+func TestMemoryGetFriendIds(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IRelationshipRepository
+
+		assert.Equal(t, map[int64][]int64{1: {2, 3}, 4: {3}}, noErr(repo.GetFriendIds([]int64{1, 4, 5})), name)
+
+		repo.CreateRelationship(&models.Relationship{RequestUserId: 2, TargetUserId: 1, Status: 3})
+
+		assert.Equal(t, map[int64][]int64{1: {3}, 3: {1, 2, 4}}, noErr(repo.GetFriendIds([]int64{1, 3})), name)
+		assert.Equal(t, map[int64]string{1: "a@email.com", 4: "d@email.com"}, noErr(repositories.IUserRepository.GetEmails([]int64{1, 4, 9})), name)
+	}
+}
+
+func TestMemoryCheckAndDeleteRelationships(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IRelationshipRepository
+
+		assert.Equal(t, []int64{2}, noErr(repo.CheckRelationshipTwoWay(1, 3, 1)), name)
+		assert.Empty(t, noErr(repo.CheckRelationshipOneWay(1, 3, 1)), name)
+		assert.Equal(t, []int64{2}, noErr(repo.CheckRelationshipOneWay(3, 1, 1)), name)
+		assert.Equal(t, []int64{6}, noErr(repo.CheckRelationshipOneWay(6, 1, 3)), name)
+
+		assert.NoError(t, repo.DeleteRelationships([]int64{1, 2}), name)
+		assert.Empty(t, noErrPage(repo.GetFriendList(1, all)), name)
+		_, err := repo.CreateRelationship(&models.Relationship{RequestUserId: 1, TargetUserId: 99, Status: 1})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestMemoryUniqueRelationships(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IRelationshipRepository
+
+		// a and b are friends and e subscribes to a.
+		_, err := repo.CreateRelationship(&models.Relationship{RequestUserId: 5, TargetUserId: 1, Status: models.RelationshipSubscribed})
+		assert.True(t, data.IsDuplicate(err), name)
+		_, err = repo.CreateRelationship(&models.Relationship{RequestUserId: 2, TargetUserId: 1, Status: models.RelationshipFriend})
+		assert.True(t, data.IsDuplicate(err), name)
+		assert.Equal(t, []int64{1}, noErr(repo.CheckRelationshipTwoWay(1, 2, models.RelationshipFriend)), name)
+
+		_, err = repo.CreateRelationship(&models.Relationship{RequestUserId: 1, TargetUserId: 5, Status: models.RelationshipSubscribed})
+		assert.NoError(t, err, name)
+	}
+}
+
+func TestMemoryGetValidUsersCanReceiveUpdates(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IRelationshipRepository
+
+		assert.Equal(t, []string{"b@email.com", "c@email.com", "e@email.com"}, noErrPage(repo.GetValidUsersCanReceiveUpdates(1, nil, all)), name)
+		assert.Equal(t, []string{"b@email.com", "c@email.com", "d@email.com", "e@email.com"}, noErrPage(repo.GetValidUsersCanReceiveUpdates(1, []int64{4, 6}, all)), name)
+		assert.Equal(t, []string{"a@email.com", "b@email.com", "d@email.com"}, noErrPage(repo.GetValidUsersCanReceiveUpdates(3, nil, all)), name)
+	}
+}
+
+// suspend changes the status of the user owning the email to suspended.
+func suspend(t *testing.T, repositories data.Repositories, email string) {
+	user, err := repositories.IUserRepository.GetUser(email)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user.Status = models.UserSuspended
+	if err := repositories.IUserRepository.UpdateUser(user); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMemoryInactiveUsers(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IRelationshipRepository
+		repo.CreateRelationship(&models.Relationship{RequestUserId: 2, TargetUserId: 1, Status: models.RelationshipSubscribed})
+		suspend(t, repositories, "c@email.com")
+		suspend(t, repositories, "e@email.com")
+
+		assert.Equal(t, []string{"b@email.com"}, noErrPage(repo.GetFriendList(1, all)), name)
+		assert.Empty(t, noErrPage(repo.GetCommonFriendList([]int64{1, 2}, all)), name)
+		assert.Empty(t, noErr(repo.CountCommonFriends([]int64{1, 2})), name)
+		assert.Equal(t, []string{"b@email.com"}, noErrPage(repo.GetValidUsersCanReceiveUpdates(1, []int64{3}, all)), name)
+		assert.Equal(t, []string{"b@email.com"}, noErrPage(repo.GetFollowers(1, all)), name)
+		assert.Equal(t, models.UserSummary{Friends: 1, Followers: 1}, noErr(repo.CountRelationships(1)), name)
+		assert.Empty(t, noErr(repo.GetFriendSuggestions(2, 10)), name)
+		assert.Equal(t, map[int64][]int64{1: {2}}, noErr(repo.GetFriendIds([]int64{1, 3, 4})), name)
+	}
+}
+
+func TestMemoryConcurrentAccess(t *testing.T) {
+	repositories := data.NewMemoryRepositories(data.NewMemoryStore())
+	seed(repositories)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			relationship := models.Relationship{RequestUserId: 5, TargetUserId: 6, Status: 2}
+			id, _ := repositories.IRelationshipRepository.CreateRelationship(&relationship)
+			repositories.IRelationshipRepository.DeleteRelationships([]int64{id})
+		}()
+		go func() {
+			defer wg.Done()
+			repositories.IRelationshipRepository.GetValidUsersCanReceiveUpdates(6, []int64{5}, all)
+		}()
+	}
+	wg.Wait()
+
+	assert.Empty(t, noErr(repositories.IRelationshipRepository.CheckRelationshipOneWay(5, 6, 2)))
+}
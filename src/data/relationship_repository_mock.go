@@ -10,44 +10,116 @@ type RelationshipRepositoryMock struct {
 	mock.Mock
 }
 
-func (m RelationshipRepositoryMock) CreateRelationship(relationship *models.Relationship) int64 {
+func (m *RelationshipRepositoryMock) CreateRelationship(relationship *models.Relationship) (int64, error) {
 	args := m.Called(relationship)
 
-	return args.Get(0).(int64)
+	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m RelationshipRepositoryMock) DeleteRelationships(ids []int64) bool {
+func (m *RelationshipRepositoryMock) DeleteRelationships(ids []int64) error {
 	args := m.Called(ids)
 
-	return args.Get(0).(bool)
+	return args.Error(0)
 }
 
-func (m RelationshipRepositoryMock) GetFriendList(id int64) []string {
+func (m *RelationshipRepositoryMock) GetUserRelationships(id int64) ([]models.Relationship, error) {
 	args := m.Called(id)
 
-	return args.Get(0).([]string)
+	return args.Get(0).([]models.Relationship), args.Error(1)
 }
 
-func (m RelationshipRepositoryMock) GetCommonFriendList(id int64, withId int64) []string {
-	args := m.Called(id, withId)
+func (m *RelationshipRepositoryMock) GetRelationships(page models.Page) ([]models.Relationship, int64, error) {
+	args := m.Called(page)
 
-	return args.Get(0).([]string)
+	return args.Get(0).([]models.Relationship), args.Get(1).(int64), args.Error(2)
 }
 
-func (m RelationshipRepositoryMock) GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64) []string {
-	args := m.Called(senderId, mentionIds)
+func (m *RelationshipRepositoryMock) DeleteUserRelationships(id int64) (int, error) {
+	args := m.Called(id)
+
+	return args.Int(0), args.Error(1)
+}
+
+func (m *RelationshipRepositoryMock) GetFriendList(id int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(id, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipRepositoryMock) GetCommonFriendList(ids []int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(ids, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipRepositoryMock) CountCommonFriends(ids []int64) (map[int64]map[int64]int, error) {
+	args := m.Called(ids)
 
-	return args.Get(0).([]string)
+	return args.Get(0).(map[int64]map[int64]int), args.Error(1)
 }
 
-func (m RelationshipRepositoryMock) CheckRelationshipTwoWay(requestUserId int64, targetUserId int64, status int64) []int64 {
+func (m *RelationshipRepositoryMock) GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(senderId, mentionIds, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipRepositoryMock) CheckRelationshipTwoWay(requestUserId int64, targetUserId int64, status models.RelationshipStatus) ([]int64, error) {
 	args := m.Called(requestUserId, targetUserId, status)
 
-	return args.Get(0).([]int64)
+	return args.Get(0).([]int64), args.Error(1)
 }
 
-func (m RelationshipRepositoryMock) CheckRelationshipOneWay(requestUserId int64, targetUserId int64, status int64) []int64 {
+func (m *RelationshipRepositoryMock) CheckRelationshipOneWay(requestUserId int64, targetUserId int64, status models.RelationshipStatus) ([]int64, error) {
 	args := m.Called(requestUserId, targetUserId, status)
 
-	return args.Get(0).([]int64)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *RelationshipRepositoryMock) GetIncomingFriendRequests(id int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(id, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipRepositoryMock) GetOutgoingFriendRequests(id int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(id, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipRepositoryMock) GetFriendSuggestions(id int64, limit int) ([]models.SuggestedFriend, error) {
+	args := m.Called(id, limit)
+
+	return args.Get(0).([]models.SuggestedFriend), args.Error(1)
+}
+
+func (m *RelationshipRepositoryMock) GetFriendIds(ids []int64) (map[int64][]int64, error) {
+	args := m.Called(ids)
+
+	return args.Get(0).(map[int64][]int64), args.Error(1)
+}
+
+func (m *RelationshipRepositoryMock) GetFollowers(id int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(id, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipRepositoryMock) GetFollowing(id int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(id, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipRepositoryMock) CountRelationships(id int64) (models.UserSummary, error) {
+	args := m.Called(id)
+
+	return args.Get(0).(models.UserSummary), args.Error(1)
+}
+
+func (m *RelationshipRepositoryMock) GetBlockedUsers(id int64, page models.Page) ([]models.BlockedUser, int64, error) {
+	args := m.Called(id, page)
+
+	return args.Get(0).([]models.BlockedUser), args.Get(1).(int64), args.Error(2)
 }
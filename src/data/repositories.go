@@ -0,0 +1,28 @@
+package data
+
+// Repositories groups the repositories backed by the same storage, with the unit of work making them share a transaction.
+type Repositories struct {
+	IUserRepository         IUserRepository
+	IRelationshipRepository IRelationshipRepository
+	IPostRepository         IPostRepository
+	IUnitOfWork             IUnitOfWork
+}
+
+// NewSQLRepositories returns the repositories running their queries on db, which is either the database or a transaction.
+func NewSQLRepositories(db DBTX) Repositories {
+	return Repositories{
+		IUserRepository:         UserRepository{DB: db},
+		IRelationshipRepository: RelationshipRepository{DB: db},
+		IPostRepository:         PostRepository{DB: db},
+		IUnitOfWork:             SQLUnitOfWork{DB: db},
+	}
+}
+
+func NewMemoryRepositories(store *MemoryStore) Repositories {
+	return Repositories{
+		IUserRepository:         UserRepositoryMemory{Store: store},
+		IRelationshipRepository: RelationshipRepositoryMemory{Store: store},
+		IPostRepository:         PostRepositoryMemory{Store: store},
+		IUnitOfWork:             MemoryUnitOfWork{Store: store},
+	}
+}
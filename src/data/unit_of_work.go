@@ -0,0 +1,81 @@
+package data
+
+import "database/sql"
+
+// IUnitOfWork runs a function against repositories sharing a single transaction: the changes made through them
+// are committed together when the function returns nil and rolled back otherwise.
+type IUnitOfWork interface {
+	Do(fn func(repositories Repositories) error) error
+}
+
+// DBTX is the part of *sql.DB and *sql.Tx used by the SQL repositories, so they run the same inside or outside a transaction.
+type DBTX interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// SQLUnitOfWork runs the function in a database transaction, or within the current one when DB is already a transaction.
+type SQLUnitOfWork struct {
+	DB DBTX
+}
+
+func (uow SQLUnitOfWork) Do(fn func(repositories Repositories) error) error {
+	return withTx(uow.DB, func(tx DBTX) error {
+		return fn(NewSQLRepositories(tx))
+	})
+}
+
+// withTx runs fn in a new transaction of db, or directly in db when it's already a transaction.
+func withTx(db DBTX, fn func(tx DBTX) error) error {
+	sqlDB, ok := db.(*sql.DB)
+	if !ok {
+		return fn(db)
+	}
+
+	tx, err := sqlDB.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MemoryUnitOfWork runs the function against a transaction of the store while holding its lock, the changes it
+// made being reverted when the function fails.
+type MemoryUnitOfWork struct {
+	Store *MemoryStore
+}
+
+func (uow MemoryUnitOfWork) Do(fn func(repositories Repositories) error) error {
+	uow.Store.mu.Lock()
+	defer uow.Store.mu.Unlock()
+
+	tx := uow.Store.begin()
+	// Nothing is left to revert once committed, the changes are reverted if fn fails or panics.
+	defer tx.rollback()
+	repositories := NewMemoryRepositories(tx)
+	repositories.IUnitOfWork = joinedUnitOfWork{repositories: repositories}
+
+	if err := fn(repositories); err != nil {
+		return err
+	}
+
+	uow.Store.commit(tx)
+
+	return nil
+}
+
+// joinedUnitOfWork makes nested units of work part of the transaction they are started from.
+type joinedUnitOfWork struct {
+	repositories Repositories
+}
+
+func (uow joinedUnitOfWork) Do(fn func(repositories Repositories) error) error {
+	return fn(uow.repositories)
+}
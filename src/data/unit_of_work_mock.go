@@ -0,0 +1,17 @@
+package data
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+// UnitOfWorkMock runs the function right away against Repositories, without any transaction.
+type UnitOfWorkMock struct {
+	mock.Mock
+	Repositories Repositories
+}
+
+func (m *UnitOfWorkMock) Do(fn func(repositories Repositories) error) error {
+	m.Called()
+
+	return fn(m.Repositories)
+}
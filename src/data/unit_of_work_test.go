@@ -0,0 +1,124 @@
+package data_test
+
+import (
+	"errors"
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitOfWorkCommits(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+
+		err := repositories.IUnitOfWork.Do(func(tx data.Repositories) error {
+			if err := tx.IRelationshipRepository.DeleteRelationships([]int64{1}); err != nil {
+				return err
+			}
+			_, err := tx.IRelationshipRepository.CreateRelationship(&models.Relationship{RequestUserId: 1, TargetUserId: 2, Status: 3})
+
+			return err
+		})
+
+		assert.NoError(t, err, name)
+		assert.Equal(t, []string{"c@email.com"}, noErrPage(repositories.IRelationshipRepository.GetFriendList(1, all)), name)
+		assert.Len(t, noErr(repositories.IRelationshipRepository.CheckRelationshipOneWay(1, 2, 3)), 1, name)
+	}
+}
+
+func TestUnitOfWorkRollsBack(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		failure := errors.New("failure")
+
+		err := repositories.IUnitOfWork.Do(func(tx data.Repositories) error {
+			if err := tx.IRelationshipRepository.DeleteRelationships([]int64{1}); err != nil {
+				return err
+			}
+			if err := tx.IUnitOfWork.Do(func(nested data.Repositories) error {
+				_, err := nested.IRelationshipRepository.CreateRelationship(&models.Relationship{RequestUserId: 1, TargetUserId: 2, Status: 3})
+				return err
+			}); err != nil {
+				return err
+			}
+
+			return failure
+		})
+
+		assert.Equal(t, failure, err, name)
+		assert.Equal(t, []string{"b@email.com", "c@email.com"}, noErrPage(repositories.IRelationshipRepository.GetFriendList(1, all)), name)
+		assert.Empty(t, noErr(repositories.IRelationshipRepository.CheckRelationshipOneWay(1, 2, 3)), name)
+	}
+}
+
+func TestMemoryUnitOfWorkRollsBackUsersAndPosts(t *testing.T) {
+	repositories := data.NewMemoryRepositories(data.NewMemoryStore())
+	seed(repositories)
+	failure := errors.New("failure")
+
+	err := repositories.IUnitOfWork.Do(func(tx data.Repositories) error {
+		if err := tx.IUserRepository.Create("g@email.com", createdAt); err != nil {
+			return err
+		}
+		if err := tx.IUserRepository.ChangeEmail(1, "z@email.com", createdAt); err != nil {
+			return err
+		}
+		if err := tx.IUserRepository.UpdateUser(models.User{ID: 2, Status: models.UserSuspended}); err != nil {
+			return err
+		}
+		if err := tx.IRelationshipRepository.DeleteRelationships([]int64{5, 6}); err != nil {
+			return err
+		}
+		if err := tx.IUserRepository.DeleteUser(5); err != nil {
+			return err
+		}
+		if _, err := tx.IPostRepository.CreatePost(2, "from b", nil, createdAt); err != nil {
+			return err
+		}
+
+		return failure
+	})
+
+	assert.Equal(t, failure, err)
+	assert.Equal(t, int64(1), noErr(repositories.IUserRepository.CheckUserExist("a@email.com")))
+	assert.Equal(t, int64(5), noErr(repositories.IUserRepository.CheckUserExist("e@email.com")))
+	_, err = repositories.IUserRepository.CheckUserExist("z@email.com")
+	assert.True(t, data.IsNotFound(err))
+	_, err = repositories.IUserRepository.CheckUserExist("g@email.com")
+	assert.True(t, data.IsNotFound(err))
+	assert.Equal(t, models.UserActive, noErr(repositories.IUserRepository.GetUser("b@email.com")).(models.User).Status)
+	assert.Equal(t, []string{"e@email.com"}, noErrPage(repositories.IRelationshipRepository.GetFollowers(1, all)))
+	assert.Equal(t, []int64{6}, noErr(repositories.IRelationshipRepository.CheckRelationshipOneWay(6, 1, models.RelationshipBlocked)))
+	assert.Empty(t, feedTexts(repositories.IPostRepository.GetFeed(1, all)))
+
+	// The ids taken by the rolled back transaction are given again.
+	assert.NoError(t, repositories.IUserRepository.Create("h@email.com", createdAt))
+	assert.Equal(t, int64(7), noErr(repositories.IUserRepository.CheckUserExist("h@email.com")))
+}
+
+func TestMemoryUnitOfWorkRollsBackOnPanic(t *testing.T) {
+	repositories := data.NewMemoryRepositories(data.NewMemoryStore())
+	seed(repositories)
+
+	assert.Panics(t, func() {
+		repositories.IUnitOfWork.Do(func(tx data.Repositories) error {
+			tx.IRelationshipRepository.DeleteRelationships([]int64{1})
+			panic("failure")
+		})
+	})
+
+	assert.Equal(t, []string{"b@email.com", "c@email.com"}, noErrPage(repositories.IRelationshipRepository.GetFriendList(1, all)))
+}
+
+func TestCreateDuplicateRelationship(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+
+		_, err := repositories.IRelationshipRepository.CreateRelationship(&models.Relationship{RequestUserId: 5, TargetUserId: 1, Status: 2})
+
+		assert.True(t, data.IsDuplicate(err), name)
+		assert.Equal(t, []int64{5}, noErr(repositories.IRelationshipRepository.CheckRelationshipOneWay(5, 1, 2)), name)
+	}
+}
@@ -2,87 +2,262 @@ package data
 
 import (
 	"database/sql"
-	"fmt"
+	"friendMgmt/common"
+	"friendMgmt/models"
 	"strings"
-
-	_ "github.com/go-sql-driver/mysql"
+	"time"
 )
 
 type IUserRepository interface {
-	FindAll() []string
-	Create(email string) bool
-	CheckUserExist(email string) int64
-	CheckUsersExist(emails []string) []int64
+	FindPage(page models.Page) ([]models.User, int64, error)
+	SearchUsers(filter models.UserFilter, page models.Page) ([]models.User, int64, error)
+	Create(email string, createdAt time.Time) error
+	GetUser(email string) (models.User, error)
+	UpdateUser(user models.User) error
+	ChangeEmail(id int64, email string, updatedAt time.Time) error
+	DeleteUser(id int64) error
+	CheckUserExist(email string) (int64, error)
+	CheckUsersExist(emails []string) ([]int64, error)
+	GetUsers(emails []string) ([]models.User, error)
+	GetEmails(ids []int64) (map[int64]string, error)
 }
 
 type UserRepository struct {
-	DB *sql.DB
+	DB DBTX
 }
 
-func (repo UserRepository) FindAll() []string {
-	query := `SELECT email FROM user ORDER BY id;`
+// userColumns are the columns of an user, in the order scanUser reads them.
+const userColumns = `Id, Email, DisplayName, AvatarUrl, Status, CreatedAt, UpdatedAt`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
-	rows, _ := repo.DB.Query(query)
+func scanUser(row rowScanner) (models.User, error) {
+	var user models.User
+	err := row.Scan(&user.ID, &user.Email, &user.DisplayName, &user.AvatarUrl, &user.Status, &user.CreatedAt, &user.UpdatedAt)
+
+	return user, err
+}
 
-	var emails []string
+// queryUserPage runs a query selecting the user columns, ordered by the list order and ending with a limit placeholder.
+// It returns the users of the page and the id to continue from, 0 when the page is the last one.
+func queryUserPage(db DBTX, query string, page models.Page, args ...interface{}) ([]models.User, int64, error) {
+	rows, err := db.Query(query, append(args, page.Limit+1)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var keys []int64
+	var users []models.User
 	for rows.Next() {
-		var email string
-		if err := rows.Scan(&email); err != nil {
-			return nil
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
 		}
-		emails = append(emails, email)
+		keys = append(keys, user.ID)
+		users = append(users, user)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	size, next := pageEnd(keys, page.Limit)
 
-	return emails
+	return users[:size], next, nil
 }
 
-func (repo UserRepository) Create(email string) bool {
-	query := `INSERT INTO user (email) VALUES (?)`
+func (repo UserRepository) FindPage(page models.Page) ([]models.User, int64, error) {
+	query := `SELECT ` + userColumns + ` FROM user WHERE id > ? ORDER BY id LIMIT ?;`
 
-	rows, err := repo.DB.Prepare(query)
-	if err != nil {
-		return false
+	return queryUserPage(repo.DB, query, page, page.After)
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern, '!' being the escape character of the search queries.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// SearchUsers pages through the users matching filter. When sorted by email, the page continues after the email
+// AfterSort, ties being ordered by id.
+func (repo UserRepository) SearchUsers(filter models.UserFilter, page models.Page) ([]models.User, int64, error) {
+	pattern := likeEscaper.Replace(strings.ToLower(filter.Query)) + "%"
+	if filter.Contains {
+		pattern = "%" + pattern
+	}
+	args := []interface{}{pattern}
+
+	where := `lower(email) like ? escape '!'`
+	if filter.Domain != "" {
+		where += ` and lower(email) like ? escape '!'`
+		args = append(args, "%@"+likeEscaper.Replace(strings.ToLower(filter.Domain)))
 	}
 
-	rows.Exec(email)
+	var order string
+	switch filter.Sort {
+	case models.UserSortEmail:
+		where += ` and (? = 0 or email > ? or (email = ? and id > ?))`
+		order = `email, id`
+		args = append(args, page.After, page.AfterSort, page.AfterSort, page.After)
+	case models.UserSortEmailDesc:
+		where += ` and (? = 0 or email < ? or (email = ? and id < ?))`
+		order = `email desc, id desc`
+		args = append(args, page.After, page.AfterSort, page.AfterSort, page.After)
+	default:
+		where += ` and (? = 0 or id > ?)`
+		order = `id`
+		args = append(args, page.After, page.After)
+	}
+
+	query := `SELECT ` + userColumns + ` FROM user WHERE ` + where + ` ORDER BY ` + order + ` LIMIT ?;`
 
-	return true
+	return queryUserPage(repo.DB, query, page, args...)
 }
 
-func (repo UserRepository) CheckUserExist(email string) int64 {
+func (repo UserRepository) Create(email string, createdAt time.Time) error {
+	query := `INSERT INTO user (Email, Status, CreatedAt, UpdatedAt) VALUES (?, ?, ?, ?)`
+
+	_, err := repo.DB.Exec(query, common.NormalizeEmail(email), models.UserActive, createdAt, createdAt)
+	if isUniqueViolation(err) {
+		return duplicateEmail(email)
+	}
+
+	return err
+}
+
+// GetUser returns the user owning the email, or a NotFoundError.
+func (repo UserRepository) GetUser(email string) (models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM user WHERE email = ? ORDER BY id LIMIT 1;`
+
+	user, err := scanUser(repo.DB.QueryRow(query, common.NormalizeEmail(email)))
+	if err == sql.ErrNoRows {
+		return models.User{}, &NotFoundError{Entity: "user", Key: email}
+	}
+
+	return user, err
+}
+
+// UpdateUser stores the profile, status and update time of the user, its email and creation time are kept.
+func (repo UserRepository) UpdateUser(user models.User) error {
+	query := `UPDATE user SET DisplayName = ?, AvatarUrl = ?, Status = ?, UpdatedAt = ? WHERE Id = ?`
+
+	_, err := repo.DB.Exec(query, user.DisplayName, user.AvatarUrl, user.Status, user.UpdatedAt, user.ID)
+
+	return err
+}
+
+// CheckUserExist returns the id of the user owning the email, or a NotFoundError.
+func (repo UserRepository) CheckUserExist(email string) (int64, error) {
 
 	query := `SELECT id FROM user WHERE email =? limit 1;`
 
 	var id int64
-	row := repo.DB.QueryRow(query, email)
+	row := repo.DB.QueryRow(query, common.NormalizeEmail(email))
 	err := row.Scan(&id)
 
+	if err == sql.ErrNoRows {
+		return 0, &NotFoundError{Entity: "user", Key: email}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (repo UserRepository) ChangeEmail(id int64, email string, updatedAt time.Time) error {
+	_, err := repo.DB.Exec(`UPDATE user SET Email = ?, UpdatedAt = ? WHERE Id = ?`, common.NormalizeEmail(email), updatedAt, id)
+	if isUniqueViolation(err) {
+		return duplicateEmail(email)
+	}
+
+	return err
+}
+
+// duplicateEmail is the error of giving an user the email of another one.
+func duplicateEmail(email string) error {
+	return &DuplicateError{Entity: "user", Key: email}
+}
+
+// DeleteUser removes the user, whose relationships and posts must be deleted first.
+func (repo UserRepository) DeleteUser(id int64) error {
+	_, err := repo.DB.Exec(`DELETE FROM user WHERE Id = ?`, id)
+
+	return err
+}
+
+func (repo UserRepository) CheckUsersExist(emails []string) ([]int64, error) {
+	if len(emails) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(emails))
+	for i, email := range emails {
+		args[i] = common.NormalizeEmail(email)
+	}
+
+	query := `select id from user where email in (?` + strings.Repeat(",?", len(args)-1) + `)`
+
+	return queryIds(repo.DB, query, args...)
+}
+
+// GetUsers returns the users owning the emails ordered by id, the emails no user owns being skipped.
+func (repo UserRepository) GetUsers(emails []string) ([]models.User, error) {
+	if len(emails) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(emails))
+	for i, email := range emails {
+		args[i] = common.NormalizeEmail(email)
+	}
+
+	query := `SELECT ` + userColumns + ` FROM user WHERE email IN (?` + strings.Repeat(",?", len(args)-1) + `) ORDER BY id;`
+
+	rows, err := repo.DB.Query(query, args...)
 	if err != nil {
-		return -1
+		return nil, err
 	}
+	defer rows.Close()
 
-	return id
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
 }
 
-func (repo UserRepository) CheckUsersExist(emails []string) []int64 {
+func (repo UserRepository) GetEmails(ids []int64) (map[int64]string, error) {
+	emails := make(map[int64]string, len(ids))
+	if len(ids) == 0 {
+		return emails, nil
+	}
 
-	stmt := `
-		select id from user where email in ('%s')
-	`
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
 
-	query := fmt.Sprintf(stmt, strings.Join(emails, "','"))
+	query := `select id, email from user where id in (?` + strings.Repeat(",?", len(args)-1) + `)`
 
-	rows, err := repo.DB.Query(query)
+	rows, err := repo.DB.Query(query, args...)
 	if err != nil {
-		fmt.Println(err)
+		return nil, err
 	}
+	defer rows.Close()
 
-	var ids []int64
 	for rows.Next() {
 		var id int64
-		rows.Scan(&id)
-		ids = append(ids, id)
+		var email string
+		if err := rows.Scan(&id, &email); err != nil {
+			return nil, err
+		}
+		emails[id] = email
 	}
 
-	return ids
+	return emails, rows.Err()
 }
@@ -0,0 +1,200 @@
+package data
+
+import (
+	"fmt"
+	"friendMgmt/common"
+	"friendMgmt/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UserRepositoryMemory implements IUserRepository on top of a MemoryStore.
+type UserRepositoryMemory struct {
+	Store *MemoryStore
+}
+
+func (repo UserRepositoryMemory) FindPage(page models.Page) ([]models.User, int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	ids := make([]int64, 0, len(repo.Store.users))
+	for id := range repo.Store.users {
+		ids = append(ids, id)
+	}
+	sortIds(ids)
+
+	ids, next := pageKeys(ids, page)
+
+	return repo.Store.usersOf(ids), next, nil
+}
+
+func (repo UserRepositoryMemory) SearchUsers(filter models.UserFilter, page models.Page) ([]models.User, int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	query := strings.ToLower(filter.Query)
+	domain := "@" + strings.ToLower(filter.Domain)
+
+	var ids []int64
+	for id, user := range repo.Store.users {
+		email := strings.ToLower(user.Email)
+		matches := strings.HasPrefix(email, query)
+		if filter.Contains {
+			matches = strings.Contains(email, query)
+		}
+		if matches && (filter.Domain == "" || strings.HasSuffix(email, domain)) {
+			ids = append(ids, id)
+		}
+	}
+
+	before := repo.Store.userOrder(filter.Sort)
+	sort.Slice(ids, func(i, j int) bool { return before(ids[i], ids[j]) })
+
+	if page.After != 0 {
+		start := sort.Search(len(ids), func(i int) bool { return repo.Store.follows(ids[i], filter.Sort, page) })
+		ids = ids[start:]
+	}
+	if len(ids) > page.Limit+1 {
+		ids = ids[:page.Limit+1]
+	}
+
+	size, next := pageEnd(ids, page.Limit)
+
+	return repo.Store.usersOf(ids[:size]), next, nil
+}
+
+func (repo UserRepositoryMemory) Create(email string, createdAt time.Time) error {
+	repo.Store.mu.Lock()
+	defer repo.Store.mu.Unlock()
+
+	if _, ok := repo.Store.userIds[common.NormalizeEmail(email)]; ok {
+		return duplicateEmail(email)
+	}
+	email = common.NormalizeEmail(email)
+
+	repo.Store.lastUserId++
+	repo.Store.setUser(models.User{
+		ID:        repo.Store.lastUserId,
+		Email:     email,
+		Status:    models.UserActive,
+		CreatedAt: &createdAt,
+		UpdatedAt: &createdAt,
+	})
+	repo.Store.indexEmail(email, repo.Store.lastUserId)
+
+	return nil
+}
+
+func (repo UserRepositoryMemory) GetUser(email string) (models.User, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	if id, ok := repo.Store.userIds[common.NormalizeEmail(email)]; ok {
+		return repo.Store.users[id], nil
+	}
+
+	return models.User{}, &NotFoundError{Entity: "user", Key: email}
+}
+
+func (repo UserRepositoryMemory) UpdateUser(user models.User) error {
+	repo.Store.mu.Lock()
+	defer repo.Store.mu.Unlock()
+
+	stored, ok := repo.Store.users[user.ID]
+	if !ok {
+		return nil
+	}
+
+	stored.DisplayName = user.DisplayName
+	stored.AvatarUrl = user.AvatarUrl
+	stored.Status = user.Status
+	stored.UpdatedAt = user.UpdatedAt
+	repo.Store.setUser(stored)
+
+	return nil
+}
+
+func (repo UserRepositoryMemory) CheckUserExist(email string) (int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	if id, ok := repo.Store.userIds[common.NormalizeEmail(email)]; ok {
+		return id, nil
+	}
+
+	return 0, &NotFoundError{Entity: "user", Key: email}
+}
+
+func (repo UserRepositoryMemory) ChangeEmail(id int64, email string, updatedAt time.Time) error {
+	repo.Store.mu.Lock()
+	defer repo.Store.mu.Unlock()
+
+	user, ok := repo.Store.users[id]
+	if !ok {
+		return nil
+	}
+
+	if currentId, ok := repo.Store.userIds[common.NormalizeEmail(email)]; ok && currentId != id {
+		return duplicateEmail(email)
+	}
+	email = common.NormalizeEmail(email)
+	repo.Store.unindexEmail(user)
+	user.Email = email
+	user.UpdatedAt = &updatedAt
+	repo.Store.setUser(user)
+	repo.Store.indexEmail(email, id)
+
+	return nil
+}
+
+func (repo UserRepositoryMemory) DeleteUser(id int64) error {
+	repo.Store.mu.Lock()
+	defer repo.Store.mu.Unlock()
+
+	user, ok := repo.Store.users[id]
+	if !ok {
+		return nil
+	}
+	if len(repo.Store.outgoing[id]) > 0 || len(repo.Store.incoming[id]) > 0 {
+		return fmt.Errorf("user %d still has relationships", id)
+	}
+	for _, post := range repo.Store.posts {
+		if post.SenderUserId == id || containsId(post.MentionIds, id) {
+			return fmt.Errorf("user %d still has posts or mentions", id)
+		}
+	}
+
+	repo.Store.deleteUser(id)
+	repo.Store.unindexEmail(user)
+
+	return nil
+}
+
+func (repo UserRepositoryMemory) CheckUsersExist(emails []string) ([]int64, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	return repo.Store.findIds(emails), nil
+}
+
+func (repo UserRepositoryMemory) GetUsers(emails []string) ([]models.User, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	return repo.Store.usersOf(repo.Store.findIds(emails)), nil
+}
+
+func (repo UserRepositoryMemory) GetEmails(ids []int64) (map[int64]string, error) {
+	repo.Store.mu.RLock()
+	defer repo.Store.mu.RUnlock()
+
+	emails := make(map[int64]string, len(ids))
+	for _, id := range ids {
+		if user, ok := repo.Store.users[id]; ok {
+			emails[id] = user.Email
+		}
+	}
+
+	return emails, nil
+}
@@ -0,0 +1,156 @@
+package data_test
+
+import (
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryUsers(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+
+		assert.Equal(t, []string{"a@email.com", "b@email.com", "c@email.com", "d@email.com", "e@email.com", "f@email.com"}, noErrEmails(repositories.IUserRepository.FindPage(all)), name)
+		assert.Equal(t, int64(3), noErr(repositories.IUserRepository.CheckUserExist("c@email.com")), name)
+		_, err := repositories.IUserRepository.CheckUserExist("unknown@email.com")
+		assert.True(t, data.IsNotFound(err), name)
+		assert.Equal(t, []int64{2, 5}, noErr(repositories.IUserRepository.CheckUsersExist([]string{"e@email.com", "b@email.com", "unknown@email.com"})), name)
+
+		users, err := repositories.IUserRepository.GetUsers([]string{"E@email.com", "b@email.com", "e@email.com", "unknown@email.com"})
+		assert.Equal(t, []string{"b@email.com", "e@email.com"}, noErrEmails(users, 0, err), name)
+	}
+}
+
+func TestMemoryFindPage(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IUserRepository
+
+		users, next, err := repo.FindPage(models.Page{Limit: 4})
+		assert.Equal(t, []string{"a@email.com", "b@email.com", "c@email.com", "d@email.com"}, noErrEmails(users, next, err), name)
+		assert.Equal(t, int64(4), next, name)
+
+		users, next, err = repo.FindPage(models.Page{After: next, Limit: 4})
+		assert.Equal(t, []string{"e@email.com", "f@email.com"}, noErrEmails(users, next, err), name)
+		assert.Equal(t, int64(0), next, name)
+
+		// The pages go on from the key of the cursor when its user is gone.
+		repositories.IRelationshipRepository.DeleteUserRelationships(4)
+		assert.Nil(t, repo.DeleteUser(4), name)
+		assert.Equal(t, []string{"e@email.com", "f@email.com"}, noErrEmails(repo.FindPage(models.Page{After: 4, Limit: 4})), name)
+		assert.Empty(t, noErrEmails(repo.FindPage(models.Page{After: 6, Limit: 4})), name)
+	}
+}
+
+func TestMemoryUserProfile(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IUserRepository
+
+		user, err := repo.GetUser("b@email.com")
+		assert.Nil(t, err, name)
+		assert.Equal(t, int64(2), user.ID, name)
+		assert.Equal(t, models.UserActive, user.Status, name)
+		assert.True(t, createdAt.Equal(*user.CreatedAt), name)
+		_, err = repo.GetUser("unknown@email.com")
+		assert.True(t, data.IsNotFound(err), name)
+
+		updatedAt := createdAt.Add(time.Hour)
+		user.DisplayName = "Bee"
+		user.AvatarUrl = "https://example.com/b.png"
+		user.Status = models.UserSuspended
+		user.UpdatedAt = &updatedAt
+		assert.Nil(t, repo.UpdateUser(user), name)
+
+		updated, err := repo.GetUser("b@email.com")
+		assert.Nil(t, err, name)
+		assert.Equal(t, "Bee", updated.DisplayName, name)
+		assert.Equal(t, "https://example.com/b.png", updated.AvatarUrl, name)
+		assert.Equal(t, models.UserSuspended, updated.Status, name)
+		assert.True(t, createdAt.Equal(*updated.CreatedAt), name)
+		assert.True(t, updatedAt.Equal(*updated.UpdatedAt), name)
+	}
+}
+
+func TestMemoryChangeEmail(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IUserRepository
+
+		updatedAt := createdAt.Add(time.Hour)
+		assert.Nil(t, repo.ChangeEmail(2, "bee@email.com", updatedAt), name)
+
+		_, err := repo.CheckUserExist("b@email.com")
+		assert.True(t, data.IsNotFound(err), name)
+		user, err := repo.GetUser("bee@email.com")
+		assert.Nil(t, err, name)
+		assert.Equal(t, int64(2), user.ID, name)
+		assert.True(t, updatedAt.Equal(*user.UpdatedAt), name)
+		assert.Equal(t, []string{"bee@email.com", "c@email.com"}, noErrPage(repositories.IRelationshipRepository.GetFriendList(1, all)), name)
+	}
+}
+
+func TestMemoryUsersNormalizeEmails(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		repo := repositories.IUserRepository
+		repo.Create(" John@Example.com", createdAt)
+
+		assert.Equal(t, []string{"john@example.com"}, noErrEmails(repo.FindPage(all)), name)
+		assert.Equal(t, int64(1), noErr(repo.CheckUserExist("JOHN@example.com ")), name)
+		assert.Equal(t, []int64{1}, noErr(repo.CheckUsersExist([]string{"john@EXAMPLE.com"})), name)
+		user, err := repo.GetUser("John@example.COM")
+		assert.Nil(t, err, name)
+		assert.Equal(t, int64(1), user.ID, name)
+
+		assert.Nil(t, repo.ChangeEmail(1, "Johnny@Example.com", createdAt), name)
+		assert.Equal(t, int64(1), noErr(repo.CheckUserExist("johnny@example.com")), name)
+	}
+}
+
+func TestMemorySearchUsers(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IUserRepository
+		for _, email := range []string{"john@example.com", "Anna.john@Example.com", "johnny@gmail.com", "x_y@example.com", "xzy@example.com"} {
+			repo.Create(email, createdAt)
+		}
+
+		assert.Equal(t, []string{"john@example.com", "johnny@gmail.com"}, noErrEmails(repo.SearchUsers(models.UserFilter{Query: "john"}, all)), name)
+		assert.Equal(t, []string{"john@example.com", "anna.john@example.com", "johnny@gmail.com"}, noErrEmails(repo.SearchUsers(models.UserFilter{Query: "JOHN", Contains: true}, all)), name)
+		assert.Equal(t, []string{"x_y@example.com"}, noErrEmails(repo.SearchUsers(models.UserFilter{Query: "x_"}, all)), name)
+		assert.Empty(t, noErrEmails(repo.SearchUsers(models.UserFilter{Query: "%"}, all)), name)
+
+		users, next, err := repo.SearchUsers(models.UserFilter{Domain: "example.com", Sort: models.UserSortEmail}, models.Page{Limit: 2})
+		assert.Equal(t, []string{"anna.john@example.com", "john@example.com"}, noErrEmails(users, next, err), name)
+		assert.Equal(t, int64(7), next, name)
+		assert.Equal(t, []string{"x_y@example.com", "xzy@example.com"}, noErrEmails(repo.SearchUsers(models.UserFilter{Domain: "example.com", Sort: models.UserSortEmail}, models.Page{After: next, AfterSort: "john@example.com", Limit: 2})), name)
+
+		users, next, err = repo.SearchUsers(models.UserFilter{Domain: "email.com", Sort: models.UserSortEmailDesc}, models.Page{Limit: 2})
+		assert.Equal(t, []string{"f@email.com", "e@email.com"}, noErrEmails(users, next, err), name)
+		assert.Equal(t, []string{"d@email.com", "c@email.com"}, noErrEmails(repo.SearchUsers(models.UserFilter{Domain: "email.com", Sort: models.UserSortEmailDesc}, models.Page{After: next, AfterSort: "e@email.com", Limit: 2})), name)
+
+		// The page continues from the email of the cursor even when its user is gone.
+		assert.Nil(t, repo.DeleteUser(7), name)
+		assert.Equal(t, []string{"x_y@example.com", "xzy@example.com"}, noErrEmails(repo.SearchUsers(models.UserFilter{Domain: "example.com", Sort: models.UserSortEmail}, models.Page{After: 7, AfterSort: "john@example.com", Limit: 2})), name)
+	}
+}
+
+func TestMemoryUniqueEmails(t *testing.T) {
+	for name, repositories := range newRepositories(t) {
+		seed(repositories)
+		repo := repositories.IUserRepository
+
+		err := repo.Create(" B@email.com", createdAt)
+		assert.True(t, data.IsDuplicate(err), name)
+		assert.Equal(t, &data.DuplicateError{Entity: "user", Key: " B@email.com"}, err, name)
+
+		assert.True(t, data.IsDuplicate(repo.ChangeEmail(1, "C@email.com", createdAt)), name)
+		assert.Nil(t, repo.ChangeEmail(1, "A@email.com", createdAt), name)
+		assert.Equal(t, int64(1), noErr(repo.CheckUserExist("a@email.com")), name)
+		assert.Equal(t, int64(3), noErr(repo.CheckUserExist("c@email.com")), name)
+		assert.Equal(t, []string{"a@email.com", "b@email.com", "c@email.com", "d@email.com", "e@email.com", "f@email.com"}, noErrEmails(repo.FindPage(all)), name)
+	}
+}
@@ -1,31 +1,78 @@
 package data
 
-import "github.com/stretchr/testify/mock"
+import (
+	"friendMgmt/models"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
 
 type UserRepositoryMock struct {
 	mock.Mock
 }
 
-func (m UserRepositoryMock) FindAll() []string {
-	args := m.Called()
+func (m *UserRepositoryMock) FindPage(page models.Page) ([]models.User, int64, error) {
+	args := m.Called(page)
+
+	return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *UserRepositoryMock) SearchUsers(filter models.UserFilter, page models.Page) ([]models.User, int64, error) {
+	args := m.Called(filter, page)
+
+	return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *UserRepositoryMock) Create(email string, createdAt time.Time) error {
+	args := m.Called(email, createdAt)
 
-	return args.Get(0).([]string)
+	return args.Error(0)
 }
 
-func (m UserRepositoryMock) Create(email string) bool {
+func (m *UserRepositoryMock) GetUser(email string) (models.User, error) {
 	args := m.Called(email)
 
-	return args.Get(0).(bool)
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *UserRepositoryMock) UpdateUser(user models.User) error {
+	args := m.Called(user)
+
+	return args.Error(0)
 }
 
-func (m UserRepositoryMock) CheckUserExist(email string) int64 {
+func (m *UserRepositoryMock) ChangeEmail(id int64, email string, updatedAt time.Time) error {
+	args := m.Called(id, email, updatedAt)
+
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) DeleteUser(id int64) error {
+	args := m.Called(id)
+
+	return args.Error(0)
+}
+
+func (m *UserRepositoryMock) CheckUserExist(email string) (int64, error) {
 	args := m.Called(email)
 
-	return args.Get(0).(int64)
+	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m UserRepositoryMock) CheckUsersExist(emails []string) []int64 {
+func (m *UserRepositoryMock) CheckUsersExist(emails []string) ([]int64, error) {
 	args := m.Called(emails)
 
-	return args.Get(0).([]int64)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *UserRepositoryMock) GetUsers(emails []string) ([]models.User, error) {
+	args := m.Called(emails)
+
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *UserRepositoryMock) GetEmails(ids []int64) (map[int64]string, error) {
+	args := m.Called(ids)
+
+	return args.Get(0).(map[int64]string), args.Error(1)
 }
@@ -1,6 +1,6 @@
 // GENERATED BY THE COMMAND ABOVE; DO NOT EDIT
 // This file was generated by swaggo/swag at
-// 2020-04-13 11:46:35.9564197 +0700 +07 m=+0.046985001
+// 2026-10-18 05:26:34.253139757 +0000 UTC m=+0.134634574
 
 package docs
 
@@ -28,6 +28,7 @@ var doc = `{
     "paths": {
         "/friends": {
             "post": {
+                "description": "Friends who blocked the user are left out. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.",
                 "consumes": [
                     "application/json"
                 ],
@@ -37,7 +38,7 @@ var doc = `{
                 "tags": [
                     "Friend"
                 ],
-                "summary": "API to check list friends of an user",
+                "summary": "API to check list friends of an user, ordered by id",
                 "parameters": [
                     {
                         "description": "Body",
@@ -45,7 +46,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Email"
+                            "$ref": "#/definitions/models.ListRequest"
                         }
                     }
                 ],
@@ -61,11 +62,23 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/models.Failure"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
                     }
                 }
             }
         },
-        "/friends/add": {
+        "/friends/accept": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -76,7 +89,7 @@ var doc = `{
                 "tags": [
                     "Friend"
                 ],
-                "summary": "API to create a friend connection between two users",
+                "summary": "API to allow an user (requestor) to accept the friend request sent by another user (target)",
                 "parameters": [
                     {
                         "description": "Body",
@@ -84,7 +97,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.FriendCheck"
+                            "$ref": "#/definitions/models.UserAction"
                         }
                     }
                 ],
@@ -100,12 +113,25 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/models.Failure"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
                     }
                 }
             }
         },
-        "/friends/block": {
+        "/friends/add": {
             "post": {
+                "description": "The connection is only created once the target accepts the request. If the target has already requested the requestor, both requests are accepted at once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -115,7 +141,7 @@ var doc = `{
                 "tags": [
                     "Friend"
                 ],
-                "summary": "API to allow an user can block another user",
+                "summary": "API to send a friend request from the first user to the second one",
                 "parameters": [
                     {
                         "description": "Body",
@@ -123,7 +149,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserAction"
+                            "$ref": "#/definitions/models.FriendCheck"
                         }
                     }
                 ],
@@ -139,12 +165,25 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/models.Failure"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
                     }
                 }
             }
         },
-        "/friends/common-friends": {
+        "/friends/batch": {
             "post": {
+                "description": "The operations are applied in their order in a single transaction like /friends/add, /friends/subcribe and /friends/block would, and the result of each of them is reported in the same order. A failed operation is skipped, unless the batch is atomic in which case nothing is stored. A batch holds up to 500 operations.",
                 "consumes": [
                     "application/json"
                 ],
@@ -154,7 +193,7 @@ var doc = `{
                 "tags": [
                     "Friend"
                 ],
-                "summary": "API to check common friends of two users",
+                "summary": "API to apply friend requests, subscriptions and blocks in bulk",
                 "parameters": [
                     {
                         "description": "Body",
@@ -162,7 +201,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.FriendCheck"
+                            "$ref": "#/definitions/models.FriendBatchRequest"
                         }
                     }
                 ],
@@ -170,7 +209,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Success"
+                            "$ref": "#/definitions/models.FriendBatch"
                         }
                     },
                     "400": {
@@ -178,11 +217,17 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/models.Failure"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
                     }
                 }
             }
         },
-        "/friends/receive-updates": {
+        "/friends/block": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -193,7 +238,7 @@ var doc = `{
                 "tags": [
                     "Friend"
                 ],
-                "summary": "API to return list of users can receive update from an user",
+                "summary": "API to allow an user can block another user",
                 "parameters": [
                     {
                         "description": "Body",
@@ -201,7 +246,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UserPost"
+                            "$ref": "#/definitions/models.UserAction"
                         }
                     }
                 ],
@@ -217,11 +262,23 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/models.Failure"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
                     }
                 }
             }
         },
-        "/friends/subcribe": {
+        "/friends/cancel": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -232,7 +289,7 @@ var doc = `{
                 "tags": [
                     "Friend"
                 ],
-                "summary": "API to allow an user can subscribe another user",
+                "summary": "API to allow an user (requestor) to cancel the friend request sent to another user (target)",
                 "parameters": [
                     {
                         "description": "Body",
@@ -256,12 +313,25 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/models.Failure"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
                     }
                 }
             }
         },
-        "/users": {
-            "get": {
+        "/friends/common-friends": {
+            "post": {
+                "description": "Only the friends of every user are listed, the ones who blocked the first user being left out. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.",
                 "consumes": [
                     "application/json"
                 ],
@@ -269,23 +339,51 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Friend"
+                ],
+                "summary": "API to check common friends of 2 to 50 users, ordered by id",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CommonFriendRequest"
+                        }
+                    }
                 ],
-                "summary": "API to get all users in app",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.Friend"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/friends/common-friends/matrix": {
             "post": {
-                "description": "create user",
+                "description": "The count at counts[i][j] is the number of friends the users i and j have in common, the ones who blocked the user i being left out like /friends/common-friends does. The counts of an user with itself are 0.",
                 "consumes": [
                     "application/json"
                 ],
@@ -293,17 +391,17 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Friend"
                 ],
-                "summary": "API to create new user",
+                "summary": "API to count the common friends of every pair of 2 to 50 users",
                 "parameters": [
                     {
                         "description": "Body",
-                        "name": "email",
+                        "name": "model",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Email"
+                            "$ref": "#/definitions/models.CommonFriendMatrixRequest"
                         }
                     }
                 ],
@@ -311,7 +409,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Success"
+                            "$ref": "#/definitions/models.CommonFriendMatrix"
                         }
                     },
                     "400": {
@@ -319,83 +417,2404 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/models.Failure"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Email": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "example@email.com"
-                }
-            }
-        },
-        "models.Failure": {
-            "type": "object",
-            "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "error message"
-                },
-                "success": {
-                    "type": "boolean",
-                    "example": false
-                }
-            }
         },
-        "models.FriendCheck": {
-            "type": "object",
-            "properties": {
-                "friends": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        "/friends/path": {
+            "post": {
+                "description": "The path lists the users in between, from the first user to the second one, and the length is the number of friendships on it. Friendships between users blocking each other are skipped. The maxDepth defaults to 6 and can't exceed 10.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Friend"
+                ],
+                "summary": "API to find the shortest friendship path between two users",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PathRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FriendPath"
+                        }
                     },
-                    "example": [
-                        "johndoe@gmail.com",
-                        "janedoe@gmail.com"
-                    ]
-                }
-            }
-        },
-        "models.Success": {
-            "type": "object",
-            "properties": {
-                "success": {
-                    "type": "boolean",
-                    "example": true
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
                 }
             }
         },
-        "models.UserAction": {
-            "type": "object",
-            "properties": {
-                "requestor": {
-                    "type": "string",
-                    "example": "johndoe@gmail.com"
-                },
-                "target": {
-                    "type": "string",
-                    "example": "janedoe@gmail.com"
+        "/friends/receive-updates": {
+            "post": {
+                "description": "The post is stored with its mentions and shows up in the feed of its recipients. The first 20 recipients are returned, ordered by id, the next ones are listed by the recipients API of the post from the nextCursor.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Friend"
+                ],
+                "summary": "API to publish a post of an user and return list of users can receive update from it",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPost"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
                 }
             }
         },
-        "models.UserPost": {
-            "type": "object",
-            "properties": {
-                "sender": {
-                    "type": "string",
-                    "example": "janedoe@gmail.com"
-                },
-                "text": {
-                    "type": "string",
+        "/friends/reject": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Friend"
+                ],
+                "summary": "API to allow an user (requestor) to reject the friend request sent by another user (target)",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserAction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/remove": {
+            "post": {
+                "description": "Both users stop receiving updates from each other. Subscriptions dropped when they became friends are not restored, either user can subscribe again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Friend"
+                ],
+                "summary": "API to remove the friend connection between two users",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FriendCheck"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/requests/incoming": {
+            "post": {
+                "description": "The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Friend"
+                ],
+                "summary": "API to list friend requests sent to an user which are waiting for an answer, oldest first",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FriendRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/requests/outgoing": {
+            "post": {
+                "description": "The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Friend"
+                ],
+                "summary": "API to list friend requests sent by an user which are waiting for an answer, oldest first",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ListRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FriendRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/subcribe": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Friend"
+                ],
+                "summary": "API to allow an user can subscribe another user",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserAction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/suggestions": {
+            "post": {
+                "description": "Users already connected, blocked or holding a pending friend request with the user in either direction are not suggested. The limit defaults to 10 and can't exceed 100.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Friend"
+                ],
+                "summary": "API to suggest friends of friends to an user, ranked by their number of mutual friends",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SuggestionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Suggestion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/unblock": {
+            "post": {
+                "description": "Connections, subscriptions and friend requests dropped by the block are not restored. A block set by the target on the requestor is kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Friend"
+                ],
+                "summary": "API to allow an user to unblock another user",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserAction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/friends/unsubscribe": {
+            "post": {
+                "description": "Only removes an explicit subscription, friends keep receiving updates from each other until the connection is removed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Friend"
+                ],
+                "summary": "API to allow an user to stop subscribing another user",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserAction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/feed": {
+            "post": {
+                "description": "The feed holds the posts of friends, of subscribed users and the ones mentioning the user, except the posts of blocked users. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "API to return the posts an user can see, newest first",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.FeedRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Feed"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}/recipients": {
+            "get": {
+                "description": "Recipients are computed from the current relationships of the sender. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Post"
+                ],
+                "summary": "API to list the users receiving a post by page, ordered by id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Post id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Recipent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/relationships/status": {
+            "post": {
+                "description": "Each flag is read from the requestor's side: subscribed means the requestor subscribes to the target and subscribedBy the other way round, the same goes for blocked and blockedBy. requestSent and requestReceived tell a pending friend request in either direction. outgoing and incoming list the same relationships by their status, a friend connection being listed on both sides.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Relationship"
+                ],
+                "summary": "API to tell what the target is to the requestor",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserAction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RelationshipView"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "The limit defaults to 20 and can't exceed 100. The next page is read by passing the nextCursor of the page as cursor, there is none on the last page.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to list the users in app by page, in the order they signed up",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "create user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to create new user",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Email"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Success"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/export": {
+            "post": {
+                "description": "The users come first, then the relationships in the order they were created, so importing the export rebuilds the same graph. It's a POST since the GET routes under /users take an email.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to export every user and relationship as CSV or NDJSON",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Format of the export, ndjson by default",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Records",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/import": {
+            "post": {
+                "description": "Every record is an user, created with its profile unless its email is in use, or a relationship from the user to the target with the status friend, subscribed, blocked or pending, applied like the relationship operations. The records are applied in their order in a single transaction, the invalid and conflicting ones are skipped and the existing ones are kept so importing a file again changes nothing. A dry run reports the same results without storing anything. CSV files start with a header naming their columns among type, email, displayName, avatarUrl, status, target and createdAt, the RFC 3339 time a relationship was created at. The body must not exceed 16 MB.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to import users and their relationships from CSV or NDJSON",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "ndjson"
+                        ],
+                        "type": "string",
+                        "description": "Format of the body, ndjson by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report the results without storing anything",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Records",
+                        "name": "records",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/merge": {
+            "post": {
+                "description": "The relationships of both users are unioned on the target, a block prevailing over a friend connection, a subscription or a request with the same user, and mutual requests becoming a friend connection. The relationships between both users are dropped, the posts and mentions of the source are moved to the target and the source is deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API for the administrators to merge an user into another one",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserMergeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserMerge"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/search": {
+            "post": {
+                "description": "The query matches the start of the emails, or any part of them when match is contains, and the domain keeps the users of a domain, both ignoring the case. The users are sorted by id unless sort is email or -email. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor with the same search.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to search the users by email",
+                "parameters": [
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSearch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{email}": {
+            "get": {
+                "description": "Followers are the users subscribing to the user, following the users the user subscribes to.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to read an user with the counts of its friends, followers and followed users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSummary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "The relationships of the user in both directions, its posts and the mentions of the user or in its posts are deleted with it, the response counts them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to erase an user with all its data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserDeletion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Only the fields of the body are changed. The avatar url must be an http or https url, or empty to remove it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to change the profile or the status of an user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{email}/blocked": {
+            "get": {
+                "description": "The blockedAt time is null for the blocks set before it was recorded. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to list the users blocked by an user, oldest block first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BlockList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{email}/email": {
+            "put": {
+                "description": "The new email must be valid and not used by another user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to change the email of an user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Body",
+                        "name": "model",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Email"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{email}/followers": {
+            "get": {
+                "description": "The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor. The total counts all the followers.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to list the users subscribing to an user, oldest subscription first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{email}/following": {
+            "get": {
+                "description": "The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor. The total counts all the followed users.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "API to list the users an user subscribes to, oldest subscription first",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Failure"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.BlockList": {
+            "type": "object",
+            "properties": {
+                "blocked": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BlockedUser"
+                    }
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "MTI"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.BlockedUser": {
+            "type": "object",
+            "properties": {
+                "blockedAt": {
+                    "type": "string",
+                    "example": "2020-05-01T10:00:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "janedoe@gmail.com"
+                }
+            }
+        },
+        "models.CommonFriendMatrix": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "johndoe@gmail.com",
+                        "janedoe@gmail.com"
+                    ]
+                }
+            }
+        },
+        "models.CommonFriendMatrixRequest": {
+            "type": "object",
+            "properties": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "johndoe@gmail.com",
+                        "janedoe@gmail.com",
+                        "jacksmith@gmail.com"
+                    ]
+                }
+            }
+        },
+        "models.CommonFriendRequest": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "string"
+                },
+                "friends": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "johndoe@gmail.com",
+                        "janedoe@gmail.com"
+                    ]
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                }
+            }
+        },
+        "models.Email": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "example@email.com"
+                }
+            }
+        },
+        "models.Failure": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "enum": [
+                        "VALIDATION_FAILED",
+                        "USER_NOT_FOUND",
+                        "USER_INACTIVE",
+                        "POST_NOT_FOUND",
+                        "EMAIL_IN_USE",
+                        "ALREADY_CONNECTED",
+                        "NOT_CONNECTED",
+                        "ALREADY_SUBSCRIBED",
+                        "NOT_SUBSCRIBED",
+                        "BLOCKED",
+                        "NOT_BLOCKED",
+                        "REQUEST_PENDING",
+                        "REQUEST_NOT_FOUND",
+                        "SERVICE_UNAVAILABLE"
+                    ],
+                    "example": "VALIDATION_FAILED"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "error message"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.Feed": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "MTI"
+                },
+                "posts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Post"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.FeedRequest": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                }
+            }
+        },
+        "models.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "friends[1]"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be a valid email"
+                }
+            }
+        },
+        "models.Friend": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "friends": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "johndoe@gmail.com",
+                        "janedoe@gmail.com"
+                    ]
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "MTI"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.FriendBatch": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FriendOperationResult"
+                    }
+                },
+                "rolledBack": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 98
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.FriendBatchRequest": {
+            "type": "object",
+            "properties": {
+                "atomic": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "operations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FriendOperation"
+                    }
+                }
+            }
+        },
+        "models.FriendCheck": {
+            "type": "object",
+            "properties": {
+                "friends": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "johndoe@gmail.com",
+                        "janedoe@gmail.com"
+                    ]
+                }
+            }
+        },
+        "models.FriendOperation": {
+            "type": "object",
+            "properties": {
+                "requestor": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "janedoe@gmail.com"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "friend",
+                        "subscribe",
+                        "block"
+                    ],
+                    "example": "friend"
+                }
+            }
+        },
+        "models.FriendOperationResult": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "BLOCKED"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "blocked status is existed"
+                },
+                "outcome": {
+                    "type": "string",
+                    "example": "FRIEND_REQUEST_SENT"
+                },
+                "requestor": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "target": {
+                    "type": "string",
+                    "example": "janedoe@gmail.com"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "friend",
+                        "subscribe",
+                        "block"
+                    ],
+                    "example": "friend"
+                }
+            }
+        },
+        "models.FriendPath": {
+            "type": "object",
+            "properties": {
+                "connected": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "length": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "path": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "kate@gmail.com"
+                    ]
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.FriendRequest": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "MTI"
+                },
+                "requests": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "johndoe@gmail.com",
+                        "janedoe@gmail.com"
+                    ]
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.ListRequest": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                }
+            }
+        },
+        "models.PathRequest": {
+            "type": "object",
+            "properties": {
+                "friends": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "johndoe@gmail.com",
+                        "janedoe@gmail.com"
+                    ]
+                },
+                "maxDepth": {
+                    "type": "integer",
+                    "example": 6
+                }
+            }
+        },
+        "models.Post": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2020-04-13T11:46:35Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "mentions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "johndoe@gmail.com"
+                    ]
+                },
+                "sender": {
+                    "type": "string",
+                    "example": "janedoe@gmail.com"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "hello johndoe@gmail.com"
+                }
+            }
+        },
+        "models.Recipent": {
+            "type": "object",
+            "properties": {
+                "nextCursor": {
+                    "type": "string",
+                    "example": "MTI"
+                },
+                "postId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "recipents": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "johndoe@gmail.com",
+                        "janedoe@gmail.com"
+                    ]
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.RelationshipView": {
+            "type": "object",
+            "properties": {
+                "blocked": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "blockedBy": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "friends": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "incoming": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "friend",
+                            "subscribed",
+                            "blocked",
+                            "pending"
+                        ]
+                    },
+                    "example": [
+                        "subscribed"
+                    ]
+                },
+                "outgoing": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "friend",
+                            "subscribed",
+                            "blocked",
+                            "pending"
+                        ]
+                    },
+                    "example": [
+                        "pending"
+                    ]
+                },
+                "requestReceived": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "requestSent": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "subscribed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "subscribedBy": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.Success": {
+            "type": "object",
+            "properties": {
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.SuggestedFriend": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "mutualFriends": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "models.Suggestion": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SuggestedFriend"
+                    }
+                }
+            }
+        },
+        "models.SuggestionRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string",
+                    "example": "https://example.com/johndoe.png"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended",
+                        "deleted"
+                    ],
+                    "example": "active"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserAction": {
+            "type": "object",
+            "properties": {
+                "requestor": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "janedoe@gmail.com"
+                }
+            }
+        },
+        "models.UserDeletion": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "mentions": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "posts": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "relationships": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.UserImport": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "dryRun": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "existing": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "invalid": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserImportRow"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.UserImportRow": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "BLOCKED"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "blocked status is existed"
+                },
+                "result": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "existing",
+                        "invalid",
+                        "conflict"
+                    ],
+                    "example": "conflict"
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "models.UserList": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "MTI"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "johndoe@gmail.com",
+                        "janedoe@gmail.com"
+                    ]
+                }
+            }
+        },
+        "models.UserMerge": {
+            "type": "object",
+            "properties": {
+                "dropped": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "mentions": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "posts": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "relationships": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "source": {
+                    "type": "string",
+                    "example": "johndoe.old@gmail.com"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "target": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                }
+            }
+        },
+        "models.UserMergeRequest": {
+            "type": "object",
+            "properties": {
+                "source": {
+                    "type": "string",
+                    "example": "johndoe.old@gmail.com"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                }
+            }
+        },
+        "models.UserPage": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "nextCursor": {
+                    "type": "string",
+                    "example": "MTI"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
+                }
+            }
+        },
+        "models.UserPatch": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string",
+                    "example": "https://example.com/johndoe.png"
+                },
+                "displayName": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended",
+                        "deleted"
+                    ],
+                    "example": "suspended"
+                }
+            }
+        },
+        "models.UserPost": {
+            "type": "object",
+            "properties": {
+                "sender": {
+                    "type": "string",
+                    "example": "janedoe@gmail.com"
+                },
+                "text": {
+                    "type": "string",
                     "example": "hello johndoe@gmail.com"
                 }
             }
+        },
+        "models.UserProfile": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string",
+                    "example": "https://example.com/johndoe.png"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended",
+                        "deleted"
+                    ],
+                    "example": "active"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserSearch": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string",
+                    "example": "example.com"
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "match": {
+                    "type": "string",
+                    "enum": [
+                        "prefix",
+                        "contains"
+                    ],
+                    "example": "prefix"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "john"
+                },
+                "sort": {
+                    "type": "string",
+                    "enum": [
+                        "id",
+                        "email",
+                        "-email"
+                    ],
+                    "example": "email"
+                }
+            }
+        },
+        "models.UserSummary": {
+            "type": "object",
+            "properties": {
+                "avatarUrl": {
+                    "type": "string",
+                    "example": "https://example.com/johndoe.png"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "johndoe@gmail.com"
+                },
+                "followers": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "following": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "friends": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "suspended",
+                        "deleted"
+                    ],
+                    "example": "active"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
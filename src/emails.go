@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"friendMgmt/common"
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"strings"
+	"time"
+)
+
+// emails runs the "emails report|normalize" command on the users stored before their emails were normalized.
+// The report lists the groups of users sharing a canonical email, which have to be resolved by hand, and the emails to rewrite
+// in their canonical form; normalize rewrites the emails of the users without duplicate.
+func emails(repositories data.Repositories, action string) error {
+	if action != "report" && action != "normalize" {
+		return fmt.Errorf("unknown emails action %q, expected report or normalize", action)
+	}
+
+	users, err := allUsers(repositories.IUserRepository)
+	if err != nil {
+		return err
+	}
+
+	var canonicalEmails []string
+	groups := map[string][]models.User{}
+	for _, user := range users {
+		canonicalEmail := common.NormalizeEmail(user.Email)
+		if _, ok := groups[canonicalEmail]; !ok {
+			canonicalEmails = append(canonicalEmails, canonicalEmail)
+		}
+		groups[canonicalEmail] = append(groups[canonicalEmail], user)
+	}
+
+	var duplicates int
+	var renames []models.User
+	for _, canonicalEmail := range canonicalEmails {
+		group := groups[canonicalEmail]
+		if len(group) > 1 {
+			duplicates++
+			owners := make([]string, len(group))
+			for i, user := range group {
+				owners[i] = fmt.Sprintf("%d %s", user.ID, user.Email)
+			}
+			fmt.Printf("duplicate\t%s\t%s\n", canonicalEmail, strings.Join(owners, ", "))
+			continue
+		}
+		if group[0].Email != canonicalEmail {
+			renames = append(renames, group[0])
+		}
+	}
+
+	if action == "report" {
+		for _, user := range renames {
+			fmt.Printf("normalize\t%d\t%s\t%s\n", user.ID, user.Email, common.NormalizeEmail(user.Email))
+		}
+		fmt.Printf("%d duplicated emails, %d emails to normalize\n", duplicates, len(renames))
+		return nil
+	}
+
+	now := time.Now().UTC()
+	err = repositories.IUnitOfWork.Do(func(tx data.Repositories) error {
+		for _, user := range renames {
+			if err := tx.IUserRepository.ChangeEmail(user.ID, common.NormalizeEmail(user.Email), now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, user := range renames {
+		fmt.Printf("normalized\t%d\t%s\t%s\n", user.ID, user.Email, common.NormalizeEmail(user.Email))
+	}
+	fmt.Printf("%d emails normalized, %d duplicated emails left to resolve\n", len(renames), duplicates)
+
+	return nil
+}
+
+// allUsers reads every user ordered by id.
+func allUsers(repo data.IUserRepository) ([]models.User, error) {
+	var users []models.User
+	page := models.Page{Limit: 100}
+	for {
+		pageUsers, next, err := repo.FindPage(page)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, pageUsers...)
+		if next == 0 {
+			return users, nil
+		}
+		page.After = next
+	}
+}
@@ -1,8 +1,17 @@
 package endpoints
 
 import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"friendMgmt/common"
+	"friendMgmt/data"
 	"friendMgmt/models"
+	"friendMgmt/services"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,9 +21,300 @@ func responseOk(c *gin.Context, body interface{}) {
 	return
 }
 
-func responseError(c *gin.Context, code int, message string) {
+func responseError(c *gin.Context, status int, code string, message string) {
 	var failure models.Failure
 	failure.Success = false
+	failure.Code = code
 	failure.Message = message
-	c.JSON(code, failure)
+	c.JSON(status, failure)
 }
+
+func responseValidationError(c *gin.Context, details ...models.FieldError) {
+	var failure models.Failure
+	failure.Success = false
+	failure.Code = models.CodeValidationFailed
+	failure.Message = "Invalid request: incorrect info"
+	failure.Details = details
+	c.JSON(http.StatusBadRequest, failure)
+}
+
+func responseUserNotFound(c *gin.Context, email string) {
+	responseError(c, http.StatusNotFound, models.CodeUserNotFound, fmt.Sprintf("Invalid request: User name %s is not found", email))
+}
+
+// responseUsersNotFound answers 404 for the emails no user owns, with the details of the fields holding them.
+func responseUsersNotFound(c *gin.Context, emails []string, details []models.FieldError) {
+	var failure models.Failure
+	failure.Success = false
+	failure.Code = models.CodeUserNotFound
+	failure.Message = fmt.Sprintf("Invalid request: User name %s is not found", emails[0])
+	if len(emails) > 1 {
+		failure.Message = fmt.Sprintf("Invalid request: User names %s are not found", strings.Join(emails, ", "))
+	}
+	failure.Details = details
+	c.JSON(http.StatusNotFound, failure)
+}
+
+func responseEmailInUse(c *gin.Context) {
+	responseError(c, http.StatusBadRequest, models.CodeEmailInUse, "Invalid request: the email is already in use")
+}
+
+// responseStorageError answers 404 when err tells a record is missing, 400 when an email is taken and 503 when the
+// storage failed.
+func responseStorageError(c *gin.Context, err error) {
+	var notFound *data.NotFoundError
+	if errors.As(err, &notFound) && notFound.Entity == "user" {
+		responseUserNotFound(c, notFound.Key)
+		return
+	}
+	if errors.As(err, &notFound) && notFound.Entity == "post" {
+		responseError(c, http.StatusNotFound, models.CodePostNotFound, fmt.Sprintf("Invalid request: Post %s is not found", notFound.Key))
+		return
+	}
+	if data.IsNotFound(err) {
+		responseError(c, http.StatusNotFound, models.CodeUserNotFound, "Invalid request: "+err.Error())
+		return
+	}
+	var duplicate *data.DuplicateError
+	if errors.As(err, &duplicate) && duplicate.Entity == "user" {
+		responseEmailInUse(c)
+		return
+	}
+
+	log.Printf("%s %s: %v", c.Request.Method, c.FullPath(), err)
+	responseError(c, http.StatusServiceUnavailable, models.CodeServiceUnavailable, "Oops! There is an error, please try again.")
+}
+
+// responseRelationshipError answers 400 when err tells the relationships between the users don't allow the change,
+// and like responseStorageError otherwise.
+func responseRelationshipError(c *gin.Context, err error) {
+	var relationshipErr *services.RelationshipError
+	if errors.As(err, &relationshipErr) {
+		responseError(c, http.StatusBadRequest, relationshipErr.Code, "Invalid request: "+relationshipErr.Message)
+		return
+	}
+
+	responseStorageError(c, err)
+}
+
+// findUserIds resolves the ids of the users owning the emails of the field in the same order with a single lookup,
+// responding with an error if it can't. Every email no user owns is reported.
+func findUserIds(c *gin.Context, userService services.IUserService, emails []string, field string) ([]int64, bool) {
+	users, err := userService.GetUsers(emails)
+	if err != nil {
+		responseStorageError(c, err)
+		return nil, false
+	}
+
+	ids := make(map[string]int64, len(users))
+	for _, user := range users {
+		ids[common.NormalizeEmail(user.Email)] = user.ID
+	}
+
+	userIds := make([]int64, 0, len(emails))
+	var missingEmails []string
+	var details []models.FieldError
+	for i, email := range emails {
+		userId, ok := ids[common.NormalizeEmail(email)]
+		if !ok {
+			missingEmails = append(missingEmails, email)
+			details = append(details, models.FieldError{Field: fmt.Sprintf("%s[%d]", field, i), Message: "must be an existing user"})
+		}
+		userIds = append(userIds, userId)
+	}
+	if len(missingEmails) > 0 {
+		responseUsersNotFound(c, missingEmails, details)
+		return nil, false
+	}
+
+	return userIds, true
+}
+
+// findUserId resolves the id of the user owning the email, responding with an error if it can't.
+func findUserId(c *gin.Context, userService services.IUserService, email string) (int64, bool) {
+	userId, err := userService.CheckUserExist(email)
+	if data.IsNotFound(err) {
+		responseUserNotFound(c, email)
+		return 0, false
+	}
+	if err != nil {
+		responseStorageError(c, err)
+		return 0, false
+	}
+
+	return userId, true
+}
+
+// findActiveUserId resolves the id of the user owning the email like findUserId, responding with an error as well if
+// the user is suspended or deleted.
+func findActiveUserId(c *gin.Context, userService services.IUserService, email string) (int64, bool) {
+	user, err := userService.GetUser(email)
+	if data.IsNotFound(err) {
+		responseUserNotFound(c, email)
+		return 0, false
+	}
+	if err != nil {
+		responseStorageError(c, err)
+		return 0, false
+	}
+	if user.Status != models.UserActive {
+		responseError(c, http.StatusBadRequest, models.CodeUserInactive, fmt.Sprintf("Invalid request: User %s is %s", email, user.Status))
+		return 0, false
+	}
+
+	return user.ID, true
+}
+
+const mustBeValidEmail = "must be a valid email"
+
+// fieldErrors collects the fields of a request body failing the validation.
+type fieldErrors []models.FieldError
+
+// check records the field with the message unless ok holds.
+func (errs *fieldErrors) check(ok bool, field string, message string) {
+	if !ok {
+		*errs = append(*errs, models.FieldError{Field: field, Message: message})
+	}
+}
+
+// checkEmail records the field unless it holds a valid email.
+func (errs *fieldErrors) checkEmail(email string, field string) {
+	errs.check(common.IsValidEmail(email), field, mustBeValidEmail)
+}
+
+// checkPair records the fields of the two users of a request unless they hold valid emails of distinct users.
+func (errs *fieldErrors) checkPair(requestUser string, requestField string, targetUser string, targetField string) {
+	errs.checkEmail(requestUser, requestField)
+	errs.checkEmail(targetUser, targetField)
+	errs.check(common.NormalizeEmail(requestUser) != common.NormalizeEmail(targetUser), targetField, "must differ from "+requestField)
+}
+
+// checkUsers records the field of the users of a request unless it holds between 2 and max emails, and the fields
+// of its items unless they hold valid emails of distinct users.
+func (errs *fieldErrors) checkUsers(emails []string, field string, max int) {
+	if len(emails) < 2 || len(emails) > max {
+		errs.check(false, field, fmt.Sprintf("must hold between 2 and %d emails", max))
+		return
+	}
+
+	firstFields := map[string]string{}
+	for i, email := range emails {
+		itemField := fmt.Sprintf("%s[%d]", field, i)
+		errs.checkEmail(email, itemField)
+
+		firstField, ok := firstFields[common.NormalizeEmail(email)]
+		errs.check(!ok, itemField, "must differ from "+firstField)
+		if !ok {
+			firstFields[common.NormalizeEmail(email)] = itemField
+		}
+	}
+}
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// checkPage records the limit and cursor fields of a listing unless they are valid and returns the page they select.
+// The limit defaults to 20 and can't exceed 100, an empty cursor selects the first page.
+func (errs *fieldErrors) checkPage(limit int, cursor string) models.Page {
+	return errs.checkSortedPage(limit, cursor, false)
+}
+
+// checkSortedPage is checkPage for a listing which is sorted by a value when sorted holds, its cursors holding the
+// value to continue from along with the key.
+func (errs *fieldErrors) checkSortedPage(limit int, cursor string, sorted bool) models.Page {
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+
+	after, afterSort, ok := decodeCursor(cursor)
+	errs.check(limit > 0 && limit <= maxPageLimit, "limit", fmt.Sprintf("must be between 1 and %d", maxPageLimit))
+	errs.check(ok && (after == 0 || (afterSort != "") == sorted), "cursor", "must be the nextCursor of a previous page")
+
+	return models.Page{After: after, AfterSort: afterSort, Limit: limit}
+}
+
+// bindPage reads the limit and cursor query parameters of a listing, responding with an error if they are invalid.
+func bindPage(c *gin.Context) (models.Page, bool) {
+	limit := 0
+	if value := c.Query("limit"); value != "" {
+		var err error
+		// An explicit 0 is out of range rather than the default limit.
+		if limit, err = strconv.Atoi(value); err != nil || limit == 0 {
+			limit = -1
+		}
+	}
+
+	var details fieldErrors
+	page := details.checkPage(limit, c.Query("cursor"))
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return models.Page{}, false
+	}
+
+	return page, true
+}
+
+// encodeCursor turns the key a list continues from into an opaque cursor, empty when the list is over.
+func encodeCursor(key int64) string {
+	return encodeSortedCursor(key, "")
+}
+
+// encodeSortedCursor is encodeCursor for a list sorted by a value, the value of the item keyed key being kept in the
+// cursor so that the list continues from it even if the item is gone.
+func encodeSortedCursor(key int64, sortValue string) string {
+	if key == 0 {
+		return ""
+	}
+
+	raw := strconv.FormatInt(key, 10)
+	if sortValue != "" {
+		raw += cursorSeparator + sortValue
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// cursorSeparator separates the key of a sorted cursor from the value it continues from.
+const cursorSeparator = "|"
+
+// decodeCursor reads the key and sort value of a cursor made by encodeCursor or encodeSortedCursor, 0 for an empty
+// cursor.
+func decodeCursor(cursor string) (int64, string, bool) {
+	if cursor == "" {
+		return 0, "", true
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", false
+	}
+
+	fields := strings.SplitN(string(raw), cursorSeparator, 2)
+	key, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil || key <= 0 {
+		return 0, "", false
+	}
+	if len(fields) == 2 {
+		return key, fields[1], fields[1] != ""
+	}
+
+	return key, "", true
+}
+
+// bindPathEmail reads the email of the request path and resolves the id of its user, responding with an error if it can't.
+func bindPathEmail(c *gin.Context, userService services.IUserService) (string, int64, bool) {
+	email := c.Param("email")
+	if !common.IsValidEmail(email) {
+		responseValidationError(c, models.FieldError{Field: "email", Message: mustBeValidEmail})
+		return "", 0, false
+	}
+
+	userId, ok := findUserId(c, userService, email)
+
+	return email, userId, ok
+}
+
+// bodyError is the detail reported when the request body can't be read as JSON.
+var bodyError = models.FieldError{Field: "body", Message: "must be a valid JSON object"}
@@ -1,7 +1,6 @@
 package endpoints
 
 import (
-	"database/sql"
 	"friendMgmt/data"
 	"friendMgmt/services"
 
@@ -10,37 +9,76 @@ import (
 	"github.com/swaggo/gin-swagger/swaggerFiles"
 )
 
-func initUserEndpoint(db *sql.DB) UserEndpoint {
-	var userRepo = data.UserRepository{DB: db}
-	userService := services.UserService{IUserRepository: userRepo}
-	return UserEndpoint{IUserService: userService}
+func initUserEndpoint(repositories data.Repositories) UserEndpoint {
+	userService := services.UserService{IUserRepository: repositories.IUserRepository, IUnitOfWork: repositories.IUnitOfWork}
+	relationshipService := services.RelationshipService{IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+	return UserEndpoint{IUserService: userService, IRelationshipService: relationshipService}
 }
 
-func initRelationshipEndpoint(db *sql.DB) RelationshipEndpoint {
-	var relationshipRepo = data.RelationshipRepository{DB: db}
-	relationshipService := services.RelationshipService{IRelationshipRepository: relationshipRepo}
-	var userRepo = data.UserRepository{DB: db}
-	userService := services.UserService{IUserRepository: userRepo}
-	return RelationshipEndpoint{IRelationshipService: relationshipService, IUserService: userService}
+func initRelationshipEndpoint(repositories data.Repositories) RelationshipEndpoint {
+	relationshipService := services.RelationshipService{IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+	userService := services.UserService{IUserRepository: repositories.IUserRepository}
+	postService := services.PostService{IPostRepository: repositories.IPostRepository}
+	return RelationshipEndpoint{IRelationshipService: relationshipService, IUserService: userService, IPostService: postService}
 }
 
-func ConfigRoutes(db *sql.DB) {
+func initPostEndpoint(repositories data.Repositories) PostEndpoint {
+	postService := services.PostService{IPostRepository: repositories.IPostRepository}
+	userService := services.UserService{IUserRepository: repositories.IUserRepository}
+	relationshipService := services.RelationshipService{IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+	return PostEndpoint{IPostService: postService, IUserService: userService, IRelationshipService: relationshipService}
+}
+
+func initTransferEndpoint(repositories data.Repositories) TransferEndpoint {
+	transferService := services.TransferService{IUserRepository: repositories.IUserRepository, IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+	return TransferEndpoint{ITransferService: transferService}
+}
+
+func ConfigRoutes(repositories data.Repositories) {
 
 	gin.SetMode(gin.ReleaseMode)
 
-	userApi := initUserEndpoint(db)
-	relationshipApi := initRelationshipEndpoint(db)
+	userApi := initUserEndpoint(repositories)
+	relationshipApi := initRelationshipEndpoint(repositories)
+	postApi := initPostEndpoint(repositories)
+	transferApi := initTransferEndpoint(repositories)
 
 	router := gin.Default()
 
 	router.POST("/api/friends/add", relationshipApi.CreateRelationship)
+	router.POST("/api/friends/batch", relationshipApi.Batch)
+	router.POST("/api/friends/requests/incoming", relationshipApi.IncomingFriendRequests)
+	router.POST("/api/friends/requests/outgoing", relationshipApi.OutgoingFriendRequests)
+	router.POST("/api/friends/suggestions", relationshipApi.FriendSuggestions)
+	router.POST("/api/friends/accept", relationshipApi.AcceptFriendRequest)
+	router.POST("/api/friends/reject", relationshipApi.RejectFriendRequest)
+	router.POST("/api/friends/cancel", relationshipApi.CancelFriendRequest)
 	router.POST("/api/friends", relationshipApi.FriendList)
 	router.POST("/api/friends/common-friends", relationshipApi.CommonFriendList)
+	router.POST("/api/friends/common-friends/matrix", relationshipApi.CommonFriendMatrix)
+	router.POST("/api/friends/path", relationshipApi.FriendPath)
 	router.POST("/api/friends/subcribe", relationshipApi.Subscribe)
 	router.POST("/api/friends/block", relationshipApi.Block)
+	router.POST("/api/friends/remove", relationshipApi.RemoveFriend)
+	router.POST("/api/friends/unsubscribe", relationshipApi.Unsubscribe)
+	router.POST("/api/friends/unblock", relationshipApi.Unblock)
 	router.POST("/api/friends/receive-updates", relationshipApi.ReceiveUpdates)
+	router.POST("/api/relationships/status", relationshipApi.RelationshipStatus)
+	router.POST("/api/posts/feed", postApi.Feed)
+	router.GET("/api/posts/:id/recipients", postApi.Recipients)
 	router.GET("/api/users", userApi.Users)
 	router.POST("/api/users", userApi.CreateUser)
+	router.POST("/api/users/search", userApi.Search)
+	router.POST("/api/users/merge", userApi.MergeUsers)
+	router.POST("/api/users/import", transferApi.Import)
+	router.POST("/api/users/export", transferApi.Export)
+	router.GET("/api/users/:email", userApi.Summary)
+	router.PATCH("/api/users/:email", userApi.UpdateUser)
+	router.DELETE("/api/users/:email", userApi.DeleteUser)
+	router.PUT("/api/users/:email/email", userApi.ChangeEmail)
+	router.GET("/api/users/:email/followers", userApi.Followers)
+	router.GET("/api/users/:email/following", userApi.Following)
+	router.GET("/api/users/:email/blocked", userApi.BlockedUsers)
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
@@ -0,0 +1,100 @@
+package endpoints
+
+import (
+	"friendMgmt/models"
+	"friendMgmt/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PostEndpoint struct {
+	IPostService         services.IPostService
+	IUserService         services.IUserService
+	IRelationshipService services.IRelationshipService
+}
+
+// Feed godoc
+// @Tags Post
+// @Summary API to return the posts an user can see, newest first
+// @Description The feed holds the posts of friends, of subscribed users and the ones mentioning the user, except the posts of blocked users. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.
+// @Accept  json
+// @Produce  json
+// @Param model body models.FeedRequest true "Body"
+// @Success 200 {object} models.Feed "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /posts/feed [post]
+func (p PostEndpoint) Feed(c *gin.Context) {
+	var feedRequest models.FeedRequest
+	if err := c.BindJSON(&feedRequest); err != nil {
+		responseValidationError(c, bodyError)
+		return
+	}
+
+	var details fieldErrors
+	details.checkEmail(feedRequest.Email, "email")
+	page := details.checkPage(feedRequest.Limit, feedRequest.Cursor)
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return
+	}
+
+	userId, ok := findUserId(c, p.IUserService, feedRequest.Email)
+	if !ok {
+		return
+	}
+
+	posts, next, err := p.IPostService.GetFeed(userId, page)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	feedModel := models.Feed{Posts: posts, Count: len(posts), NextCursor: encodeCursor(next), Success: true}
+
+	responseOk(c, feedModel)
+}
+
+// Recipients godoc
+// @Tags Post
+// @Summary API to list the users receiving a post by page, ordered by id
+// @Description Recipients are computed from the current relationships of the sender. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.
+// @Produce  json
+// @Param id path int true "Post id"
+// @Param limit query int false "Limit"
+// @Param cursor query string false "Cursor"
+// @Success 200 {object} models.Recipent "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /posts/{id}/recipients [get]
+func (p PostEndpoint) Recipients(c *gin.Context) {
+	postId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || postId <= 0 {
+		responseValidationError(c, models.FieldError{Field: "id", Message: "must be a post id"})
+		return
+	}
+
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	senderId, mentionIds, err := p.IPostService.GetPostAudience(postId)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	recipients, next, err := p.IRelationshipService.GetValidUsersCanReceiveUpdates(senderId, mentionIds, page)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	recipent := models.Recipent{PostId: postId, Recipents: recipients, NextCursor: encodeCursor(next), Success: true}
+
+	responseOk(c, recipent)
+}
@@ -0,0 +1,181 @@
+package endpoints_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"friendMgmt/data"
+	"friendMgmt/endpoints"
+	"friendMgmt/models"
+	"friendMgmt/services"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedWithInvalidRequest(t *testing.T) {
+	var invalidRequests = []string{
+		`{"email":}`,
+		`{"email":"invalid_email"}`,
+		`{"email":"user@email.com","limit":-1}`,
+		`{"email":"user@email.com","limit":101}`,
+		`{"email":"user@email.com","cursor":"not a cursor"}`,
+		`{"email":"user@email.com","cursor":"MA"}`}
+	for _, request := range invalidRequests {
+
+		var jsonStr = []byte(request)
+
+		postServiceMock := services.PostServiceMock{}
+		userServiceMock := services.UserServiceMock{}
+
+		postEndpoint := endpoints.PostEndpoint{IPostService: &postServiceMock, IUserService: &userServiceMock}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/posts/feed", bytes.NewBuffer(jsonStr))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		postEndpoint.Feed(c)
+
+		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, false, actualResult.Success)
+		assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
+	}
+}
+
+func TestFeedWithNotFoundAccount(t *testing.T) {
+	var jsonStr = []byte(`{"email":"user@notfound.com"}`)
+
+	postServiceMock := services.PostServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("CheckUserExist", "user@notfound.com").Return(int64(0), &data.NotFoundError{Entity: "user", Key: "user@notfound.com"})
+
+	postEndpoint := endpoints.PostEndpoint{IPostService: &postServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/posts/feed", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	postEndpoint.Feed(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusNotFound)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, "Invalid request: User name user@notfound.com is not found", actualResult.Message)
+	assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
+}
+
+func TestFeedReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"email":"user@email.com","cursor":"MjA"}`)
+
+	posts := []models.Post{
+		{ID: 2, Sender: "friend@email.com", Text: "hello user@email.com", Mentions: []string{"user@email.com"}, CreatedAt: time.Date(2020, 4, 13, 11, 0, 0, 0, time.UTC)},
+		{ID: 1, Sender: "friend@email.com", Text: "hello world", CreatedAt: time.Date(2020, 4, 13, 10, 0, 0, 0, time.UTC)}}
+
+	postServiceMock := services.PostServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("CheckUserExist", "user@email.com").Return(int64(1), nil)
+	postServiceMock.On("GetFeed", int64(1), models.Page{After: 20, Limit: 20}).Return(posts, int64(1), nil)
+
+	postEndpoint := endpoints.PostEndpoint{IPostService: &postServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/posts/feed", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	postEndpoint.Feed(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.Feed
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, true, actualResult.Success)
+	assert.Equal(t, 2, actualResult.Count)
+	assert.Equal(t, posts, actualResult.Posts)
+	assert.Equal(t, "MQ", actualResult.NextCursor)
+}
+
+func TestRecipientsWithInvalidRequest(t *testing.T) {
+	var invalidRequests = map[string]string{
+		"abc": "",
+		"0":   "",
+		"10":  "limit=101",
+	}
+	for id, query := range invalidRequests {
+		postEndpoint := endpoints.PostEndpoint{IPostService: &services.PostServiceMock{}, IUserService: &services.UserServiceMock{}, IRelationshipService: &services.RelationshipServiceMock{}}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/posts/"+id+"/recipients?"+query, nil)
+		c.Params = gin.Params{{Key: "id", Value: id}}
+
+		postEndpoint.Recipients(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, id)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
+	}
+}
+
+func TestRecipientsWithNotFoundPost(t *testing.T) {
+	postServiceMock := services.PostServiceMock{}
+	postServiceMock.On("GetPostAudience", int64(10)).Return(int64(0), []int64(nil), &data.NotFoundError{Entity: "post", Key: "10"})
+
+	postEndpoint := endpoints.PostEndpoint{IPostService: &postServiceMock, IUserService: &services.UserServiceMock{}, IRelationshipService: &services.RelationshipServiceMock{}}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/posts/10/recipients", nil)
+	c.Params = gin.Params{{Key: "id", Value: "10"}}
+
+	postEndpoint.Recipients(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.CodePostNotFound, actualResult.Code)
+}
+
+func TestRecipientsReturnOk(t *testing.T) {
+	postServiceMock := services.PostServiceMock{}
+	postServiceMock.On("GetPostAudience", int64(10)).Return(int64(1), []int64{4}, nil)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	relationshipServiceMock.On("GetValidUsersCanReceiveUpdates", int64(1), []int64{4}, models.Page{After: 2, Limit: 2}).Return([]string{"user3@email.com", "user4@email.com"}, int64(4), nil)
+
+	postEndpoint := endpoints.PostEndpoint{IPostService: &postServiceMock, IUserService: &services.UserServiceMock{}, IRelationshipService: &relationshipServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/posts/10/recipients?limit=2&cursor=Mg", nil)
+	c.Params = gin.Params{{Key: "id", Value: "10"}}
+
+	postEndpoint.Recipients(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	var actualResult models.Recipent
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.Recipent{PostId: 10, Recipents: []string{"user3@email.com", "user4@email.com"}, NextCursor: "NA", Success: true}, actualResult)
+}
@@ -5,229 +5,536 @@ import (
 	"friendMgmt/common"
 	"friendMgmt/models"
 	"friendMgmt/services"
-	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcnijman/go-emailaddress"
 )
 
+const (
+	defaultSuggestionLimit = 10
+	maxSuggestionLimit     = 100
+	defaultPathDepth       = 6
+	maxPathDepth           = 10
+	maxBatchOperations     = 500
+	maxCommonFriendUsers   = 50
+)
+
 type RelationshipEndpoint struct {
 	IRelationshipService services.IRelationshipService
 	IUserService         services.IUserService
+	IPostService         services.IPostService
 }
 
 // CreateRelationship godoc
 // @Tags Friend
-// @Summary API to create a friend connection between two users
+// @Summary API to send a friend request from the first user to the second one
+// @Description The connection is only created once the target accepts the request. If the target has already requested the requestor, both requests are accepted at once.
 // @Accept  json
 // @Produce  json
 // @Param model body models.FriendCheck true "Body"
 // @Success 200 {object} models.Success "OK"
 // @Failure 400 {object} models.Failure "Bad Request"
-// @Failure 500 {object} models.Failure "Internal Error"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
 // @Router /friends/add [post]
 func (r RelationshipEndpoint) CreateRelationship(c *gin.Context) {
-	var friendCheck models.FriendCheck
-	if err := c.BindJSON(&friendCheck); err != nil {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	requestUser, targetUser, ok := r.bindFriendCheck(c)
+	if !ok {
 		return
 	}
 
-	if len(friendCheck.Friends) != 2 {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	if _, err := r.IRelationshipService.Befriend(requestUser, targetUser); err != nil {
+		responseRelationshipError(c, err)
 		return
 	}
 
-	var requestUser = friendCheck.Friends[0]
-	var targetUser = friendCheck.Friends[1]
+	success := models.Success{Success: true}
+	responseOk(c, success)
+}
+
+// Batch godoc
+// @Tags Friend
+// @Summary API to apply friend requests, subscriptions and blocks in bulk
+// @Description The operations are applied in their order in a single transaction like /friends/add, /friends/subcribe and /friends/block would, and the result of each of them is reported in the same order. A failed operation is skipped, unless the batch is atomic in which case nothing is stored. A batch holds up to 500 operations.
+// @Accept  json
+// @Produce  json
+// @Param model body models.FriendBatchRequest true "Body"
+// @Success 200 {object} models.FriendBatch "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/batch [post]
+func (r RelationshipEndpoint) Batch(c *gin.Context) {
+	var request models.FriendBatchRequest
+
+	if err := c.BindJSON(&request); err != nil {
+		responseValidationError(c, bodyError)
+		return
+	}
+
+	var details fieldErrors
+	details.check(len(request.Operations) > 0 && len(request.Operations) <= maxBatchOperations, "operations", fmt.Sprintf("must hold between 1 and %d operations", maxBatchOperations))
+	for i, operation := range request.Operations {
+		field := fmt.Sprintf("operations[%d]", i)
+		details.check(operation.Type.IsValid(), field+".type", "must be friend, subscribe or block")
+		details.checkPair(operation.Requestor, field+".requestor", operation.Target, field+".target")
+	}
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return
+	}
 
-	if !common.IsValidEmail(requestUser) || !common.IsValidEmail(targetUser) || requestUser == targetUser {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	batch, err := r.IRelationshipService.ApplyBatch(request.Operations, request.Atomic)
+	if err != nil {
+		responseStorageError(c, err)
 		return
 	}
 
-	var requestUserId = r.IUserService.CheckUserExist(requestUser)
-	if requestUserId <= 0 {
-		responseError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: User name %s is not found", requestUser))
+	batch.Success = true
+
+	responseOk(c, batch)
+}
+
+// IncomingFriendRequests godoc
+// @Tags Friend
+// @Summary API to list friend requests sent to an user which are waiting for an answer, oldest first
+// @Description The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.
+// @Accept  json
+// @Produce  json
+// @Param model body models.ListRequest true "Body"
+// @Success 200 {object} models.FriendRequest "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/requests/incoming [post]
+func (r RelationshipEndpoint) IncomingFriendRequests(c *gin.Context) {
+	userId, page, ok := r.bindListRequest(c)
+	if !ok {
 		return
+	}
 
+	requests, next, err := r.IRelationshipService.GetIncomingFriendRequests(userId, page)
+	if err != nil {
+		responseStorageError(c, err)
+		return
 	}
 
-	var targetUserId = r.IUserService.CheckUserExist(targetUser)
-	if targetUserId <= 0 {
-		responseError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: User name %s is not found", targetUser))
+	friendRequestModel := models.FriendRequest{Requests: requests, Count: len(requests), NextCursor: encodeCursor(next), Success: true}
+
+	responseOk(c, friendRequestModel)
+}
+
+// OutgoingFriendRequests godoc
+// @Tags Friend
+// @Summary API to list friend requests sent by an user which are waiting for an answer, oldest first
+// @Description The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.
+// @Accept  json
+// @Produce  json
+// @Param model body models.ListRequest true "Body"
+// @Success 200 {object} models.FriendRequest "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/requests/outgoing [post]
+func (r RelationshipEndpoint) OutgoingFriendRequests(c *gin.Context) {
+	userId, page, ok := r.bindListRequest(c)
+	if !ok {
 		return
 	}
 
-	connectedRelationshipIds := r.IRelationshipService.CheckConnected(requestUserId, targetUserId)
-	if len(connectedRelationshipIds) > 0 {
-		responseError(c, http.StatusBadRequest, "Invalid request: connected status is existed")
+	requests, next, err := r.IRelationshipService.GetOutgoingFriendRequests(userId, page)
+	if err != nil {
+		responseStorageError(c, err)
 		return
 	}
 
-	blockedRelationshipIds := r.IRelationshipService.CheckFullyBlocked(requestUserId, targetUserId)
-	if len(blockedRelationshipIds) > 0 {
-		responseError(c, http.StatusBadRequest, "Invalid request: blocked status is existed")
+	friendRequestModel := models.FriendRequest{Requests: requests, Count: len(requests), NextCursor: encodeCursor(next), Success: true}
+
+	responseOk(c, friendRequestModel)
+}
+
+// FriendSuggestions godoc
+// @Tags Friend
+// @Summary API to suggest friends of friends to an user, ranked by their number of mutual friends
+// @Description Users already connected, blocked or holding a pending friend request with the user in either direction are not suggested. The limit defaults to 10 and can't exceed 100.
+// @Accept  json
+// @Produce  json
+// @Param model body models.SuggestionRequest true "Body"
+// @Success 200 {object} models.Suggestion "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/suggestions [post]
+func (r RelationshipEndpoint) FriendSuggestions(c *gin.Context) {
+	var suggestionRequest models.SuggestionRequest
+	if err := c.BindJSON(&suggestionRequest); err != nil {
+		responseValidationError(c, bodyError)
 		return
 	}
 
-	subcribedRelationshipIds := r.IRelationshipService.CheckFullySubcribed(requestUserId, targetUserId)
-	if len(subcribedRelationshipIds) > 0 {
-		r.IRelationshipService.DeleteRelationships(subcribedRelationshipIds)
+	if suggestionRequest.Limit == 0 {
+		suggestionRequest.Limit = defaultSuggestionLimit
 	}
 
-	relationshipModel := models.Relationship{Status: 1, RequestUserId: requestUserId, TargetUserId: targetUserId}
+	var details fieldErrors
+	details.checkEmail(suggestionRequest.Email, "email")
+	details.check(suggestionRequest.Limit > 0 && suggestionRequest.Limit <= maxSuggestionLimit, "limit", fmt.Sprintf("must be between 1 and %d", maxSuggestionLimit))
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return
+	}
 
-	if insertedId := r.IRelationshipService.CreateRelationship(&relationshipModel); insertedId > 0 {
-		success := models.Success{Success: true}
-		responseOk(c, success)
+	userId, ok := findUserId(c, r.IUserService, suggestionRequest.Email)
+	if !ok {
 		return
 	}
 
-	responseError(c, http.StatusInternalServerError, "Oops! There is an error, please try again.")
-	return
+	suggestions, err := r.IRelationshipService.GetFriendSuggestions(userId, suggestionRequest.Limit)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	suggestionModel := models.Suggestion{Suggestions: suggestions, Count: len(suggestions), Success: true}
+
+	responseOk(c, suggestionModel)
 }
 
-// FriendList godoc
+// AcceptFriendRequest godoc
 // @Tags Friend
-// @Summary API to check list friends of an user
+// @Summary API to allow an user (requestor) to accept the friend request sent by another user (target)
 // @Accept  json
 // @Produce  json
-// @Param model body models.Email true "Body"
+// @Param model body models.UserAction true "Body"
 // @Success 200 {object} models.Success "OK"
 // @Failure 400 {object} models.Failure "Bad Request"
-// @Router /friends [post]
-func (r RelationshipEndpoint) FriendList(c *gin.Context) {
-	var email models.Email
-	if err := c.BindJSON(&email); err != nil {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/accept [post]
+func (r RelationshipEndpoint) AcceptFriendRequest(c *gin.Context) {
+	requestUser, targetUser, ok := r.bindUserAction(c)
+	if !ok {
+		return
+	}
+
+	if _, err := r.IRelationshipService.AcceptFriendRequest(requestUser, targetUser); err != nil {
+		responseRelationshipError(c, err)
+		return
+	}
+
+	success := models.Success{Success: true}
+	responseOk(c, success)
+}
+
+// RejectFriendRequest godoc
+// @Tags Friend
+// @Summary API to allow an user (requestor) to reject the friend request sent by another user (target)
+// @Accept  json
+// @Produce  json
+// @Param model body models.UserAction true "Body"
+// @Success 200 {object} models.Success "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/reject [post]
+func (r RelationshipEndpoint) RejectFriendRequest(c *gin.Context) {
+	requestUser, targetUser, ok := r.bindUserAction(c)
+	if !ok {
+		return
+	}
+
+	if _, err := r.IRelationshipService.RejectFriendRequest(requestUser, targetUser); err != nil {
+		responseRelationshipError(c, err)
+		return
+	}
+
+	success := models.Success{Success: true}
+	responseOk(c, success)
+}
+
+// CancelFriendRequest godoc
+// @Tags Friend
+// @Summary API to allow an user (requestor) to cancel the friend request sent to another user (target)
+// @Accept  json
+// @Produce  json
+// @Param model body models.UserAction true "Body"
+// @Success 200 {object} models.Success "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/cancel [post]
+func (r RelationshipEndpoint) CancelFriendRequest(c *gin.Context) {
+	requestUser, targetUser, ok := r.bindUserAction(c)
+	if !ok {
 		return
 	}
 
-	if isValid := common.IsValidEmail(email.Email); !isValid {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	if _, err := r.IRelationshipService.CancelFriendRequest(requestUser, targetUser); err != nil {
+		responseRelationshipError(c, err)
 		return
 	}
 
-	userId := r.IUserService.CheckUserExist(email.Email)
-	if userId < 0 {
-		responseError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: User name %s is not found", email.Email))
+	success := models.Success{Success: true}
+	responseOk(c, success)
+}
+
+// FriendList godoc
+// @Tags Friend
+// @Summary API to check list friends of an user, ordered by id
+// @Description Friends who blocked the user are left out. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.
+// @Accept  json
+// @Produce  json
+// @Param model body models.ListRequest true "Body"
+// @Success 200 {object} models.Success "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends [post]
+func (r RelationshipEndpoint) FriendList(c *gin.Context) {
+	userId, page, ok := r.bindListRequest(c)
+	if !ok {
 		return
 	}
 
-	friendList := r.IRelationshipService.GetFriendList(userId)
+	friendList, next, err := r.IRelationshipService.GetFriendList(userId, page)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
 
-	friendModel := models.Friend{Friends: friendList, Count: len(friendList), Success: true}
+	friendModel := models.Friend{Friends: friendList, Count: len(friendList), NextCursor: encodeCursor(next), Success: true}
 
 	responseOk(c, friendModel)
 }
 
 // CommonFriendList godoc
 // @Tags Friend
-// @Summary API to check common friends of two users
+// @Summary API to check common friends of 2 to 50 users, ordered by id
+// @Description Only the friends of every user are listed, the ones who blocked the first user being left out. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.
 // @Accept  json
 // @Produce  json
-// @Param model body models.FriendCheck true "Body"
-// @Success 200 {object} models.Success "OK"
+// @Param model body models.CommonFriendRequest true "Body"
+// @Success 200 {object} models.Friend "OK"
 // @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
 // @Router /friends/common-friends [post]
 func (r RelationshipEndpoint) CommonFriendList(c *gin.Context) {
 
-	var friendCheck models.FriendCheck
-	if err := c.BindJSON(&friendCheck); err != nil {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	var commonFriendRequest models.CommonFriendRequest
+	if err := c.BindJSON(&commonFriendRequest); err != nil {
+		responseValidationError(c, bodyError)
 		return
 	}
 
-	if len(friendCheck.Friends) != 2 {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	var details fieldErrors
+	details.checkUsers(commonFriendRequest.Friends, "friends", maxCommonFriendUsers)
+	page := details.checkPage(commonFriendRequest.Limit, commonFriendRequest.Cursor)
+	if len(details) > 0 {
+		responseValidationError(c, details...)
 		return
 	}
 
-	var requestUser = friendCheck.Friends[0]
-	var targetUser = friendCheck.Friends[1]
+	userIds, ok := findUserIds(c, r.IUserService, commonFriendRequest.Friends, "friends")
+	if !ok {
+		return
+	}
 
-	if !common.IsValidEmail(requestUser) || !common.IsValidEmail(targetUser) || requestUser == targetUser {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	commonFriends, next, err := r.IRelationshipService.GetCommonFriendList(userIds, page)
+	if err != nil {
+		responseStorageError(c, err)
 		return
 	}
 
-	var requestUserId = r.IUserService.CheckUserExist(requestUser)
-	if requestUserId <= 0 {
-		responseError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: User name %s is not found", requestUser))
+	friendModel := models.Friend{Friends: commonFriends, Count: len(commonFriends), NextCursor: encodeCursor(next), Success: true}
+
+	responseOk(c, friendModel)
+}
+
+// CommonFriendMatrix godoc
+// @Tags Friend
+// @Summary API to count the common friends of every pair of 2 to 50 users
+// @Description The count at counts[i][j] is the number of friends the users i and j have in common, the ones who blocked the user i being left out like /friends/common-friends does. The counts of an user with itself are 0.
+// @Accept  json
+// @Produce  json
+// @Param model body models.CommonFriendMatrixRequest true "Body"
+// @Success 200 {object} models.CommonFriendMatrix "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/common-friends/matrix [post]
+func (r RelationshipEndpoint) CommonFriendMatrix(c *gin.Context) {
+	var matrixRequest models.CommonFriendMatrixRequest
+	if err := c.BindJSON(&matrixRequest); err != nil {
+		responseValidationError(c, bodyError)
 		return
+	}
 
+	var details fieldErrors
+	details.checkUsers(matrixRequest.Users, "users", maxCommonFriendUsers)
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return
 	}
 
-	var targetUserId = r.IUserService.CheckUserExist(targetUser)
-	if targetUserId <= 0 {
-		responseError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: User name %s is not found", targetUser))
+	userIds, ok := findUserIds(c, r.IUserService, matrixRequest.Users, "users")
+	if !ok {
 		return
 	}
 
-	commonFriends := r.IRelationshipService.GetCommonFriendList(requestUserId, targetUserId)
+	counts, err := r.IRelationshipService.CountCommonFriends(userIds)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
 
-	friendModel := models.Friend{Friends: commonFriends, Count: len(commonFriends), Success: true}
+	matrix := models.CommonFriendMatrix{Users: matrixRequest.Users, Counts: make([][]int, len(userIds)), Success: true}
+	for i, userId := range userIds {
+		matrix.Counts[i] = make([]int, len(userIds))
+		for j, withId := range userIds {
+			matrix.Counts[i][j] = counts[userId][withId]
+		}
+	}
 
-	responseOk(c, friendModel)
+	responseOk(c, matrix)
 }
 
-// Subscribe godoc
+// FriendPath godoc
 // @Tags Friend
-// @Summary API to allow an user can subscribe another user
+// @Summary API to find the shortest friendship path between two users
+// @Description The path lists the users in between, from the first user to the second one, and the length is the number of friendships on it. Friendships between users blocking each other are skipped. The maxDepth defaults to 6 and can't exceed 10.
 // @Accept  json
 // @Produce  json
-// @Param model body models.UserAction true "Body"
-// @Success 200 {object} models.Success "OK"
+// @Param model body models.PathRequest true "Body"
+// @Success 200 {object} models.FriendPath "OK"
 // @Failure 400 {object} models.Failure "Bad Request"
-// @Router /friends/subcribe [post]
-func (r RelationshipEndpoint) Subscribe(c *gin.Context) {
-	var userAction models.UserAction
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/path [post]
+func (r RelationshipEndpoint) FriendPath(c *gin.Context) {
+	var pathRequest models.PathRequest
+	if err := c.BindJSON(&pathRequest); err != nil {
+		responseValidationError(c, bodyError)
+		return
+	}
 
-	if err := c.BindJSON(&userAction); err != nil {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	if pathRequest.MaxDepth == 0 {
+		pathRequest.MaxDepth = defaultPathDepth
+	}
+
+	var details fieldErrors
+	details.check(len(pathRequest.Friends) == 2, "friends", "must hold exactly 2 emails")
+	details.check(pathRequest.MaxDepth > 0 && pathRequest.MaxDepth <= maxPathDepth, "maxDepth", fmt.Sprintf("must be between 1 and %d", maxPathDepth))
+	if len(details) > 0 {
+		responseValidationError(c, details...)
 		return
 	}
 
-	var requestUser = userAction.Requestor
-	var targetUser = userAction.Target
+	var requestUser = pathRequest.Friends[0]
+	var targetUser = pathRequest.Friends[1]
+
+	details.checkPair(requestUser, "friends[0]", targetUser, "friends[1]")
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return
+	}
+
+	requestUserId, ok := findUserId(c, r.IUserService, requestUser)
+	if !ok {
+		return
+	}
+
+	targetUserId, ok := findUserId(c, r.IUserService, targetUser)
+	if !ok {
+		return
+	}
 
-	if !common.IsValidEmail(requestUser) || !common.IsValidEmail(targetUser) || requestUser == targetUser {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	pathIds, err := r.IRelationshipService.GetShortestPath(requestUserId, targetUserId, pathRequest.MaxDepth)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+	if pathIds == nil {
+		responseOk(c, models.FriendPath{Connected: false, Success: true})
 		return
 	}
 
-	var requestUserId = r.IUserService.CheckUserExist(requestUser)
-	if requestUserId <= 0 {
-		responseError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: User name %s is not found", requestUser))
+	intermediateIds := pathIds[1 : len(pathIds)-1]
+	emails, err := r.IUserService.GetEmails(intermediateIds)
+	if err != nil {
+		responseStorageError(c, err)
 		return
 	}
 
-	var targetUserId = r.IUserService.CheckUserExist(targetUser)
-	if targetUserId <= 0 {
-		responseError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: User name %s is not found", targetUser))
+	path := make([]string, 0, len(intermediateIds))
+	for _, id := range intermediateIds {
+		path = append(path, emails[id])
+	}
+
+	friendPathModel := models.FriendPath{Connected: true, Path: path, Length: len(pathIds) - 1, Success: true}
+
+	responseOk(c, friendPathModel)
+}
+
+// RelationshipStatus godoc
+// @Tags Relationship
+// @Summary API to tell what the target is to the requestor
+// @Description Each flag is read from the requestor's side: subscribed means the requestor subscribes to the target and subscribedBy the other way round, the same goes for blocked and blockedBy. requestSent and requestReceived tell a pending friend request in either direction. outgoing and incoming list the same relationships by their status, a friend connection being listed on both sides.
+// @Accept  json
+// @Produce  json
+// @Param model body models.UserAction true "Body"
+// @Success 200 {object} models.RelationshipView "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /relationships/status [post]
+func (r RelationshipEndpoint) RelationshipStatus(c *gin.Context) {
+	requestUser, targetUser, ok := r.bindUserAction(c)
+	if !ok {
 		return
 	}
 
-	if subcribedRelationshipId := r.IRelationshipService.CheckPartialSubcribed(requestUserId, targetUserId); len(subcribedRelationshipId) > 0 {
-		responseError(c, http.StatusBadRequest, "Invalid request: subcribed status is existed")
+	requestUserId, ok := findUserId(c, r.IUserService, requestUser)
+	if !ok {
 		return
 	}
 
-	if blockedRelationshipId := r.IRelationshipService.CheckPartialBlocked(requestUserId, targetUserId); len(blockedRelationshipId) > 0 {
-		responseError(c, http.StatusBadRequest, "Invalid request: blocked status is existed")
+	targetUserId, ok := findUserId(c, r.IUserService, targetUser)
+	if !ok {
 		return
 	}
 
-	if connectedRelationshipIds := r.IRelationshipService.CheckConnected(requestUserId, targetUserId); len(connectedRelationshipIds) > 0 {
-		success := models.Success{Success: true}
-		responseOk(c, success)
+	view, err := r.IRelationshipService.GetRelationshipView(requestUserId, targetUserId)
+	if err != nil {
+		responseStorageError(c, err)
 		return
 	}
 
-	relationshipModel := models.Relationship{Status: 2, RequestUserId: requestUserId, TargetUserId: targetUserId}
+	view.Success = true
 
-	r.IRelationshipService.CreateRelationship(&relationshipModel)
+	responseOk(c, view)
+}
+
+// Subscribe godoc
+// @Tags Friend
+// @Summary API to allow an user can subscribe another user
+// @Accept  json
+// @Produce  json
+// @Param model body models.UserAction true "Body"
+// @Success 200 {object} models.Success "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/subcribe [post]
+func (r RelationshipEndpoint) Subscribe(c *gin.Context) {
+	requestUser, targetUser, ok := r.bindUserAction(c)
+	if !ok {
+		return
+	}
+
+	if _, err := r.IRelationshipService.Subscribe(requestUser, targetUser); err != nil {
+		responseRelationshipError(c, err)
+		return
+	}
 
 	success := models.Success{Success: true}
 	responseOk(c, success)
@@ -241,51 +548,100 @@ func (r RelationshipEndpoint) Subscribe(c *gin.Context) {
 // @Param model body models.UserAction true "Body"
 // @Success 200 {object} models.Success "OK"
 // @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
 // @Router /friends/block [post]
 func (r RelationshipEndpoint) Block(c *gin.Context) {
-	var userAction models.UserAction
+	requestUser, targetUser, ok := r.bindUserAction(c)
+	if !ok {
+		return
+	}
 
-	if err := c.BindJSON(&userAction); err != nil {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	if _, err := r.IRelationshipService.Block(requestUser, targetUser); err != nil {
+		responseRelationshipError(c, err)
 		return
 	}
 
-	var requestUser = userAction.Requestor
-	var targetUser = userAction.Target
+	success := models.Success{Success: true}
+	responseOk(c, success)
+}
 
-	if !common.IsValidEmail(requestUser) || !common.IsValidEmail(targetUser) || requestUser == targetUser {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+// RemoveFriend godoc
+// @Tags Friend
+// @Summary API to remove the friend connection between two users
+// @Description Both users stop receiving updates from each other. Subscriptions dropped when they became friends are not restored, either user can subscribe again.
+// @Accept  json
+// @Produce  json
+// @Param model body models.FriendCheck true "Body"
+// @Success 200 {object} models.Success "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/remove [post]
+func (r RelationshipEndpoint) RemoveFriend(c *gin.Context) {
+	requestUser, targetUser, ok := r.bindFriendCheck(c)
+	if !ok {
 		return
 	}
 
-	var requestUserId = r.IUserService.CheckUserExist(requestUser)
-	if requestUserId <= 0 {
-		responseError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: User name %s is not found", requestUser))
+	if _, err := r.IRelationshipService.RemoveFriend(requestUser, targetUser); err != nil {
+		responseRelationshipError(c, err)
 		return
 	}
 
-	var targetUserId = r.IUserService.CheckUserExist(targetUser)
-	if targetUserId <= 0 {
-		responseError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: User name %s is not found", targetUser))
+	success := models.Success{Success: true}
+	responseOk(c, success)
+}
+
+// Unsubscribe godoc
+// @Tags Friend
+// @Summary API to allow an user to stop subscribing another user
+// @Description Only removes an explicit subscription, friends keep receiving updates from each other until the connection is removed.
+// @Accept  json
+// @Produce  json
+// @Param model body models.UserAction true "Body"
+// @Success 200 {object} models.Success "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/unsubscribe [post]
+func (r RelationshipEndpoint) Unsubscribe(c *gin.Context) {
+	requestUser, targetUser, ok := r.bindUserAction(c)
+	if !ok {
 		return
 	}
 
-	if blockedRelationshipId := r.IRelationshipService.CheckPartialBlocked(requestUserId, targetUserId); len(blockedRelationshipId) > 0 {
-		responseError(c, http.StatusBadRequest, "Invalid request: blocked status is existed")
+	if _, err := r.IRelationshipService.Unsubscribe(requestUser, targetUser); err != nil {
+		responseRelationshipError(c, err)
 		return
 	}
 
-	if subcribedRelationshipId := r.IRelationshipService.CheckPartialSubcribed(requestUserId, targetUserId); len(subcribedRelationshipId) > 0 {
-		r.IRelationshipService.DeleteRelationships(subcribedRelationshipId)
-	}
+	success := models.Success{Success: true}
+	responseOk(c, success)
+}
 
-	if connectedRelationshipId := r.IRelationshipService.CheckConnected(requestUserId, targetUserId); len(connectedRelationshipId) > 0 {
-		r.IRelationshipService.DeleteRelationships(connectedRelationshipId)
+// Unblock godoc
+// @Tags Friend
+// @Summary API to allow an user to unblock another user
+// @Description Connections, subscriptions and friend requests dropped by the block are not restored. A block set by the target on the requestor is kept.
+// @Accept  json
+// @Produce  json
+// @Param model body models.UserAction true "Body"
+// @Success 200 {object} models.Success "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /friends/unblock [post]
+func (r RelationshipEndpoint) Unblock(c *gin.Context) {
+	requestUser, targetUser, ok := r.bindUserAction(c)
+	if !ok {
+		return
 	}
 
-	relationshipModel := models.Relationship{Status: 3, RequestUserId: requestUserId, TargetUserId: targetUserId}
-
-	r.IRelationshipService.CreateRelationship(&relationshipModel)
+	if _, err := r.IRelationshipService.Unblock(requestUser, targetUser); err != nil {
+		responseRelationshipError(c, err)
+		return
+	}
 
 	success := models.Success{Success: true}
 	responseOk(c, success)
@@ -293,32 +649,37 @@ func (r RelationshipEndpoint) Block(c *gin.Context) {
 
 // ReceiveUpdates godoc
 // @Tags Friend
-// @Summary API to return list of users can receive update from an user
+// @Summary API to publish a post of an user and return list of users can receive update from it
+// @Description The post is stored with its mentions and shows up in the feed of its recipients. The first 20 recipients are returned, ordered by id, the next ones are listed by the recipients API of the post from the nextCursor.
 // @Accept  json
 // @Produce  json
 // @Param model body models.UserPost true "Body"
-// @Success 200 {object} models.Success "OK"
+// @Success 200 {object} models.Recipent "OK"
 // @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
 // @Router /friends/receive-updates [post]
 func (r RelationshipEndpoint) ReceiveUpdates(c *gin.Context) {
 	var userPost models.UserPost
 
 	if err := c.BindJSON(&userPost); err != nil {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+		responseValidationError(c, bodyError)
 		return
 	}
 
 	var sender = userPost.Sender
 	var text = userPost.Text
 
-	if !common.IsValidEmail(sender) || len(text) == 0 {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	var details fieldErrors
+	details.checkEmail(sender, "sender")
+	details.check(len(text) > 0, "text", "must not be empty")
+	if len(details) > 0 {
+		responseValidationError(c, details...)
 		return
 	}
 
-	var senderId = r.IUserService.CheckUserExist(sender)
-	if senderId <= 0 {
-		responseError(c, http.StatusBadRequest, fmt.Sprintf("Invalid request: User name %s is not found", sender))
+	senderId, ok := findActiveUserId(c, r.IUserService, sender)
+	if !ok {
 		return
 	}
 
@@ -327,23 +688,109 @@ func (r RelationshipEndpoint) ReceiveUpdates(c *gin.Context) {
 
 	mentionedEmails = make([]string, len(emails))
 	for i, strEmail := range emails {
-		mentionedEmails[i] = strEmail.String()
+		mentionedEmails[i] = common.NormalizeEmail(strEmail.String())
 	}
 
 	var mentionedIds []int64
 	if len(mentionedEmails) > 0 {
-		senderIndex := common.GetIndex(sender, mentionedEmails)
+		senderIndex := common.GetIndex(common.NormalizeEmail(sender), mentionedEmails)
 		if senderIndex >= 0 {
 			mentionedEmails = common.RemoveItemInStringSlice(mentionedEmails, senderIndex)
 		}
 
-		mentionedIds = r.IUserService.CheckUsersExist(mentionedEmails)
+		var err error
+		if mentionedIds, err = r.IUserService.CheckUsersExist(mentionedEmails); err != nil {
+			responseStorageError(c, err)
+			return
+		}
+	}
+
+	// The recipients are read before storing the post, so that a failure doesn't leave a post to be duplicated
+	// by a retry.
+	result, next, err := r.IRelationshipService.GetValidUsersCanReceiveUpdates(senderId, mentionedIds, models.Page{Limit: defaultPageLimit})
+	if err != nil {
+		responseStorageError(c, err)
+		return
 	}
 
-	result := r.IRelationshipService.GetValidUsersCanReceiveUpdates(senderId, mentionedIds)
+	postId, err := r.IPostService.CreatePost(senderId, text, mentionedIds)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
 
-	recipent := models.Recipent{Success: true, Recipents: result}
+	recipent := models.Recipent{PostId: postId, Success: true, Recipents: result, NextCursor: encodeCursor(next)}
 
 	responseOk(c, recipent)
 	return
 }
+
+// bindListRequest reads a ListRequest body and resolves the id of its user and the page it asks for,
+// responding with an error if it can't.
+func (r RelationshipEndpoint) bindListRequest(c *gin.Context) (int64, models.Page, bool) {
+	var listRequest models.ListRequest
+	if err := c.BindJSON(&listRequest); err != nil {
+		responseValidationError(c, bodyError)
+		return 0, models.Page{}, false
+	}
+
+	var details fieldErrors
+	details.checkEmail(listRequest.Email, "email")
+	page := details.checkPage(listRequest.Limit, listRequest.Cursor)
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return 0, models.Page{}, false
+	}
+
+	userId, ok := findUserId(c, r.IUserService, listRequest.Email)
+
+	return userId, page, ok
+}
+
+// bindFriendCheck reads a FriendCheck body of exactly two users and returns their emails, responding with an error if they are invalid.
+func (r RelationshipEndpoint) bindFriendCheck(c *gin.Context) (string, string, bool) {
+	var friendCheck models.FriendCheck
+	if err := c.BindJSON(&friendCheck); err != nil {
+		responseValidationError(c, bodyError)
+		return "", "", false
+	}
+
+	if len(friendCheck.Friends) != 2 {
+		responseValidationError(c, models.FieldError{Field: "friends", Message: "must hold exactly 2 emails"})
+		return "", "", false
+	}
+
+	var requestUser = friendCheck.Friends[0]
+	var targetUser = friendCheck.Friends[1]
+
+	var details fieldErrors
+	details.checkPair(requestUser, "friends[0]", targetUser, "friends[1]")
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return "", "", false
+	}
+
+	return requestUser, targetUser, true
+}
+
+// bindUserAction reads an UserAction body and returns the emails of both users, responding with an error if they are invalid.
+func (r RelationshipEndpoint) bindUserAction(c *gin.Context) (string, string, bool) {
+	var userAction models.UserAction
+
+	if err := c.BindJSON(&userAction); err != nil {
+		responseValidationError(c, bodyError)
+		return "", "", false
+	}
+
+	var requestUser = userAction.Requestor
+	var targetUser = userAction.Target
+
+	var details fieldErrors
+	details.checkPair(requestUser, "requestor", targetUser, "target")
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return "", "", false
+	}
+
+	return requestUser, targetUser, true
+}
@@ -3,6 +3,7 @@ package endpoints_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"friendMgmt/data"
 	"friendMgmt/endpoints"
@@ -12,6 +13,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -32,7 +34,7 @@ func TestCreateRelationshipWithInvalidAccounts(t *testing.T) {
 		relationshipServiceMock := services.RelationshipServiceMock{}
 		userServiceMock := services.UserServiceMock{}
 
-		relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
@@ -48,6 +50,40 @@ func TestCreateRelationshipWithInvalidAccounts(t *testing.T) {
 
 		assert.Equal(t, false, actualResult.Success)
 		assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
+	}
+}
+
+func TestCreateRelationshipReportsInvalidFields(t *testing.T) {
+	var invalidRequests = map[string][]models.FieldError{
+		`{"friends":"target@email.com"}`:   {{Field: "body", Message: "must be a valid JSON object"}},
+		`{"friends":["target@email.com"]}`: {{Field: "friends", Message: "must hold exactly 2 emails"}},
+		`{"friends":["request","request"]}`: {
+			{Field: "friends[0]", Message: "must be a valid email"},
+			{Field: "friends[1]", Message: "must be a valid email"},
+			{Field: "friends[1]", Message: "must differ from friends[0]"}},
+		`{"friends":["request@email.com","Request@Email.com"]}`: {{Field: "friends[1]", Message: "must differ from friends[0]"}},
+	}
+	for request, expectedDetails := range invalidRequests {
+
+		var jsonStr = []byte(request)
+
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &services.RelationshipServiceMock{}, IUserService: &services.UserServiceMock{}}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		relationshipEndpoint.CreateRelationship(c)
+
+		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
+		assert.Equal(t, expectedDetails, actualResult.Details, request)
 	}
 }
 
@@ -71,21 +107,9 @@ func TestCreateRelationshipWithNotFoundAccounts(t *testing.T) {
 		relationshipServiceMock := services.RelationshipServiceMock{}
 		userServiceMock := services.UserServiceMock{}
 
-		if i == 0 {
-			userRepositoryMock := data.UserRepositoryMock{}
-			userRepositoryMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-
-			userServiceMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-		} else {
-			userRepositoryMock := data.UserRepositoryMock{}
-			userRepositoryMock.On("CheckUserExist", friendCheckObj.Friends[0]).Return(int64(1))
-			userRepositoryMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-
-			userServiceMock.On("CheckUserExist", friendCheckObj.Friends[0]).Return(int64(1))
-			userServiceMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-		}
+		relationshipServiceMock.On("Befriend", friendCheckObj.Friends[0], friendCheckObj.Friends[1]).Return(services.Outcome(""), &data.NotFoundError{Entity: "user", Key: undefinedEmail})
 
-		relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
@@ -93,7 +117,7 @@ func TestCreateRelationshipWithNotFoundAccounts(t *testing.T) {
 
 		relationshipEndpoint.CreateRelationship(c)
 
-		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+		assert.Equal(t, w.Result().StatusCode, http.StatusNotFound)
 
 		var actualResult models.Failure
 		body, _ := ioutil.ReadAll(w.Result().Body)
@@ -101,37 +125,19 @@ func TestCreateRelationshipWithNotFoundAccounts(t *testing.T) {
 
 		assert.Equal(t, false, actualResult.Success)
 		assert.Equal(t, fmt.Sprintf("Invalid request: User name %s is not found", undefinedEmail), actualResult.Message)
+		assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
 	}
 }
 
 func TestCreateRelationshipForAlreadyConnectedAccounts(t *testing.T) {
 	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
 
-	friendCheckObj := models.FriendCheck{}
-	json.Unmarshal(jsonStr, &friendCheckObj)
-
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
 	relationshipServiceMock := services.RelationshipServiceMock{}
 	userServiceMock := services.UserServiceMock{}
 
-	requestUser := friendCheckObj.Friends[0]
-	requestUserId := int64(1)
-	targetUser := friendCheckObj.Friends[1]
-	targetUserId := int64(2)
-	status := int64(1)
-	relationshipId := int64(1)
-
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
+	relationshipServiceMock.On("Befriend", "email@request.com", "email@target.com").Return(services.Outcome(""), &services.RelationshipError{Code: models.CodeAlreadyConnected, Message: "connected status is existed"})
 
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
-
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, status).Return([]int64{relationshipId})
-	relationshipServiceMock.On("CheckConnected", requestUserId, targetUserId).Return([]int64{relationshipId})
-
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
@@ -147,41 +153,18 @@ func TestCreateRelationshipForAlreadyConnectedAccounts(t *testing.T) {
 
 	assert.Equal(t, false, actualResult.Success)
 	assert.Equal(t, "Invalid request: connected status is existed", actualResult.Message)
+	assert.Equal(t, models.CodeAlreadyConnected, actualResult.Code)
 }
 
 func TestCreateRelationshipForAlreadyBlockedAccounts(t *testing.T) {
 	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
 
-	friendCheckObj := models.FriendCheck{}
-	json.Unmarshal(jsonStr, &friendCheckObj)
-
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
 	relationshipServiceMock := services.RelationshipServiceMock{}
 	userServiceMock := services.UserServiceMock{}
 
-	requestUser := friendCheckObj.Friends[0]
-	requestUserId := int64(1)
-	targetUser := friendCheckObj.Friends[1]
-	targetUserId := int64(2)
-	connectedStatus := int64(1)
-	blockedStatus := int64(2)
-	connectedIds := []int64{}
-	blockedIds := []int64{int64(1)}
-
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
+	relationshipServiceMock.On("Befriend", "email@request.com", "email@target.com").Return(services.Outcome(""), &services.RelationshipError{Code: models.CodeBlocked, Message: "blocked status is existed"})
 
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
-
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, connectedStatus).Return(connectedIds)
-	relationshipServiceMock.On("CheckConnected", requestUserId, targetUserId).Return(connectedIds)
-
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, blockedStatus).Return(blockedIds)
-	relationshipServiceMock.On("CheckFullyBlocked", requestUserId, targetUserId).Return(blockedIds)
-
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
@@ -197,53 +180,72 @@ func TestCreateRelationshipForAlreadyBlockedAccounts(t *testing.T) {
 
 	assert.Equal(t, false, actualResult.Success)
 	assert.Equal(t, "Invalid request: blocked status is existed", actualResult.Message)
+	assert.Equal(t, models.CodeBlocked, actualResult.Code)
 }
 
 func TestCreateRelationshipForAlreadySubcribedAccounts(t *testing.T) {
 	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
 
-	friendCheckObj := models.FriendCheck{}
-	json.Unmarshal(jsonStr, &friendCheckObj)
+	relationshipEndpoint, repositories := newMemoryRelationshipEndpoint()
+	repositories.IRelationshipRepository.CreateRelationship(&models.Relationship{RequestUserId: 1, TargetUserId: 2, Status: models.RelationshipSubscribed})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.CreateRelationship(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.Success
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, true, actualResult.Success)
+	// Adding a friend sends a friend request, the subscription becomes a friend connection once it's accepted.
+	requests, _, _ := repositories.IRelationshipRepository.GetOutgoingFriendRequests(1, models.Page{Limit: 20})
+	assert.Equal(t, []string{"email@target.com"}, requests)
+	following, _, _ := repositories.IRelationshipRepository.GetFollowing(1, models.Page{Limit: 20})
+	assert.Equal(t, []string{"email@target.com"}, following)
+}
+
+func TestCreateRelationshipForPendingRequest(t *testing.T) {
+	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
 
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
 	relationshipServiceMock := services.RelationshipServiceMock{}
 	userServiceMock := services.UserServiceMock{}
 
-	requestUser := friendCheckObj.Friends[0]
-	requestUserId := int64(1)
-	targetUser := friendCheckObj.Friends[1]
-	targetUserId := int64(2)
-	connectedStatus := int64(1)
-	blockedStatus := int64(3)
-	subcribedStatus := int64(2)
-	connectedIds := []int64{}
-	blockedIds := []int64{}
-	subcribedIds := []int64{int64(1)}
+	relationshipServiceMock.On("Befriend", "email@request.com", "email@target.com").Return(services.Outcome(""), &services.RelationshipError{Code: models.CodeRequestPending, Message: "pending request is existed"})
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
+	relationshipEndpoint.CreateRelationship(c)
 
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
+	assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
 
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, connectedStatus).Return(connectedIds)
-	relationshipServiceMock.On("CheckConnected", requestUserId, targetUserId).Return(connectedIds)
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
 
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, blockedStatus).Return(blockedIds)
-	relationshipServiceMock.On("CheckFullyBlocked", requestUserId, targetUserId).Return(blockedIds)
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, "Invalid request: pending request is existed", actualResult.Message)
+	assert.Equal(t, models.CodeRequestPending, actualResult.Code)
+}
 
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, subcribedStatus).Return(subcribedIds)
-	relationshipServiceMock.On("CheckFullySubcribed", requestUserId, targetUserId).Return(subcribedIds)
+func TestCreateRelationshipSendsFriendRequest(t *testing.T) {
+	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
 
-	relationshipRepositoryMock.On("DeleteRelationships", subcribedIds).Return(true)
-	relationshipServiceMock.On("DeleteRelationships", subcribedIds).Return(true)
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
 
-	relationshipModel := models.Relationship{Status: connectedStatus, RequestUserId: requestUserId, TargetUserId: targetUserId}
-	relationshipRepositoryMock.On("CreateRelationship", &relationshipModel).Return(int64(10))
-	relationshipServiceMock.On("CreateRelationship", &relationshipModel).Return(int64(10))
+	relationshipServiceMock.On("Befriend", "email@request.com", "email@target.com").Return(services.FriendRequestSent, nil)
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
@@ -258,50 +260,58 @@ func TestCreateRelationshipForAlreadySubcribedAccounts(t *testing.T) {
 	json.Unmarshal(body, &actualResult)
 
 	assert.Equal(t, true, actualResult.Success)
+	relationshipServiceMock.AssertExpectations(t)
 }
 
-func TestCreateRelationshipReturnInternalError(t *testing.T) {
+// newMemoryRelationshipEndpoint returns a RelationshipEndpoint running the relationship operations on memory
+// repositories, where email@request.com and email@target.com are the users 1 and 2.
+func newMemoryRelationshipEndpoint() (endpoints.RelationshipEndpoint, data.Repositories) {
+	repositories := data.NewMemoryRepositories(data.NewMemoryStore())
+	for _, email := range []string{"email@request.com", "email@target.com"} {
+		repositories.IUserRepository.Create(email, time.Now())
+	}
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+	userService := services.UserService{IUserRepository: repositories.IUserRepository, IUnitOfWork: repositories.IUnitOfWork}
+
+	return endpoints.RelationshipEndpoint{IRelationshipService: relationshipService, IUserService: userService}, repositories
+}
+
+func TestCreateRelationshipAcceptsIncomingRequest(t *testing.T) {
 	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
 
-	friendCheckObj := models.FriendCheck{}
-	json.Unmarshal(jsonStr, &friendCheckObj)
+	relationshipEndpoint, repositories := newMemoryRelationshipEndpoint()
+	repositories.IRelationshipRepository.CreateRelationship(&models.Relationship{RequestUserId: 2, TargetUserId: 1, Status: models.RelationshipPending})
 
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
-	relationshipServiceMock := services.RelationshipServiceMock{}
-	userServiceMock := services.UserServiceMock{}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-	requestUser := friendCheckObj.Friends[0]
-	requestUserId := int64(1)
-	targetUser := friendCheckObj.Friends[1]
-	targetUserId := int64(2)
-	connectedStatus := int64(1)
-	blockedStatus := int64(3)
-	subcribedStatus := int64(2)
-	connectedIds := []int64{}
-	blockedIds := []int64{}
-	subcribedIds := []int64{}
+	relationshipEndpoint.CreateRelationship(c)
 
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
 
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
+	var actualResult models.Success
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
 
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, connectedStatus).Return(connectedIds)
-	relationshipServiceMock.On("CheckConnected", requestUserId, targetUserId).Return(connectedIds)
+	assert.Equal(t, true, actualResult.Success)
+	friends, _, _ := repositories.IRelationshipRepository.GetFriendList(1, models.Page{Limit: 20})
+	assert.Equal(t, []string{"email@target.com"}, friends)
+	requests, _, _ := repositories.IRelationshipRepository.GetIncomingFriendRequests(1, models.Page{Limit: 20})
+	assert.Empty(t, requests)
+}
 
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, blockedStatus).Return(blockedIds)
-	relationshipServiceMock.On("CheckFullyBlocked", requestUserId, targetUserId).Return(blockedIds)
+func TestCreateRelationshipReturnInternalError(t *testing.T) {
+	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
 
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, subcribedStatus).Return(subcribedIds)
-	relationshipServiceMock.On("CheckFullySubcribed", requestUserId, targetUserId).Return(subcribedIds)
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
 
-	relationshipModel := models.Relationship{Status: connectedStatus, RequestUserId: requestUserId, TargetUserId: targetUserId}
-	relationshipRepositoryMock.On("CreateRelationship", &relationshipModel).Return(int64(-1))
-	relationshipServiceMock.On("CreateRelationship", &relationshipModel).Return(int64(-1))
+	relationshipServiceMock.On("Befriend", "email@request.com", "email@target.com").Return(services.Outcome(""), errors.New("connection refused"))
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
@@ -309,7 +319,7 @@ func TestCreateRelationshipReturnInternalError(t *testing.T) {
 
 	relationshipEndpoint.CreateRelationship(c)
 
-	assert.Equal(t, w.Result().StatusCode, http.StatusInternalServerError)
+	assert.Equal(t, w.Result().StatusCode, http.StatusServiceUnavailable)
 
 	var actualResult models.Failure
 	body, _ := ioutil.ReadAll(w.Result().Body)
@@ -317,236 +327,771 @@ func TestCreateRelationshipReturnInternalError(t *testing.T) {
 
 	assert.Equal(t, false, actualResult.Success)
 	assert.Equal(t, "Oops! There is an error, please try again.", actualResult.Message)
+	assert.Equal(t, models.CodeServiceUnavailable, actualResult.Code)
 }
 
-func TestFriendListWithInvalidAccount(t *testing.T) {
-	var invalidRequests = []string{
-		`{"email":}`,
-		`{"email":"almost@valid.email'"}`,
-		`{"email":"invalid_email"}`}
-	for _, request := range invalidRequests {
+func TestIncomingFriendRequestsReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"email":"email@target.com"}`)
 
-		var jsonStr = []byte(request)
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
 
-		relationshipServiceMock := services.RelationshipServiceMock{}
-		userServiceMock := services.UserServiceMock{}
+	requests := []string{"user1@email.com", "user2@email.com"}
 
-		relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request, _ = http.NewRequest("POST", "/friends", bytes.NewBuffer(jsonStr))
-		c.Request.Header.Set("Content-Type", "application/json")
+	userServiceMock.On("CheckUserExist", "email@target.com").Return(int64(2), nil)
+	relationshipServiceMock.On("GetIncomingFriendRequests", int64(2), models.Page{Limit: 20}).Return(requests, int64(0), nil)
 
-		relationshipEndpoint.FriendList(c)
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/requests/incoming", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+	relationshipEndpoint.IncomingFriendRequests(c)
 
-		var actualResult models.Failure
-		body, _ := ioutil.ReadAll(w.Result().Body)
-		json.Unmarshal(body, &actualResult)
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
 
-		assert.Equal(t, false, actualResult.Success)
-		assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
-	}
-}
+	var actualResult models.FriendRequest
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
 
-func TestFriendListWithNotFoundAccount(t *testing.T) {
-	var jsonStr = []byte(`{"email":"not@found.account"}`)
+	assert.Equal(t, true, actualResult.Success)
+	assert.Equal(t, 2, actualResult.Count)
+	assert.Equal(t, requests, actualResult.Requests)
+}
 
-	email := models.Email{}
-	json.Unmarshal(jsonStr, &email)
+func TestOutgoingFriendRequestsWithNotFoundAccount(t *testing.T) {
+	var jsonStr = []byte(`{"email":"email@notfound.com"}`)
 
 	relationshipServiceMock := services.RelationshipServiceMock{}
 	userServiceMock := services.UserServiceMock{}
-	userServiceMock.On("CheckUserExist", email.Email).Return(int64(-1))
 
-	userRepositoryMock := data.UserRepositoryMock{}
-	userRepositoryMock.On("CheckUserExist", email.Email).Return(int64(-1))
+	userServiceMock.On("CheckUserExist", "email@notfound.com").Return(int64(0), &data.NotFoundError{Entity: "user", Key: "email@notfound.com"})
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
+	c.Request, _ = http.NewRequest("POST", "/friends/requests/outgoing", bytes.NewBuffer(jsonStr))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	relationshipEndpoint.FriendList(c)
+	relationshipEndpoint.OutgoingFriendRequests(c)
 
-	assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+	assert.Equal(t, w.Result().StatusCode, http.StatusNotFound)
 
 	var actualResult models.Failure
 	body, _ := ioutil.ReadAll(w.Result().Body)
 	json.Unmarshal(body, &actualResult)
 
 	assert.Equal(t, false, actualResult.Success)
-	assert.Equal(t, fmt.Sprintf("Invalid request: User name %s is not found", email.Email), actualResult.Message)
+	assert.Equal(t, "Invalid request: User name email@notfound.com is not found", actualResult.Message)
+	assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
 }
 
-func TestFriendListWithValidAccount(t *testing.T) {
-	var jsonStr = []byte(`{"email":"not@found.account"}`)
+func TestFriendSuggestionsWithInvalidLimit(t *testing.T) {
+	var jsonStr = []byte(`{"email":"email@target.com","limit":101}`)
 
-	email := models.Email{}
-	json.Unmarshal(jsonStr, &email)
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &services.RelationshipServiceMock{}, IUserService: &services.UserServiceMock{}}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/suggestions", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-	relationshipServiceMock := services.RelationshipServiceMock{}
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipEndpoint.FriendSuggestions(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
+	assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
+}
 
+func TestFriendSuggestionsReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"email":"email@target.com"}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
 	userServiceMock := services.UserServiceMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
 
-	userServiceMock.On("CheckUserExist", email.Email).Return(int64(1))
-	userRepositoryMock.On("CheckUserExist", email.Email).Return(int64(1))
+	suggestions := []models.SuggestedFriend{{Email: "user1@email.com", MutualFriends: 2}, {Email: "user2@email.com", MutualFriends: 1}}
 
-	friendList := []string{"user1@email.com", "user2@email.com"}
-	relationshipServiceMock.On("GetFriendList", int64(1)).Return(friendList)
-	relationshipRepositoryMock.On("GetFriendList", int64(1)).Return(friendList)
+	userServiceMock.On("CheckUserExist", "email@target.com").Return(int64(2), nil)
+	relationshipServiceMock.On("GetFriendSuggestions", int64(2), 10).Return(suggestions, nil)
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
+	c.Request, _ = http.NewRequest("POST", "/friends/suggestions", bytes.NewBuffer(jsonStr))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	relationshipEndpoint.FriendList(c)
+	relationshipEndpoint.FriendSuggestions(c)
 
 	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
 
-	var actualResult models.Success
+	var actualResult models.Suggestion
 	body, _ := ioutil.ReadAll(w.Result().Body)
 	json.Unmarshal(body, &actualResult)
 
 	assert.Equal(t, true, actualResult.Success)
+	assert.Equal(t, 2, actualResult.Count)
+	assert.Equal(t, suggestions, actualResult.Suggestions)
 }
 
-func TestCommonFriendListWithInvalidAccounts(t *testing.T) {
-	var invalidRequests = []string{
-		`{"friends":"target@email.com"}`,
-		`{"friends":["request","target@email.com"]}`,
-		`{"friends":["target@email.com","request"]}`,
-		`{"friends":["target@email.com"]}`,
-		`{"friends":["request@email.com","request@email.com"]}`,
-		`{"friends":["request@email.com","target@email.com","unknown@email.com"]}`}
-	for _, request := range invalidRequests {
+func TestAcceptFriendRequestWithoutPendingRequest(t *testing.T) {
+	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-		var jsonStr = []byte(request)
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
 
-		relationshipServiceMock := services.RelationshipServiceMock{}
-		userServiceMock := services.UserServiceMock{}
+	relationshipServiceMock.On("AcceptFriendRequest", "email@request.com", "email@target.com").Return(services.Outcome(""), &services.RelationshipError{Code: models.CodeRequestNotFound, Message: "pending request is not existed"})
 
-		relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request, _ = http.NewRequest("POST", "/friends/common-friends", bytes.NewBuffer(jsonStr))
-		c.Request.Header.Set("Content-Type", "application/json")
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/accept", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-		relationshipEndpoint.CommonFriendList(c)
+	relationshipEndpoint.AcceptFriendRequest(c)
 
-		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+	assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
 
-		var actualResult models.Failure
-		body, _ := ioutil.ReadAll(w.Result().Body)
-		json.Unmarshal(body, &actualResult)
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
 
-		assert.Equal(t, false, actualResult.Success)
-		assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
-	}
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, "Invalid request: pending request is not existed", actualResult.Message)
+	assert.Equal(t, models.CodeRequestNotFound, actualResult.Code)
 }
 
-func TestCommonFriendListWithNotFoundAccounts(t *testing.T) {
-	var invalidRequests = []string{
-		`{"friends":["undefined@request.com","email@target.com"]}`,
-		`{"friends":["email@request.com","undefined@target.com"]}`}
+func TestAcceptFriendRequestWithBlockedAccounts(t *testing.T) {
+	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-	for i, request := range invalidRequests {
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
 
-		var jsonStr = []byte(request)
+	relationshipServiceMock.On("AcceptFriendRequest", "email@request.com", "email@target.com").Return(services.Outcome(""), &services.RelationshipError{Code: models.CodeBlocked, Message: "blocked status is existed"})
 
-		friendCheckObj := models.FriendCheck{}
-		json.Unmarshal(jsonStr, &friendCheckObj)
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/accept", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-		var undefinedEmail = friendCheckObj.Friends[0]
-		if i == 1 {
-			undefinedEmail = friendCheckObj.Friends[1]
-		}
+	relationshipEndpoint.AcceptFriendRequest(c)
 
-		relationshipServiceMock := services.RelationshipServiceMock{}
-		userServiceMock := services.UserServiceMock{}
+	assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
 
-		if i == 0 {
-			userRepositoryMock := data.UserRepositoryMock{}
-			userRepositoryMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
 
-			userServiceMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-		} else {
-			userRepositoryMock := data.UserRepositoryMock{}
-			userRepositoryMock.On("CheckUserExist", friendCheckObj.Friends[0]).Return(int64(1))
-			userRepositoryMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, "Invalid request: blocked status is existed", actualResult.Message)
+	assert.Equal(t, models.CodeBlocked, actualResult.Code)
+}
 
-			userServiceMock.On("CheckUserExist", friendCheckObj.Friends[0]).Return(int64(1))
-			userServiceMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-		}
+func TestAcceptFriendRequestWithAlreadySubcribedAccounts(t *testing.T) {
+	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-		relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
-		w := httptest.NewRecorder()
+	relationshipEndpoint, repositories := newMemoryRelationshipEndpoint()
+	repositories.IRelationshipRepository.CreateRelationship(&models.Relationship{RequestUserId: 2, TargetUserId: 1, Status: models.RelationshipSubscribed})
+	repositories.IRelationshipRepository.CreateRelationship(&models.Relationship{RequestUserId: 2, TargetUserId: 1, Status: models.RelationshipPending})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/accept", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.AcceptFriendRequest(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.Success
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, true, actualResult.Success)
+	// The friend connection replaces the subscription of the target.
+	friends, _, _ := repositories.IRelationshipRepository.GetFriendList(1, models.Page{Limit: 20})
+	assert.Equal(t, []string{"email@target.com"}, friends)
+	followers, _, _ := repositories.IRelationshipRepository.GetFollowers(1, models.Page{Limit: 20})
+	assert.Empty(t, followers)
+}
+
+func TestRejectFriendRequestReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+
+	relationshipServiceMock.On("RejectFriendRequest", "email@request.com", "email@target.com").Return(services.FriendRequestRejected, nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/reject", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.RejectFriendRequest(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.Success
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, true, actualResult.Success)
+	relationshipServiceMock.AssertExpectations(t)
+}
+
+func TestCancelFriendRequestReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+
+	relationshipServiceMock.On("CancelFriendRequest", "email@request.com", "email@target.com").Return(services.FriendRequestCanceled, nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/cancel", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.CancelFriendRequest(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.Success
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, true, actualResult.Success)
+	relationshipServiceMock.AssertExpectations(t)
+}
+
+func TestFriendListWithInvalidAccount(t *testing.T) {
+	var invalidRequests = []string{
+		`{"email":}`,
+		`{"email":"almost@valid.email'"}`,
+		`{"email":"invalid_email"}`}
+	for _, request := range invalidRequests {
+
+		var jsonStr = []byte(request)
+
+		relationshipServiceMock := services.RelationshipServiceMock{}
+		userServiceMock := services.UserServiceMock{}
+
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/friends", bytes.NewBuffer(jsonStr))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		relationshipEndpoint.FriendList(c)
+
+		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, false, actualResult.Success)
+		assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
+	}
+}
+
+func TestFriendListWithNotFoundAccount(t *testing.T) {
+	var jsonStr = []byte(`{"email":"not@found.account"}`)
+
+	email := models.Email{}
+	json.Unmarshal(jsonStr, &email)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("CheckUserExist", email.Email).Return(int64(0), &data.NotFoundError{Entity: "user", Key: email.Email})
+
+	userRepositoryMock := data.UserRepositoryMock{}
+	userRepositoryMock.On("CheckUserExist", email.Email).Return(int64(0), &data.NotFoundError{Entity: "user", Key: email.Email})
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.FriendList(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusNotFound)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, fmt.Sprintf("Invalid request: User name %s is not found", email.Email), actualResult.Message)
+	assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
+}
+
+func TestFriendListWithValidAccount(t *testing.T) {
+	var jsonStr = []byte(`{"email":"not@found.account"}`)
+
+	email := models.Email{}
+	json.Unmarshal(jsonStr, &email)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+
+	userServiceMock := services.UserServiceMock{}
+	userRepositoryMock := data.UserRepositoryMock{}
+
+	userServiceMock.On("CheckUserExist", email.Email).Return(int64(1), nil)
+	userRepositoryMock.On("CheckUserExist", email.Email).Return(int64(1), nil)
+
+	friendList := []string{"user1@email.com", "user2@email.com"}
+	relationshipServiceMock.On("GetFriendList", int64(1), models.Page{Limit: 20}).Return(friendList, int64(0), nil)
+	relationshipRepositoryMock.On("GetFriendList", int64(1), models.Page{Limit: 20}).Return(friendList, int64(0), nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/add", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.FriendList(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.Success
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, true, actualResult.Success)
+}
+
+func TestFriendListWithUnavailableStorage(t *testing.T) {
+	var jsonStr = []byte(`{"email":"andy@example.com"}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+
+	userServiceMock.On("CheckUserExist", "andy@example.com").Return(int64(1), nil)
+	relationshipServiceMock.On("GetFriendList", int64(1), models.Page{Limit: 20}).Return([]string(nil), int64(0), errors.New("connection refused"))
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.FriendList(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusServiceUnavailable)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, "Oops! There is an error, please try again.", actualResult.Message)
+	assert.Equal(t, models.CodeServiceUnavailable, actualResult.Code)
+}
+
+func TestCommonFriendListWithInvalidAccounts(t *testing.T) {
+	var invalidRequests = []string{
+		`{"friends":"target@email.com"}`,
+		`{"friends":["request","target@email.com"]}`,
+		`{"friends":["target@email.com","request"]}`,
+		`{"friends":["target@email.com"]}`,
+		`{"friends":["request@email.com","request@email.com"]}`,
+		`{"friends":["request@email.com","target@email.com","Request@Email.com"]}`}
+	for _, request := range invalidRequests {
+
+		var jsonStr = []byte(request)
+
+		relationshipServiceMock := services.RelationshipServiceMock{}
+		userServiceMock := services.UserServiceMock{}
+
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/friends/common-friends", bytes.NewBuffer(jsonStr))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		relationshipEndpoint.CommonFriendList(c)
+
+		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, false, actualResult.Success)
+		assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
+	}
+}
+
+func TestCommonFriendListWithNotFoundAccounts(t *testing.T) {
+	var invalidRequests = []string{
+		`{"friends":["undefined@request.com","email@target.com"]}`,
+		`{"friends":["email@request.com","undefined@target.com"]}`}
+
+	for i, request := range invalidRequests {
+
+		var jsonStr = []byte(request)
+
+		friendCheckObj := models.FriendCheck{}
+		json.Unmarshal(jsonStr, &friendCheckObj)
+
+		var undefinedEmail = friendCheckObj.Friends[0]
+		if i == 1 {
+			undefinedEmail = friendCheckObj.Friends[1]
+		}
+
+		relationshipServiceMock := services.RelationshipServiceMock{}
+		userServiceMock := services.UserServiceMock{}
+
+		if i == 0 {
+			userServiceMock.On("GetUsers", friendCheckObj.Friends).Return([]models.User{{ID: 2, Email: friendCheckObj.Friends[1]}}, nil)
+		} else {
+			userServiceMock.On("GetUsers", friendCheckObj.Friends).Return([]models.User{{ID: 1, Email: friendCheckObj.Friends[0]}}, nil)
+		}
+
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/friends/common-friends", bytes.NewBuffer(jsonStr))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		relationshipEndpoint.CommonFriendList(c)
+
+		assert.Equal(t, w.Result().StatusCode, http.StatusNotFound)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, false, actualResult.Success)
+		assert.Equal(t, fmt.Sprintf("Invalid request: User name %s is not found", undefinedEmail), actualResult.Message)
+		assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
+		assert.Equal(t, []models.FieldError{{Field: fmt.Sprintf("friends[%d]", i), Message: "must be an existing user"}}, actualResult.Details)
+	}
+}
+
+func TestCommonFriendListReportsEveryNotFoundAccount(t *testing.T) {
+	var jsonStr = []byte(`{"friends":["undefined@request.com","email@target.com","Undefined@Other.com"]}`)
+
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("GetUsers", []string{"undefined@request.com", "email@target.com", "Undefined@Other.com"}).Return([]models.User{{ID: 2, Email: "email@target.com"}}, nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &services.RelationshipServiceMock{}, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/common-friends", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.CommonFriendList(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusNotFound)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, "Invalid request: User names undefined@request.com, Undefined@Other.com are not found", actualResult.Message)
+	assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
+	assert.Equal(t, []models.FieldError{
+		{Field: "friends[0]", Message: "must be an existing user"},
+		{Field: "friends[2]", Message: "must be an existing user"}}, actualResult.Details)
+	userServiceMock.AssertNumberOfCalls(t, "GetUsers", 1)
+}
+
+func TestCommonFriendListWhichFriendsReturn(t *testing.T) {
+	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
+
+	friendCheckObj := models.FriendCheck{}
+	json.Unmarshal(jsonStr, &friendCheckObj)
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+
+	requestUser := friendCheckObj.Friends[0]
+	requestUserId := int64(1)
+	targetUser := friendCheckObj.Friends[1]
+	targetUserId := int64(2)
+
+	userServiceMock.On("GetUsers", friendCheckObj.Friends).Return([]models.User{{ID: requestUserId, Email: requestUser}, {ID: targetUserId, Email: targetUser}}, nil)
+
+	friendList := []string{"user1@email.com", "user2@email.com"}
+	relationshipServiceMock.On("GetCommonFriendList", []int64{requestUserId, targetUserId}, models.Page{Limit: 20}).Return(friendList, int64(0), nil)
+	relationshipRepositoryMock.On("GetCommonFriendList", []int64{requestUserId, targetUserId}, models.Page{Limit: 20}).Return(friendList, int64(0), nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/common-friends", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.CommonFriendList(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.Friend
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, true, actualResult.Success)
+	assert.Equal(t, len(friendList), actualResult.Count)
+	assert.Equal(t, friendList, actualResult.Friends)
+}
+
+func TestCommonFriendListReportsInvalidFields(t *testing.T) {
+	tooManyFriends, _ := json.Marshal(map[string][]string{"friends": make([]string, 51)})
+	var invalidRequests = map[string][]models.FieldError{
+		`{"friends":["target@email.com"]}`: {{Field: "friends", Message: "must hold between 2 and 50 emails"}},
+		string(tooManyFriends):             {{Field: "friends", Message: "must hold between 2 and 50 emails"}},
+		`{"friends":["a@email.com","b","A@email.com","b"]}`: {
+			{Field: "friends[1]", Message: "must be a valid email"},
+			{Field: "friends[2]", Message: "must differ from friends[0]"},
+			{Field: "friends[3]", Message: "must be a valid email"},
+			{Field: "friends[3]", Message: "must differ from friends[1]"}},
+	}
+	for request, expectedDetails := range invalidRequests {
+
+		var jsonStr = []byte(request)
+
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &services.RelationshipServiceMock{}, IUserService: &services.UserServiceMock{}}
+		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Request, _ = http.NewRequest("POST", "/friends/common-friends", bytes.NewBuffer(jsonStr))
 		c.Request.Header.Set("Content-Type", "application/json")
 
-		relationshipEndpoint.CommonFriendList(c)
+		relationshipEndpoint.CommonFriendList(c)
+
+		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
+		assert.Equal(t, expectedDetails, actualResult.Details, request)
+	}
+}
+
+func TestCommonFriendListOfManyUsers(t *testing.T) {
+	var jsonStr = []byte(`{"friends":["a@email.com","b@email.com","c@email.com"]}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+
+	userServiceMock.On("GetUsers", []string{"a@email.com", "b@email.com", "c@email.com"}).Return([]models.User{
+		{ID: 1, Email: "a@email.com"}, {ID: 2, Email: "b@email.com"}, {ID: 3, Email: "c@email.com"}}, nil)
+	relationshipServiceMock.On("GetCommonFriendList", []int64{1, 2, 3}, models.Page{Limit: 20}).Return([]string{"d@email.com"}, int64(0), nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/common-friends", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.CommonFriendList(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.Friend
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.Friend{Friends: []string{"d@email.com"}, Count: 1, Success: true}, actualResult)
+	relationshipServiceMock.AssertExpectations(t)
+}
+
+func TestCommonFriendMatrixWithNotFoundAccount(t *testing.T) {
+	var jsonStr = []byte(`{"users":["a@email.com","undefined@email.com"]}`)
+
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("GetUsers", []string{"a@email.com", "undefined@email.com"}).Return([]models.User{{ID: 1, Email: "a@email.com"}}, nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &services.RelationshipServiceMock{}, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/common-friends/matrix", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.CommonFriendMatrix(c)
 
-		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+	assert.Equal(t, w.Result().StatusCode, http.StatusNotFound)
 
-		var actualResult models.Failure
-		body, _ := ioutil.ReadAll(w.Result().Body)
-		json.Unmarshal(body, &actualResult)
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
 
-		assert.Equal(t, false, actualResult.Success)
-		assert.Equal(t, fmt.Sprintf("Invalid request: User name %s is not found", undefinedEmail), actualResult.Message)
-	}
+	assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
 }
 
-func TestCommonFriendListWhichFriendsReturn(t *testing.T) {
-	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
+func TestCommonFriendMatrixReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"users":["a@email.com","b@email.com","c@email.com"]}`)
 
-	friendCheckObj := models.FriendCheck{}
-	json.Unmarshal(jsonStr, &friendCheckObj)
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+
+	userServiceMock.On("GetUsers", []string{"a@email.com", "b@email.com", "c@email.com"}).Return([]models.User{
+		{ID: 1, Email: "a@email.com"}, {ID: 2, Email: "b@email.com"}, {ID: 3, Email: "c@email.com"}}, nil)
+	relationshipServiceMock.On("CountCommonFriends", []int64{1, 2, 3}).Return(map[int64]map[int64]int{1: {2: 3, 3: 1}, 2: {1: 2}}, nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/common-friends/matrix", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.CommonFriendMatrix(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.CommonFriendMatrix
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.CommonFriendMatrix{
+		Users:   []string{"a@email.com", "b@email.com", "c@email.com"},
+		Counts:  [][]int{{0, 3, 1}, {2, 0, 0}, {0, 0, 0}},
+		Success: true,
+	}, actualResult)
+	relationshipServiceMock.AssertExpectations(t)
+}
+
+func TestFriendPathWithNotConnectedAccounts(t *testing.T) {
+	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"],"maxDepth":3}`)
 
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
 	relationshipServiceMock := services.RelationshipServiceMock{}
 	userServiceMock := services.UserServiceMock{}
 
-	requestUser := friendCheckObj.Friends[0]
-	requestUserId := int64(1)
-	targetUser := friendCheckObj.Friends[1]
-	targetUserId := int64(2)
+	userServiceMock.On("CheckUserExist", "email@request.com").Return(int64(1), nil)
+	userServiceMock.On("CheckUserExist", "email@target.com").Return(int64(2), nil)
+	relationshipServiceMock.On("GetShortestPath", int64(1), int64(2), 3).Return([]int64(nil), nil)
 
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/path", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
+	relationshipEndpoint.FriendPath(c)
 
-	friendList := []string{"user1@email.com", "user2@email.com"}
-	relationshipServiceMock.On("GetCommonFriendList", requestUserId, targetUserId).Return(friendList)
-	relationshipRepositoryMock.On("GetCommonFriendList", requestUserId, targetUserId).Return(friendList)
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.FriendPath
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	assert.Equal(t, true, actualResult.Success)
+	assert.Equal(t, false, actualResult.Connected)
+	assert.Empty(t, actualResult.Path)
+}
+
+func TestFriendPathReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+
+	userServiceMock.On("CheckUserExist", "email@request.com").Return(int64(1), nil)
+	userServiceMock.On("CheckUserExist", "email@target.com").Return(int64(2), nil)
+	relationshipServiceMock.On("GetShortestPath", int64(1), int64(2), 6).Return([]int64{1, 4, 3, 2}, nil)
+	userServiceMock.On("GetEmails", []int64{4, 3}).Return(map[int64]string{3: "user3@email.com", 4: "user4@email.com"}, nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("POST", "/friends/common-friends", bytes.NewBuffer(jsonStr))
+	c.Request, _ = http.NewRequest("POST", "/friends/path", bytes.NewBuffer(jsonStr))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	relationshipEndpoint.CommonFriendList(c)
+	relationshipEndpoint.FriendPath(c)
 
 	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
 
-	var actualResult models.Friend
+	var actualResult models.FriendPath
 	body, _ := ioutil.ReadAll(w.Result().Body)
 	json.Unmarshal(body, &actualResult)
 
 	assert.Equal(t, true, actualResult.Success)
-	assert.Equal(t, len(friendList), actualResult.Count)
-	assert.Equal(t, friendList, actualResult.Friends)
+	assert.Equal(t, true, actualResult.Connected)
+	assert.Equal(t, []string{"user4@email.com", "user3@email.com"}, actualResult.Path)
+	assert.Equal(t, 3, actualResult.Length)
+}
+
+func TestRelationshipStatusWithNotFoundAccount(t *testing.T) {
+	var jsonStr = []byte(`{"requestor":"email@request.com","target":"undefined@target.com"}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+
+	userServiceMock.On("CheckUserExist", "email@request.com").Return(int64(1), nil)
+	userServiceMock.On("CheckUserExist", "undefined@target.com").Return(int64(0), &data.NotFoundError{Entity: "user", Key: "undefined@target.com"})
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/relationships/status", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.RelationshipStatus(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusNotFound)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
+}
+
+func TestRelationshipStatusReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+
+	userServiceMock.On("CheckUserExist", "email@request.com").Return(int64(1), nil)
+	userServiceMock.On("CheckUserExist", "email@target.com").Return(int64(2), nil)
+	relationshipServiceMock.On("GetRelationshipView", int64(1), int64(2)).Return(models.RelationshipView{
+		Outgoing:   []models.RelationshipStatus{models.RelationshipSubscribed},
+		Incoming:   []models.RelationshipStatus{models.RelationshipBlocked},
+		Subscribed: true,
+		BlockedBy:  true,
+	}, nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/relationships/status", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.RelationshipStatus(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.RelationshipView
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Contains(t, string(body), `"outgoing":["subscribed"],"incoming":["blocked"]`)
+	assert.Equal(t, models.RelationshipView{
+		Outgoing:   []models.RelationshipStatus{models.RelationshipSubscribed},
+		Incoming:   []models.RelationshipStatus{models.RelationshipBlocked},
+		Subscribed: true,
+		BlockedBy:  true,
+		Success:    true,
+	}, actualResult)
 }
 
 func TestSubcribeWithInvalidAccounts(t *testing.T) {
@@ -563,7 +1108,7 @@ func TestSubcribeWithInvalidAccounts(t *testing.T) {
 		relationshipServiceMock := services.RelationshipServiceMock{}
 		userServiceMock := services.UserServiceMock{}
 
-		relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Request, _ = http.NewRequest("POST", "/friends/subcribe", bytes.NewBuffer(jsonStr))
@@ -579,6 +1124,7 @@ func TestSubcribeWithInvalidAccounts(t *testing.T) {
 
 		assert.Equal(t, false, actualResult.Success)
 		assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
 	}
 }
 
@@ -602,21 +1148,9 @@ func TestSubcribeUpdateWithNotFoundAccounts(t *testing.T) {
 		relationshipServiceMock := services.RelationshipServiceMock{}
 		userServiceMock := services.UserServiceMock{}
 
-		if i == 0 {
-			userRepositoryMock := data.UserRepositoryMock{}
-			userRepositoryMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-
-			userServiceMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-		} else {
-			userRepositoryMock := data.UserRepositoryMock{}
-			userRepositoryMock.On("CheckUserExist", userActionObj.Requestor).Return(int64(1))
-			userRepositoryMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-
-			userServiceMock.On("CheckUserExist", userActionObj.Requestor).Return(int64(1))
-			userServiceMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-		}
+		relationshipServiceMock.On("Subscribe", userActionObj.Requestor, userActionObj.Target).Return(services.Outcome(""), &data.NotFoundError{Entity: "user", Key: undefinedEmail})
 
-		relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Request, _ = http.NewRequest("POST", "/friends/subcribe", bytes.NewBuffer(jsonStr))
@@ -624,7 +1158,7 @@ func TestSubcribeUpdateWithNotFoundAccounts(t *testing.T) {
 
 		relationshipEndpoint.Subscribe(c)
 
-		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+		assert.Equal(t, w.Result().StatusCode, http.StatusNotFound)
 
 		var actualResult models.Failure
 		body, _ := ioutil.ReadAll(w.Result().Body)
@@ -632,37 +1166,19 @@ func TestSubcribeUpdateWithNotFoundAccounts(t *testing.T) {
 
 		assert.Equal(t, false, actualResult.Success)
 		assert.Equal(t, fmt.Sprintf("Invalid request: User name %s is not found", undefinedEmail), actualResult.Message)
+		assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
 	}
 }
 
 func TestSubcribeUpdateWithAlreadySubcribedAccounts(t *testing.T) {
 	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-	userActionObj := models.UserAction{}
-	json.Unmarshal(jsonStr, &userActionObj)
-
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
 	relationshipServiceMock := services.RelationshipServiceMock{}
 	userServiceMock := services.UserServiceMock{}
 
-	requestUser := userActionObj.Requestor
-	requestUserId := int64(1)
-	targetUser := userActionObj.Target
-	targetUserId := int64(2)
-	subcribedStatus := int64(2)
-	subcribedIds := []int64{int64(1)}
-
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
-
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
-
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, subcribedStatus).Return(subcribedIds)
-	relationshipServiceMock.On("CheckPartialSubcribed", requestUserId, targetUserId).Return(subcribedIds)
+	relationshipServiceMock.On("Subscribe", "email@request.com", "email@target.com").Return(services.Outcome(""), &services.RelationshipError{Code: models.CodeAlreadySubscribed, Message: "subcribed status is existed"})
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("POST", "/friends/subcribe", bytes.NewBuffer(jsonStr))
@@ -678,43 +1194,21 @@ func TestSubcribeUpdateWithAlreadySubcribedAccounts(t *testing.T) {
 
 	assert.Equal(t, false, actualResult.Success)
 	assert.Equal(t, "Invalid request: subcribed status is existed", actualResult.Message)
+	assert.Equal(t, models.CodeAlreadySubscribed, actualResult.Code)
 }
 
 func TestSubcribeUpdateWithAlreadyBlockedAccounts(t *testing.T) {
 	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-	userActionObj := models.UserAction{}
-	json.Unmarshal(jsonStr, &userActionObj)
-
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
 	relationshipServiceMock := services.RelationshipServiceMock{}
 	userServiceMock := services.UserServiceMock{}
 
-	requestUser := userActionObj.Requestor
-	requestUserId := int64(1)
-	targetUser := userActionObj.Target
-	targetUserId := int64(2)
 	//connectedStatus := int64(1)
-	blockedStatus := int64(3)
-	subcribedStatus := int64(2)
 	//connectedIds := []int64{}
-	blockedIds := []int64{int64(1)}
-	subcribedIds := []int64{}
-
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
-
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
-
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, subcribedStatus).Return(subcribedIds)
-	relationshipServiceMock.On("CheckPartialSubcribed", requestUserId, targetUserId).Return(subcribedIds)
 
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, blockedStatus).Return(blockedIds)
-	relationshipServiceMock.On("CheckPartialBlocked", requestUserId, targetUserId).Return(blockedIds)
+	relationshipServiceMock.On("Subscribe", "email@request.com", "email@target.com").Return(services.Outcome(""), &services.RelationshipError{Code: models.CodeBlocked, Message: "blocked status is existed"})
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("POST", "/friends/subcribe", bytes.NewBuffer(jsonStr))
@@ -730,46 +1224,42 @@ func TestSubcribeUpdateWithAlreadyBlockedAccounts(t *testing.T) {
 
 	assert.Equal(t, false, actualResult.Success)
 	assert.Equal(t, "Invalid request: blocked status is existed", actualResult.Message)
+	assert.Equal(t, models.CodeBlocked, actualResult.Code)
 }
 
 func TestSubcribeUpdateWithAlreadyConnectedAccounts(t *testing.T) {
 	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-	userActionObj := models.UserAction{}
-	json.Unmarshal(jsonStr, &userActionObj)
+	relationshipEndpoint, repositories := newMemoryRelationshipEndpoint()
+	repositories.IRelationshipRepository.CreateRelationship(&models.Relationship{RequestUserId: 2, TargetUserId: 1, Status: models.RelationshipFriend})
 
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
-	relationshipServiceMock := services.RelationshipServiceMock{}
-	userServiceMock := services.UserServiceMock{}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/subcribe", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-	requestUser := userActionObj.Requestor
-	requestUserId := int64(1)
-	targetUser := userActionObj.Target
-	targetUserId := int64(2)
-	connectedStatus := int64(1)
-	blockedStatus := int64(3)
-	subcribedStatus := int64(2)
-	connectedIds := []int64{int64(1)}
-	blockedIds := []int64{}
-	subcribedIds := []int64{}
+	relationshipEndpoint.Subscribe(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
 
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
+	var actualResult models.Success
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
 
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
+	assert.Equal(t, true, actualResult.Success)
+	following, _, _ := repositories.IRelationshipRepository.GetFollowing(1, models.Page{Limit: 20})
+	assert.Empty(t, following)
+}
 
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, subcribedStatus).Return(subcribedIds)
-	relationshipServiceMock.On("CheckPartialSubcribed", requestUserId, targetUserId).Return(subcribedIds)
+func TestSubcribeUpdateWhichOkResult(t *testing.T) {
+	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-	relationshipRepositoryMock.On("CheckRelationshipOnetWay", requestUserId, targetUserId, blockedStatus).Return(blockedIds)
-	relationshipServiceMock.On("CheckPartialBlocked", requestUserId, targetUserId).Return(blockedIds)
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
 
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, connectedStatus).Return(connectedIds)
-	relationshipServiceMock.On("CheckConnected", requestUserId, targetUserId).Return(connectedIds)
+	relationshipServiceMock.On("Subscribe", "email@request.com", "email@target.com").Return(services.Subscribed, nil)
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("POST", "/friends/subcribe", bytes.NewBuffer(jsonStr))
@@ -786,54 +1276,148 @@ func TestSubcribeUpdateWithAlreadyConnectedAccounts(t *testing.T) {
 	assert.Equal(t, true, actualResult.Success)
 }
 
-func TestSubcribeUpdateWhichOkResult(t *testing.T) {
+func TestBlockUpdateWithInvalidAccounts(t *testing.T) {
+	var invalidRequests = []string{
+		`{"requestor":"invalid_model`,
+		`{"requestor":"target@email.com"}`,
+		`{"requestor":"request","target":"target@email.com"}`,
+		`{"requestor":"target@email.com","target":"request"}`,
+		`{"requestor":"","target":""}`}
+	for _, request := range invalidRequests {
+
+		var jsonStr = []byte(request)
+
+		relationshipServiceMock := services.RelationshipServiceMock{}
+		userServiceMock := services.UserServiceMock{}
+
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/friends/block", bytes.NewBuffer(jsonStr))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		relationshipEndpoint.Block(c)
+
+		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, false, actualResult.Success)
+		assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
+	}
+}
+
+func TestBlockUpdateWithNotFoundAccounts(t *testing.T) {
+	var invalidRequests = []string{
+		`{"requestor":"request@notfound.com","target":"target@email.com"}`,
+		`{"requestor":"request@email.com","target":"target@notfound.com"}`}
+
+	for i, request := range invalidRequests {
+
+		var jsonStr = []byte(request)
+
+		userActionObj := models.UserAction{}
+		json.Unmarshal(jsonStr, &userActionObj)
+
+		var undefinedEmail = userActionObj.Requestor
+		if i == 1 {
+			undefinedEmail = userActionObj.Target
+		}
+
+		relationshipServiceMock := services.RelationshipServiceMock{}
+		userServiceMock := services.UserServiceMock{}
+
+		relationshipServiceMock.On("Block", userActionObj.Requestor, userActionObj.Target).Return(services.Outcome(""), &data.NotFoundError{Entity: "user", Key: undefinedEmail})
+
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/friends/block", bytes.NewBuffer(jsonStr))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		relationshipEndpoint.Block(c)
+
+		assert.Equal(t, w.Result().StatusCode, http.StatusNotFound)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, false, actualResult.Success)
+		assert.Equal(t, fmt.Sprintf("Invalid request: User name %s is not found", undefinedEmail), actualResult.Message)
+		assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
+	}
+}
+
+func TestBlockUpdateWithAlreadyBlockedAccounts(t *testing.T) {
+	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+
+	relationshipServiceMock.On("Block", "email@request.com", "email@target.com").Return(services.Outcome(""), &services.RelationshipError{Code: models.CodeBlocked, Message: "blocked status is existed"})
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/block", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.Block(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, "Invalid request: blocked status is existed", actualResult.Message)
+	assert.Equal(t, models.CodeBlocked, actualResult.Code)
+}
+
+func TestBlockUpdateWithAlreadySubcribedAccounts(t *testing.T) {
+	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
+
+	relationshipEndpoint, repositories := newMemoryRelationshipEndpoint()
+	repositories.IRelationshipRepository.CreateRelationship(&models.Relationship{RequestUserId: 1, TargetUserId: 2, Status: models.RelationshipSubscribed})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/block", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.Block(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.Success
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, true, actualResult.Success)
+	following, _, _ := repositories.IRelationshipRepository.GetFollowing(1, models.Page{Limit: 20})
+	assert.Empty(t, following)
+	blocked, _, _ := repositories.IRelationshipRepository.GetBlockedUsers(1, models.Page{Limit: 20})
+	assert.Len(t, blocked, 1)
+	assert.Equal(t, "email@target.com", blocked[0].Email)
+}
+
+func TestBlockUpdateWithAlreadyConnectedAccounts(t *testing.T) {
 	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-	userActionObj := models.UserAction{}
-	json.Unmarshal(jsonStr, &userActionObj)
-
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
-	relationshipServiceMock := services.RelationshipServiceMock{}
-	userServiceMock := services.UserServiceMock{}
-
-	requestUser := userActionObj.Requestor
-	requestUserId := int64(1)
-	targetUser := userActionObj.Target
-	targetUserId := int64(2)
-	connectedStatus := int64(1)
-	blockedStatus := int64(3)
-	subcribedStatus := int64(2)
-	connectedIds := []int64{}
-	blockedIds := []int64{}
-	subcribedIds := []int64{}
-
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
-
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
-
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, subcribedStatus).Return(subcribedIds)
-	relationshipServiceMock.On("CheckPartialSubcribed", requestUserId, targetUserId).Return(subcribedIds)
-
-	relationshipRepositoryMock.On("CheckRelationshipOnetWay", requestUserId, targetUserId, blockedStatus).Return(blockedIds)
-	relationshipServiceMock.On("CheckPartialBlocked", requestUserId, targetUserId).Return(blockedIds)
-
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, connectedStatus).Return(connectedIds)
-	relationshipServiceMock.On("CheckConnected", requestUserId, targetUserId).Return(connectedIds)
-
-	relationshipModel := models.Relationship{Status: subcribedStatus, RequestUserId: requestUserId, TargetUserId: targetUserId}
-	relationshipRepositoryMock.On("CreateRelationship", &relationshipModel).Return(int64(10))
-	relationshipServiceMock.On("CreateRelationship", &relationshipModel).Return(int64(10))
+	relationshipEndpoint, repositories := newMemoryRelationshipEndpoint()
+	repositories.IRelationshipRepository.CreateRelationship(&models.Relationship{RequestUserId: 2, TargetUserId: 1, Status: models.RelationshipFriend})
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("POST", "/friends/subcribe", bytes.NewBuffer(jsonStr))
+	c.Request, _ = http.NewRequest("POST", "/friends/block", bytes.NewBuffer(jsonStr))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	relationshipEndpoint.Subscribe(c)
+	relationshipEndpoint.Block(c)
 
 	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
 
@@ -842,15 +1426,16 @@ func TestSubcribeUpdateWhichOkResult(t *testing.T) {
 	json.Unmarshal(body, &actualResult)
 
 	assert.Equal(t, true, actualResult.Success)
+	friends, _, _ := repositories.IRelationshipRepository.GetFriendList(1, models.Page{Limit: 20})
+	assert.Empty(t, friends)
 }
 
-func TestBlockUpdateWithInvalidAccounts(t *testing.T) {
+func TestRemoveFriendWithInvalidAccounts(t *testing.T) {
 	var invalidRequests = []string{
-		`{"requestor":"invalid_model`,
-		`{"requestor":"target@email.com"}`,
-		`{"requestor":"request","target":"target@email.com"}`,
-		`{"requestor":"target@email.com","target":"request"}`,
-		`{"requestor":"","target":""}`}
+		`{"friends":"target@email.com"}`,
+		`{"friends":["request","target@email.com"]}`,
+		`{"friends":["request@email.com","request@email.com"]}`,
+		`{"friends":["request@email.com","target@email.com","unknown@email.com"]}`}
 	for _, request := range invalidRequests {
 
 		var jsonStr = []byte(request)
@@ -858,13 +1443,13 @@ func TestBlockUpdateWithInvalidAccounts(t *testing.T) {
 		relationshipServiceMock := services.RelationshipServiceMock{}
 		userServiceMock := services.UserServiceMock{}
 
-		relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
-		c.Request, _ = http.NewRequest("POST", "/friends/block", bytes.NewBuffer(jsonStr))
+		c.Request, _ = http.NewRequest("POST", "/friends/remove", bytes.NewBuffer(jsonStr))
 		c.Request.Header.Set("Content-Type", "application/json")
 
-		relationshipEndpoint.Block(c)
+		relationshipEndpoint.RemoveFriend(c)
 
 		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
 
@@ -874,96 +1459,78 @@ func TestBlockUpdateWithInvalidAccounts(t *testing.T) {
 
 		assert.Equal(t, false, actualResult.Success)
 		assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
 	}
 }
 
-func TestBlockUpdateWithNotFoundAccounts(t *testing.T) {
-	var invalidRequests = []string{
-		`{"requestor":"request@notfound.com","target":"target@email.com"}`,
-		`{"requestor":"request@email.com","target":"target@notfound.com"}`}
+func TestRemoveFriendWithNotConnectedAccounts(t *testing.T) {
+	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
 
-	for i, request := range invalidRequests {
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
 
-		var jsonStr = []byte(request)
+	relationshipServiceMock.On("RemoveFriend", "email@request.com", "email@target.com").Return(services.Outcome(""), &services.RelationshipError{Code: models.CodeNotConnected, Message: "connected status is not existed"})
 
-		userActionObj := models.UserAction{}
-		json.Unmarshal(jsonStr, &userActionObj)
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/remove", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-		var undefinedEmail = userActionObj.Requestor
-		if i == 1 {
-			undefinedEmail = userActionObj.Target
-		}
+	relationshipEndpoint.RemoveFriend(c)
 
-		relationshipServiceMock := services.RelationshipServiceMock{}
-		userServiceMock := services.UserServiceMock{}
+	assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
 
-		if i == 0 {
-			userRepositoryMock := data.UserRepositoryMock{}
-			userRepositoryMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
 
-			userServiceMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-		} else {
-			userRepositoryMock := data.UserRepositoryMock{}
-			userRepositoryMock.On("CheckUserExist", userActionObj.Requestor).Return(int64(1))
-			userRepositoryMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, "Invalid request: connected status is not existed", actualResult.Message)
+	assert.Equal(t, models.CodeNotConnected, actualResult.Code)
+}
 
-			userServiceMock.On("CheckUserExist", userActionObj.Requestor).Return(int64(1))
-			userServiceMock.On("CheckUserExist", undefinedEmail).Return(int64(-1))
-		}
+func TestRemoveFriendReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"friends":["email@request.com","email@target.com"]}`)
 
-		relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
-		w := httptest.NewRecorder()
-		c, _ := gin.CreateTestContext(w)
-		c.Request, _ = http.NewRequest("POST", "/friends/block", bytes.NewBuffer(jsonStr))
-		c.Request.Header.Set("Content-Type", "application/json")
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
 
-		relationshipEndpoint.Block(c)
+	relationshipServiceMock.On("RemoveFriend", "email@request.com", "email@target.com").Return(services.FriendRemoved, nil)
 
-		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/remove", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-		var actualResult models.Failure
-		body, _ := ioutil.ReadAll(w.Result().Body)
-		json.Unmarshal(body, &actualResult)
+	relationshipEndpoint.RemoveFriend(c)
 
-		assert.Equal(t, false, actualResult.Success)
-		assert.Equal(t, fmt.Sprintf("Invalid request: User name %s is not found", undefinedEmail), actualResult.Message)
-	}
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.Success
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, true, actualResult.Success)
+	relationshipServiceMock.AssertExpectations(t)
 }
 
-func TestBlockUpdateWithAlreadyBlockedAccounts(t *testing.T) {
+func TestUnsubscribeWithNotSubcribedAccounts(t *testing.T) {
 	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-	userActionObj := models.UserAction{}
-	json.Unmarshal(jsonStr, &userActionObj)
-
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
 	relationshipServiceMock := services.RelationshipServiceMock{}
 	userServiceMock := services.UserServiceMock{}
 
-	requestUser := userActionObj.Requestor
-	requestUserId := int64(1)
-	targetUser := userActionObj.Target
-	targetUserId := int64(2)
-	blockedStatus := int64(3)
-	blockedIds := []int64{int64(1)}
-
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
-
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
+	relationshipServiceMock.On("Unsubscribe", "email@request.com", "email@target.com").Return(services.Outcome(""), &services.RelationshipError{Code: models.CodeNotSubscribed, Message: "subcribed status is not existed"})
 
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, blockedStatus).Return(blockedIds)
-	relationshipServiceMock.On("CheckPartialBlocked", requestUserId, targetUserId).Return(blockedIds)
-
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("POST", "/friends/block", bytes.NewBuffer(jsonStr))
+	c.Request, _ = http.NewRequest("POST", "/friends/unsubscribe", bytes.NewBuffer(jsonStr))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	relationshipEndpoint.Block(c)
+	relationshipEndpoint.Unsubscribe(c)
 
 	assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
 
@@ -972,60 +1539,25 @@ func TestBlockUpdateWithAlreadyBlockedAccounts(t *testing.T) {
 	json.Unmarshal(body, &actualResult)
 
 	assert.Equal(t, false, actualResult.Success)
-	assert.Equal(t, "Invalid request: blocked status is existed", actualResult.Message)
+	assert.Equal(t, "Invalid request: subcribed status is not existed", actualResult.Message)
+	assert.Equal(t, models.CodeNotSubscribed, actualResult.Code)
 }
 
-func TestBlockUpdateWithAlreadySubcribedAccounts(t *testing.T) {
+func TestUnsubscribeReturnOk(t *testing.T) {
 	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-	userActionObj := models.UserAction{}
-	json.Unmarshal(jsonStr, &userActionObj)
-
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
 	relationshipServiceMock := services.RelationshipServiceMock{}
 	userServiceMock := services.UserServiceMock{}
 
-	requestUser := userActionObj.Requestor
-	requestUserId := int64(1)
-	targetUser := userActionObj.Target
-	targetUserId := int64(2)
-	connectedStatus := int64(1)
-	blockedStatus := int64(3)
-	subcribedStatus := int64(2)
-	connectedIds := []int64{}
-	blockedIds := []int64{}
-	subcribedIds := []int64{int64(1)}
-
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
-
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
-
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, blockedStatus).Return(blockedIds)
-	relationshipServiceMock.On("CheckPartialBlocked", requestUserId, targetUserId).Return(blockedIds)
-
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, subcribedStatus).Return(subcribedIds)
-	relationshipServiceMock.On("CheckPartialSubcribed", requestUserId, targetUserId).Return(subcribedIds)
+	relationshipServiceMock.On("Unsubscribe", "email@request.com", "email@target.com").Return(services.Unsubscribed, nil)
 
-	relationshipRepositoryMock.On("DeleteRelationships", subcribedIds).Return(true)
-	relationshipServiceMock.On("DeleteRelationships", subcribedIds).Return(true)
-
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, connectedStatus).Return(connectedIds)
-	relationshipServiceMock.On("CheckConnected", requestUserId, targetUserId).Return(connectedIds)
-
-	relationshipModel := models.Relationship{Status: blockedStatus, RequestUserId: requestUserId, TargetUserId: targetUserId}
-	relationshipRepositoryMock.On("CreateRelationship", &relationshipModel).Return(int64(10))
-	relationshipServiceMock.On("CreateRelationship", &relationshipModel).Return(int64(10))
-
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("POST", "/friends/subcribe", bytes.NewBuffer(jsonStr))
+	c.Request, _ = http.NewRequest("POST", "/friends/unsubscribe", bytes.NewBuffer(jsonStr))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	relationshipEndpoint.Block(c)
+	relationshipEndpoint.Unsubscribe(c)
 
 	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
 
@@ -1034,59 +1566,51 @@ func TestBlockUpdateWithAlreadySubcribedAccounts(t *testing.T) {
 	json.Unmarshal(body, &actualResult)
 
 	assert.Equal(t, true, actualResult.Success)
+	relationshipServiceMock.AssertExpectations(t)
 }
 
-func TestBlockUpdateWithAlreadyConnectedAccounts(t *testing.T) {
+func TestUnblockWithNotBlockedAccounts(t *testing.T) {
 	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-	userActionObj := models.UserAction{}
-	json.Unmarshal(jsonStr, &userActionObj)
-
-	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	userRepositoryMock := data.UserRepositoryMock{}
 	relationshipServiceMock := services.RelationshipServiceMock{}
 	userServiceMock := services.UserServiceMock{}
 
-	requestUser := userActionObj.Requestor
-	requestUserId := int64(1)
-	targetUser := userActionObj.Target
-	targetUserId := int64(2)
-	connectedStatus := int64(1)
-	blockedStatus := int64(3)
-	subcribedStatus := int64(2)
-	connectedIds := []int64{int64(1)}
-	blockedIds := []int64{}
-	subcribedIds := []int64{}
+	relationshipServiceMock.On("Unblock", "email@request.com", "email@target.com").Return(services.Outcome(""), &services.RelationshipError{Code: models.CodeNotBlocked, Message: "blocked status is not existed"})
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/unblock", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-	userRepositoryMock.On("CheckUserExist", requestUser).Return(requestUserId)
-	userServiceMock.On("CheckUserExist", requestUser).Return(requestUserId)
+	relationshipEndpoint.Unblock(c)
 
-	userRepositoryMock.On("CheckUserExist", targetUser).Return(targetUserId)
-	userServiceMock.On("CheckUserExist", targetUser).Return(targetUserId)
+	assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
 
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, blockedStatus).Return(blockedIds)
-	relationshipServiceMock.On("CheckPartialBlocked", requestUserId, targetUserId).Return(blockedIds)
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
 
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, subcribedStatus).Return(subcribedIds)
-	relationshipServiceMock.On("CheckPartialSubcribed", requestUserId, targetUserId).Return(subcribedIds)
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, "Invalid request: blocked status is not existed", actualResult.Message)
+	assert.Equal(t, models.CodeNotBlocked, actualResult.Code)
+}
 
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, connectedStatus).Return(connectedIds)
-	relationshipServiceMock.On("CheckConnected", requestUserId, targetUserId).Return(connectedIds)
+func TestUnblockReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"requestor":"email@request.com","target":"email@target.com"}`)
 
-	relationshipRepositoryMock.On("DeleteRelationships", connectedIds).Return(true)
-	relationshipServiceMock.On("DeleteRelationships", connectedIds).Return(true)
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
 
-	relationshipModel := models.Relationship{Status: blockedStatus, RequestUserId: requestUserId, TargetUserId: targetUserId}
-	relationshipRepositoryMock.On("CreateRelationship", &relationshipModel).Return(int64(10))
-	relationshipServiceMock.On("CreateRelationship", &relationshipModel).Return(int64(10))
+	relationshipServiceMock.On("Unblock", "email@request.com", "email@target.com").Return(services.Unblocked, nil)
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Request, _ = http.NewRequest("POST", "/friends/block", bytes.NewBuffer(jsonStr))
+	c.Request, _ = http.NewRequest("POST", "/friends/unblock", bytes.NewBuffer(jsonStr))
 	c.Request.Header.Set("Content-Type", "application/json")
 
-	relationshipEndpoint.Block(c)
+	relationshipEndpoint.Unblock(c)
 
 	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
 
@@ -1095,6 +1619,7 @@ func TestBlockUpdateWithAlreadyConnectedAccounts(t *testing.T) {
 	json.Unmarshal(body, &actualResult)
 
 	assert.Equal(t, true, actualResult.Success)
+	relationshipServiceMock.AssertExpectations(t)
 }
 
 func TestReceiveUpdateWithInvalidAccount(t *testing.T) {
@@ -1111,7 +1636,7 @@ func TestReceiveUpdateWithInvalidAccount(t *testing.T) {
 		relationshipServiceMock := services.RelationshipServiceMock{}
 		userServiceMock := services.UserServiceMock{}
 
-		relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Request, _ = http.NewRequest("POST", "/friends/receive-updates", bytes.NewBuffer(jsonStr))
@@ -1127,6 +1652,7 @@ func TestReceiveUpdateWithInvalidAccount(t *testing.T) {
 
 		assert.Equal(t, false, actualResult.Success)
 		assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
 	}
 }
 
@@ -1140,11 +1666,11 @@ func TestReceiveUpdateWithNotFoundAccount(t *testing.T) {
 	userServiceMock := services.UserServiceMock{}
 
 	userRepositoryMock := data.UserRepositoryMock{}
-	userRepositoryMock.On("CheckUserExist", userPostObj.Sender).Return(int64(-1))
+	userRepositoryMock.On("CheckUserExist", userPostObj.Sender).Return(int64(0), &data.NotFoundError{Entity: "user", Key: userPostObj.Sender})
 
-	userServiceMock.On("CheckUserExist", userPostObj.Sender).Return(int64(-1))
+	userServiceMock.On("GetUser", userPostObj.Sender).Return(models.User{}, &data.NotFoundError{Entity: "user", Key: userPostObj.Sender})
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("POST", "/friends/receive-updates", bytes.NewBuffer(jsonStr))
@@ -1152,7 +1678,7 @@ func TestReceiveUpdateWithNotFoundAccount(t *testing.T) {
 
 	relationshipEndpoint.ReceiveUpdates(c)
 
-	assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+	assert.Equal(t, w.Result().StatusCode, http.StatusNotFound)
 
 	var actualResult models.Failure
 	body, _ := ioutil.ReadAll(w.Result().Body)
@@ -1160,6 +1686,7 @@ func TestReceiveUpdateWithNotFoundAccount(t *testing.T) {
 
 	assert.Equal(t, false, actualResult.Success)
 	assert.Equal(t, fmt.Sprintf("Invalid request: User name %s is not found", userPostObj.Sender), actualResult.Message)
+	assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
 }
 
 func TestReceiveUpdateReturnOk(t *testing.T) {
@@ -1173,21 +1700,24 @@ func TestReceiveUpdateReturnOk(t *testing.T) {
 	userServiceMock := services.UserServiceMock{}
 
 	userRepositoryMock := data.UserRepositoryMock{}
-	userRepositoryMock.On("CheckUserExist", userPostObj.Sender).Return(int64(1))
-	userServiceMock.On("CheckUserExist", userPostObj.Sender).Return(int64(1))
+	userRepositoryMock.On("CheckUserExist", userPostObj.Sender).Return(int64(1), nil)
+	userServiceMock.On("GetUser", userPostObj.Sender).Return(models.User{ID: 1, Email: userPostObj.Sender, Status: models.UserActive}, nil)
 
 	existedIds := []int64{int64(10)}
-	userRepositoryMock.On("CheckUsersExist", []string{"johndoe@gmail.com"}).Return(existedIds)
-	userServiceMock.On("CheckUsersExist", []string{"johndoe@gmail.com"}).Return(existedIds)
+	userRepositoryMock.On("CheckUsersExist", []string{"johndoe@gmail.com"}).Return(existedIds, nil)
+	userServiceMock.On("CheckUsersExist", []string{"johndoe@gmail.com"}).Return(existedIds, nil)
 
 	senderId := int64(1)
 	mentionedIds := []int64{int64(10)}
 	receiveUpdateEmails := []string{"user1@email.com", "user2@email.com"}
 
-	relationshipRepositoryMock.On("GetValidUsersCanReceiveUpdates", senderId, mentionedIds).Return(receiveUpdateEmails)
-	relationshipServiceMock.On("GetValidUsersCanReceiveUpdates", senderId, mentionedIds).Return(receiveUpdateEmails)
+	relationshipRepositoryMock.On("GetValidUsersCanReceiveUpdates", senderId, mentionedIds, models.Page{Limit: 20}).Return(receiveUpdateEmails, int64(0), nil)
+	relationshipServiceMock.On("GetValidUsersCanReceiveUpdates", senderId, mentionedIds, models.Page{Limit: 20}).Return(receiveUpdateEmails, int64(0), nil)
+
+	postServiceMock := services.PostServiceMock{}
+	postServiceMock.On("CreatePost", senderId, userPostObj.Text, mentionedIds).Return(int64(1), nil)
 
-	relationshipEndpoint := endpoints.RelationshipEndpoint{relationshipServiceMock, userServiceMock}
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock, IPostService: &postServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("POST", "/friends/receive-updates", bytes.NewBuffer(jsonStr))
@@ -1197,9 +1727,212 @@ func TestReceiveUpdateReturnOk(t *testing.T) {
 
 	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
 
-	var actualResult models.Success
+	var actualResult models.Recipent
 	body, _ := ioutil.ReadAll(w.Result().Body)
 	json.Unmarshal(body, &actualResult)
 
 	assert.Equal(t, true, actualResult.Success)
+	assert.Equal(t, receiveUpdateEmails, actualResult.Recipents)
+	assert.Equal(t, int64(1), actualResult.PostId)
+	assert.Equal(t, "", actualResult.NextCursor)
+	postServiceMock.AssertExpectations(t)
+}
+
+func TestReceiveUpdateMatchesEmailsRegardlessOfCase(t *testing.T) {
+	var jsonStr = []byte(`{"sender":"Sender@Email.com","text":"hello world sender@email.com JohnDoe@Gmail.com"}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+	postServiceMock := services.PostServiceMock{}
+
+	userServiceMock.On("GetUser", "Sender@Email.com").Return(models.User{ID: 1, Email: "sender@email.com", Status: models.UserActive}, nil)
+	userServiceMock.On("CheckUsersExist", []string{"johndoe@gmail.com"}).Return([]int64{10}, nil)
+	postServiceMock.On("CreatePost", int64(1), "hello world sender@email.com JohnDoe@Gmail.com", []int64{10}).Return(int64(1), nil)
+	relationshipServiceMock.On("GetValidUsersCanReceiveUpdates", int64(1), []int64{10}, models.Page{Limit: 20}).Return([]string{"johndoe@gmail.com"}, int64(0), nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock, IPostService: &postServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/receive-updates", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.ReceiveUpdates(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+	userServiceMock.AssertExpectations(t)
+	postServiceMock.AssertExpectations(t)
+}
+
+func TestReceiveUpdateWithSuspendedSender(t *testing.T) {
+	var jsonStr = []byte(`{"sender":"sender@email.com","text":"hello world"}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+	postServiceMock := services.PostServiceMock{}
+
+	userServiceMock.On("GetUser", "sender@email.com").Return(models.User{ID: 1, Email: "sender@email.com", Status: models.UserSuspended}, nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock, IPostService: &postServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/receive-updates", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.ReceiveUpdates(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, "Invalid request: User sender@email.com is suspended", actualResult.Message)
+	assert.Equal(t, models.CodeUserInactive, actualResult.Code)
+}
+
+func TestReceiveUpdateReturnInternalError(t *testing.T) {
+	var jsonStr = []byte(`{"sender":"sender@email.com","text":"hello world"}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+	postServiceMock := services.PostServiceMock{}
+
+	var mentionedIds []int64
+
+	userServiceMock.On("GetUser", "sender@email.com").Return(models.User{ID: 1, Email: "sender@email.com", Status: models.UserActive}, nil)
+	relationshipServiceMock.On("GetValidUsersCanReceiveUpdates", int64(1), mentionedIds, models.Page{Limit: 20}).Return([]string{"user1@email.com"}, int64(0), nil)
+	postServiceMock.On("CreatePost", int64(1), "hello world", mentionedIds).Return(int64(0), errors.New("connection refused"))
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock, IPostService: &postServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/receive-updates", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.ReceiveUpdates(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusServiceUnavailable)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, false, actualResult.Success)
+	assert.Equal(t, "Oops! There is an error, please try again.", actualResult.Message)
+	assert.Equal(t, models.CodeServiceUnavailable, actualResult.Code)
+}
+
+func TestReceiveUpdateWithRecipientsErrorStoresNoPost(t *testing.T) {
+	var jsonStr = []byte(`{"sender":"sender@email.com","text":"hello world"}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	userServiceMock := services.UserServiceMock{}
+	postServiceMock := services.PostServiceMock{}
+
+	var mentionedIds []int64
+
+	userServiceMock.On("GetUser", "sender@email.com").Return(models.User{ID: 1, Email: "sender@email.com", Status: models.UserActive}, nil)
+	relationshipServiceMock.On("GetValidUsersCanReceiveUpdates", int64(1), mentionedIds, models.Page{Limit: 20}).Return([]string(nil), int64(0), errors.New("connection refused"))
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &userServiceMock, IPostService: &postServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/receive-updates", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.ReceiveUpdates(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusServiceUnavailable)
+	postServiceMock.AssertNotCalled(t, "CreatePost", int64(1), "hello world", mentionedIds)
+}
+
+func TestBatchReportsInvalidFields(t *testing.T) {
+	var invalidRequests = map[string][]models.FieldError{
+		`{"operations":{}}`: {{Field: "body", Message: "must be a valid JSON object"}},
+		`{"operations":[]}`: {{Field: "operations", Message: "must hold between 1 and 500 operations"}},
+		`{"operations":[{"type":"friend","requestor":"a@test.com","target":"b@test.com"},{"type":"unfriend","requestor":"a","target":"A@test.com"}]}`: {
+			{Field: "operations[1].type", Message: "must be friend, subscribe or block"},
+			{Field: "operations[1].requestor", Message: "must be a valid email"}},
+		`{"operations":[{"type":"block","requestor":"a@test.com","target":"A@test.com"}]}`: {
+			{Field: "operations[0].target", Message: "must differ from operations[0].requestor"}},
+	}
+	for request, expectedDetails := range invalidRequests {
+
+		var jsonStr = []byte(request)
+
+		relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &services.RelationshipServiceMock{}, IUserService: &services.UserServiceMock{}}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/friends/batch", bytes.NewBuffer(jsonStr))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		relationshipEndpoint.Batch(c)
+
+		assert.Equal(t, w.Result().StatusCode, http.StatusBadRequest)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
+		assert.Equal(t, expectedDetails, actualResult.Details, request)
+	}
+}
+
+func TestBatchReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"operations":[{"type":"friend","requestor":"a@test.com","target":"b@test.com"},{"type":"block","requestor":"a@test.com","target":"x@test.com"}],"atomic":true}`)
+
+	operations := []models.FriendOperation{
+		{Type: models.FriendOperationFriend, Requestor: "a@test.com", Target: "b@test.com"},
+		{Type: models.FriendOperationBlock, Requestor: "a@test.com", Target: "x@test.com"},
+	}
+	expectedResult := models.FriendBatch{
+		Results: []models.FriendOperationResult{
+			{FriendOperation: operations[0], Outcome: "FRIEND_REQUEST_SENT", Success: true},
+			{FriendOperation: operations[1], Code: models.CodeUserNotFound, Message: "user x@test.com is not found"},
+		},
+		Succeeded:  1,
+		Failed:     1,
+		RolledBack: true,
+	}
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	relationshipServiceMock.On("ApplyBatch", operations, true).Return(expectedResult, nil)
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &services.UserServiceMock{}}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/batch", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.Batch(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusOK)
+
+	var actualResult models.FriendBatch
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	expectedResult.Success = true
+	assert.Equal(t, expectedResult, actualResult)
+	relationshipServiceMock.AssertExpectations(t)
+}
+
+func TestBatchReturnInternalError(t *testing.T) {
+	var jsonStr = []byte(`{"operations":[{"type":"subscribe","requestor":"a@test.com","target":"b@test.com"}]}`)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	relationshipServiceMock.On("ApplyBatch", []models.FriendOperation{{Type: models.FriendOperationSubscribe, Requestor: "a@test.com", Target: "b@test.com"}}, false).Return(models.FriendBatch{}, errors.New("connection refused"))
+
+	relationshipEndpoint := endpoints.RelationshipEndpoint{IRelationshipService: &relationshipServiceMock, IUserService: &services.UserServiceMock{}}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/friends/batch", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	relationshipEndpoint.Batch(c)
+
+	assert.Equal(t, w.Result().StatusCode, http.StatusServiceUnavailable)
+	relationshipServiceMock.AssertExpectations(t)
 }
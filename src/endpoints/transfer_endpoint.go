@@ -0,0 +1,122 @@
+package endpoints
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"friendMgmt/models"
+	"friendMgmt/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TransferEndpoint struct {
+	ITransferService services.ITransferService
+}
+
+// recordContentTypes are the content types of the exports by format.
+var recordContentTypes = map[models.RecordFormat]string{
+	models.RecordFormatCSV:    "text/csv; charset=utf-8",
+	models.RecordFormatNDJSON: "application/x-ndjson",
+}
+
+// maxImportBodyLength bounds the body of an import, which is read whole and applied in a single transaction.
+const maxImportBodyLength = 16 * 1024 * 1024
+
+// Import godoc
+// @Tags User
+// @Summary API to import users and their relationships from CSV or NDJSON
+// @Description Every record is an user, created with its profile unless its email is in use, or a relationship from the user to the target with the status friend, subscribed, blocked or pending, applied like the relationship operations. The records are applied in their order in a single transaction, the invalid and conflicting ones are skipped and the existing ones are kept so importing a file again changes nothing. A dry run reports the same results without storing anything. CSV files start with a header naming their columns among type, email, displayName, avatarUrl, status, target and createdAt, the RFC 3339 time a relationship was created at. The body must not exceed 16 MB.
+// @Accept  plain
+// @Produce  json
+// @Param format query string false "Format of the body, ndjson by default" Enums(csv, ndjson)
+// @Param dryRun query bool false "Report the results without storing anything"
+// @Param records body string true "Records"
+// @Success 200 {object} models.UserImport "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /users/import [post]
+func (t TransferEndpoint) Import(c *gin.Context) {
+	var details fieldErrors
+	format := details.checkFormat(c.Query("format"))
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	details.check(err == nil, "dryRun", "must be true or false")
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return
+	}
+
+	records, err := services.ReadUserRecords(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodyLength), format)
+	var recordErr *services.RecordError
+	if errors.As(err, &recordErr) {
+		responseValidationError(c, models.FieldError{Field: "body", Message: recordErr.Error()})
+		return
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		responseValidationError(c, models.FieldError{Field: "body", Message: fmt.Sprintf("must not exceed %d bytes", maxImportBodyLength)})
+		return
+	}
+	if err != nil {
+		responseValidationError(c, models.FieldError{Field: "body", Message: "must be readable"})
+		return
+	}
+
+	report, err := t.ITransferService.Import(records, dryRun)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	report.Success = true
+
+	responseOk(c, report)
+}
+
+// Export godoc
+// @Tags User
+// @Summary API to export every user and relationship as CSV or NDJSON
+// @Description The users come first, then the relationships in the order they were created, so importing the export rebuilds the same graph. It's a POST since the GET routes under /users take an email.
+// @Produce  plain
+// @Param format query string false "Format of the export, ndjson by default" Enums(csv, ndjson)
+// @Success 200 {string} string "Records"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /users/export [post]
+func (t TransferEndpoint) Export(c *gin.Context) {
+	var details fieldErrors
+	format := details.checkFormat(c.Query("format"))
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return
+	}
+
+	records, err := t.ITransferService.Export()
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := services.WriteUserRecords(&body, format, records); err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="users.`+string(format)+`"`)
+	c.Data(http.StatusOK, recordContentTypes[format], body.Bytes())
+}
+
+// checkFormat records the format field unless it's empty or a known format and returns the format it selects,
+// ndjson by default.
+func (errs *fieldErrors) checkFormat(value string) models.RecordFormat {
+	format := models.RecordFormat(value)
+	if value == "" {
+		format = models.RecordFormatNDJSON
+	}
+	errs.check(format.IsValid(), "format", "must be csv or ndjson")
+
+	return format
+}
@@ -0,0 +1,122 @@
+package endpoints_test
+
+import (
+	"encoding/json"
+	"friendMgmt/endpoints"
+	"friendMgmt/models"
+	"friendMgmt/services"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestImportWithInvalidRequest(t *testing.T) {
+	var invalidRequests = map[string][]models.FieldError{
+		"format=xml":             {{Field: "format", Message: "must be csv or ndjson"}},
+		"dryRun=maybe":           {{Field: "dryRun", Message: "must be true or false"}},
+		"format=csv&dryRun=true": {{Field: "body", Message: "line 1 must only name the columns type, email, displayName, avatarUrl, status, target, createdAt"}},
+	}
+	for query, expectedDetails := range invalidRequests {
+		transferServiceMock := services.TransferServiceMock{}
+
+		transferEndpoint := endpoints.TransferEndpoint{ITransferService: &transferServiceMock}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/users/import?"+query, strings.NewReader("name,email\n"))
+
+		transferEndpoint.Import(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, query)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, expectedDetails, actualResult.Details, query)
+		transferServiceMock.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	}
+}
+
+func TestImportWithTooLargeBody(t *testing.T) {
+	var files = map[string]struct{ header, line string }{
+		"format=csv":    {"type,email\n", "user,a@test.com\n"},
+		"format=ndjson": {"", `{"type":"user","email":"a@test.com"}` + "\n"},
+	}
+	for query, file := range files {
+		transferServiceMock := services.TransferServiceMock{}
+
+		transferEndpoint := endpoints.TransferEndpoint{ITransferService: &transferServiceMock}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/users/import?"+query, strings.NewReader(file.header+strings.Repeat(file.line, 16*1024*1024/len(file.line)+1)))
+
+		transferEndpoint.Import(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, query)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, []models.FieldError{{Field: "body", Message: "must not exceed 16777216 bytes"}}, actualResult.Details, query)
+		transferServiceMock.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	}
+}
+
+func TestImportReturnOk(t *testing.T) {
+	records := []models.UserRecord{
+		{Type: models.UserRecordUser, Email: "a@test.com"},
+		{Type: models.UserRecordRelationship, Email: "a@test.com", Status: "blocked", Target: "b@test.com"},
+	}
+	report := models.UserImport{DryRun: true, Created: 1, Invalid: 1, Rows: []models.UserImportRow{
+		{Row: 1, Result: models.ImportCreated},
+		{Row: 2, Result: models.ImportInvalid, Details: []models.FieldError{{Field: "target", Message: "must be an imported or existing user"}}},
+	}}
+
+	transferServiceMock := services.TransferServiceMock{}
+	transferServiceMock.On("Import", records, true).Return(report, nil)
+
+	transferEndpoint := endpoints.TransferEndpoint{ITransferService: &transferServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/users/import?dryRun=true", strings.NewReader(
+		`{"type":"user","email":"a@test.com"}`+"\n"+`{"type":"relationship","email":"a@test.com","status":"blocked","target":"b@test.com"}`+"\n"))
+
+	transferEndpoint.Import(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	var actualResult models.UserImport
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	report.Success = true
+	assert.Equal(t, report, actualResult)
+}
+
+func TestExportAsCSV(t *testing.T) {
+	transferServiceMock := services.TransferServiceMock{}
+	transferServiceMock.On("Export").Return([]models.UserRecord{
+		{Type: models.UserRecordUser, Email: "a@test.com", Status: "active"},
+		{Type: models.UserRecordRelationship, Email: "a@test.com", Status: "friend", Target: "b@test.com"},
+	}, nil)
+
+	transferEndpoint := endpoints.TransferEndpoint{ITransferService: &transferServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/users/export?format=csv", nil)
+
+	transferEndpoint.Export(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Result().Header.Get("Content-Type"))
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	assert.Equal(t, "type,email,displayName,avatarUrl,status,target,createdAt\nuser,a@test.com,,,active,,\nrelationship,a@test.com,,,friend,b@test.com,\n", string(body))
+}
@@ -5,26 +5,99 @@ import (
 	"friendMgmt/common"
 	"friendMgmt/models"
 	"friendMgmt/services"
-	"net/http"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserEndpoint struct {
-	IUserService services.IUserService
+	IUserService         services.IUserService
+	IRelationshipService services.IRelationshipService
 }
 
 // Users godoc
 // @Tags User
-// @Summary API to get all users in app
+// @Summary API to list the users in app by page, in the order they signed up
+// @Description The limit defaults to 20 and can't exceed 100. The next page is read by passing the nextCursor of the page as cursor, there is none on the last page.
 // @Accept  json
 // @Produce  json
-// @Success 200 {array} string
+// @Param limit query int false "Limit"
+// @Param cursor query string false "Cursor"
+// @Success 200 {object} models.UserPage "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 503 {object} models.Failure "Service Unavailable"
 // @Router /users [get]
 func (u UserEndpoint) Users(c *gin.Context) {
-	emails := u.IUserService.FindAll()
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	users, next, err := u.IUserService.FindPage(page)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	userPage := models.UserPage{Users: users, Count: len(users), NextCursor: encodeCursor(next), Success: true}
 
-	responseOk(c, emails)
+	responseOk(c, userPage)
+}
+
+// Search godoc
+// @Tags User
+// @Summary API to search the users by email
+// @Description The query matches the start of the emails, or any part of them when match is contains, and the domain keeps the users of a domain, both ignoring the case. The users are sorted by id unless sort is email or -email. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor with the same search.
+// @Accept  json
+// @Produce  json
+// @Param model body models.UserSearch true "Body"
+// @Success 200 {object} models.UserPage "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /users/search [post]
+func (u UserEndpoint) Search(c *gin.Context) {
+	var userSearch models.UserSearch
+	if err := c.BindJSON(&userSearch); err != nil {
+		responseValidationError(c, bodyError)
+		return
+	}
+
+	filter := models.UserFilter{
+		Query:    userSearch.Query,
+		Contains: userSearch.Match == "contains",
+		Domain:   strings.TrimPrefix(userSearch.Domain, "@"),
+		Sort:     userSearch.Sort,
+	}
+	if filter.Sort == "" {
+		filter.Sort = models.UserSortId
+	}
+
+	var details fieldErrors
+	details.check(userSearch.Match == "" || userSearch.Match == "prefix" || userSearch.Match == "contains", "match", "must be prefix or contains")
+	details.check(!strings.ContainsAny(filter.Domain, "@ "), "domain", "must be a domain name")
+	details.check(filter.Sort == models.UserSortId || filter.Sort == models.UserSortEmail || filter.Sort == models.UserSortEmailDesc, "sort", "must be id, email or -email")
+	sortedByEmail := filter.Sort == models.UserSortEmail || filter.Sort == models.UserSortEmailDesc
+	page := details.checkSortedPage(userSearch.Limit, userSearch.Cursor, sortedByEmail)
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return
+	}
+
+	users, next, err := u.IUserService.SearchUsers(filter, page)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	nextCursor := encodeCursor(next)
+	if sortedByEmail && next != 0 {
+		nextCursor = encodeSortedCursor(next, users[len(users)-1].Email)
+	}
+
+	userPage := models.UserPage{Users: users, Count: len(users), NextCursor: nextCursor, Success: true}
+
+	responseOk(c, userPage)
 }
 
 // CreateUser godoc
@@ -36,6 +109,7 @@ func (u UserEndpoint) Users(c *gin.Context) {
 // @Param email body models.Email true "Body"
 // @Success 200 {object} models.Success "OK"
 // @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 503 {object} models.Failure "Service Unavailable"
 // @Router /users [post]
 func (u UserEndpoint) CreateUser(c *gin.Context) {
 	var emailModel models.Email
@@ -43,19 +117,306 @@ func (u UserEndpoint) CreateUser(c *gin.Context) {
 
 	fmt.Println(emailModel.Email)
 
-	if err != nil || !common.IsValidEmail(emailModel.Email) {
-		responseError(c, http.StatusBadRequest, "Invalid request: incorrect info")
+	if err != nil {
+		responseValidationError(c, bodyError)
 		return
 	}
 
-	if userId := u.IUserService.CheckUserExist(emailModel.Email); userId > 0 {
-		responseError(c, http.StatusBadRequest, "Invalid request: the email is already in use")
+	if !common.IsValidEmail(emailModel.Email) {
+		responseValidationError(c, models.FieldError{Field: "email", Message: mustBeValidEmail})
 		return
 	}
 
-	u.IUserService.Create(emailModel.Email)
+	if err := u.IUserService.Create(emailModel.Email); err != nil {
+		responseStorageError(c, err)
+		return
+	}
 
 	success := models.Success{Success: true}
 
 	responseOk(c, success)
 }
+
+// Summary godoc
+// @Tags User
+// @Summary API to read an user with the counts of its friends, followers and followed users
+// @Description Followers are the users subscribing to the user, following the users the user subscribes to.
+// @Produce  json
+// @Param email path string true "Email"
+// @Success 200 {object} models.UserSummary "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /users/{email} [get]
+func (u UserEndpoint) Summary(c *gin.Context) {
+	email := c.Param("email")
+	if !common.IsValidEmail(email) {
+		responseValidationError(c, models.FieldError{Field: "email", Message: mustBeValidEmail})
+		return
+	}
+
+	user, err := u.IUserService.GetUser(email)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	summary, err := u.IRelationshipService.CountRelationships(user.ID)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	summary.User = user
+	summary.Success = true
+
+	responseOk(c, summary)
+}
+
+// UpdateUser godoc
+// @Tags User
+// @Summary API to change the profile or the status of an user
+// @Description Only the fields of the body are changed. The avatar url must be an http or https url, or empty to remove it.
+// @Accept  json
+// @Produce  json
+// @Param email path string true "Email"
+// @Param model body models.UserPatch true "Body"
+// @Success 200 {object} models.UserProfile "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /users/{email} [patch]
+func (u UserEndpoint) UpdateUser(c *gin.Context) {
+	var patch models.UserPatch
+	if err := c.BindJSON(&patch); err != nil {
+		responseValidationError(c, bodyError)
+		return
+	}
+
+	email := c.Param("email")
+
+	var details fieldErrors
+	details.checkEmail(email, "email")
+	if patch.DisplayName != nil {
+		details.check(utf8.RuneCountInString(*patch.DisplayName) <= models.MaxDisplayNameLength, "displayName", fmt.Sprintf("must not exceed %d characters", models.MaxDisplayNameLength))
+	}
+	if patch.AvatarUrl != nil {
+		details.check(*patch.AvatarUrl == "" || common.IsWebUrl(*patch.AvatarUrl, models.MaxAvatarUrlLength), "avatarUrl", fmt.Sprintf("must be an http or https url of at most %d characters", models.MaxAvatarUrlLength))
+	}
+	if patch.Status != nil {
+		details.check(patch.Status.IsValid(), "status", "must be active, suspended or deleted")
+	}
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return
+	}
+
+	user, err := u.IUserService.UpdateUser(email, patch)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	responseOk(c, models.UserProfile{User: user, Success: true})
+}
+
+// DeleteUser godoc
+// @Tags User
+// @Summary API to erase an user with all its data
+// @Description The relationships of the user in both directions, its posts and the mentions of the user or in its posts are deleted with it, the response counts them.
+// @Produce  json
+// @Param email path string true "Email"
+// @Success 200 {object} models.UserDeletion "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /users/{email} [delete]
+func (u UserEndpoint) DeleteUser(c *gin.Context) {
+	email := c.Param("email")
+	if !common.IsValidEmail(email) {
+		responseValidationError(c, models.FieldError{Field: "email", Message: mustBeValidEmail})
+		return
+	}
+
+	deletion, err := u.IUserService.DeleteUser(email)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	deletion.Success = true
+
+	responseOk(c, deletion)
+}
+
+// ChangeEmail godoc
+// @Tags User
+// @Summary API to change the email of an user
+// @Description The new email must be valid and not used by another user.
+// @Accept  json
+// @Produce  json
+// @Param email path string true "Email"
+// @Param model body models.Email true "Body"
+// @Success 200 {object} models.UserProfile "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /users/{email}/email [put]
+func (u UserEndpoint) ChangeEmail(c *gin.Context) {
+	var emailModel models.Email
+	if err := c.BindJSON(&emailModel); err != nil {
+		responseValidationError(c, bodyError)
+		return
+	}
+
+	var details fieldErrors
+	details.checkPair(c.Param("email"), "email", emailModel.Email, "newEmail")
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return
+	}
+
+	user, err := u.IUserService.ChangeEmail(c.Param("email"), emailModel.Email)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	responseOk(c, models.UserProfile{User: user, Success: true})
+}
+
+// MergeUsers godoc
+// @Tags User
+// @Summary API for the administrators to merge an user into another one
+// @Description The relationships of both users are unioned on the target, a block prevailing over a friend connection, a subscription or a request with the same user, and mutual requests becoming a friend connection. The relationships between both users are dropped, the posts and mentions of the source are moved to the target and the source is deleted.
+// @Accept  json
+// @Produce  json
+// @Param model body models.UserMergeRequest true "Body"
+// @Success 200 {object} models.UserMerge "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /users/merge [post]
+func (u UserEndpoint) MergeUsers(c *gin.Context) {
+	var request models.UserMergeRequest
+	if err := c.BindJSON(&request); err != nil {
+		responseValidationError(c, bodyError)
+		return
+	}
+
+	var details fieldErrors
+	details.checkPair(request.Source, "source", request.Target, "target")
+	if len(details) > 0 {
+		responseValidationError(c, details...)
+		return
+	}
+
+	merge, err := u.IUserService.MergeUsers(request.Source, request.Target)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	merge.Success = true
+
+	responseOk(c, merge)
+}
+
+// Followers godoc
+// @Tags User
+// @Summary API to list the users subscribing to an user, oldest subscription first
+// @Description The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor. The total counts all the followers.
+// @Produce  json
+// @Param email path string true "Email"
+// @Param limit query int false "Limit"
+// @Param cursor query string false "Cursor"
+// @Success 200 {object} models.UserList "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /users/{email}/followers [get]
+func (u UserEndpoint) Followers(c *gin.Context) {
+	u.listUsers(c, u.IRelationshipService.GetFollowers, func(summary models.UserSummary) int { return summary.Followers })
+}
+
+// Following godoc
+// @Tags User
+// @Summary API to list the users an user subscribes to, oldest subscription first
+// @Description The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor. The total counts all the followed users.
+// @Produce  json
+// @Param email path string true "Email"
+// @Param limit query int false "Limit"
+// @Param cursor query string false "Cursor"
+// @Success 200 {object} models.UserList "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /users/{email}/following [get]
+func (u UserEndpoint) Following(c *gin.Context) {
+	u.listUsers(c, u.IRelationshipService.GetFollowing, func(summary models.UserSummary) int { return summary.Following })
+}
+
+// BlockedUsers godoc
+// @Tags User
+// @Summary API to list the users blocked by an user, oldest block first
+// @Description The blockedAt time is null for the blocks set before it was recorded. The limit defaults to 20 and can't exceed 100, the next page is read by passing the nextCursor of the page as cursor.
+// @Produce  json
+// @Param email path string true "Email"
+// @Param limit query int false "Limit"
+// @Param cursor query string false "Cursor"
+// @Success 200 {object} models.BlockList "OK"
+// @Failure 400 {object} models.Failure "Bad Request"
+// @Failure 404 {object} models.Failure "Not Found"
+// @Failure 503 {object} models.Failure "Service Unavailable"
+// @Router /users/{email}/blocked [get]
+func (u UserEndpoint) BlockedUsers(c *gin.Context) {
+	_, userId, ok := bindPathEmail(c, u.IUserService)
+	if !ok {
+		return
+	}
+
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	blockedUsers, next, err := u.IRelationshipService.GetBlockedUsers(userId, page)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	blockList := models.BlockList{Blocked: blockedUsers, Count: len(blockedUsers), NextCursor: encodeCursor(next), Success: true}
+
+	responseOk(c, blockList)
+}
+
+// listUsers answers a page of the users list returns for the user of the request path, with the total picked from the
+// relationship counts of the user.
+func (u UserEndpoint) listUsers(c *gin.Context, list func(id int64, page models.Page) ([]string, int64, error), total func(summary models.UserSummary) int) {
+	_, userId, ok := bindPathEmail(c, u.IUserService)
+	if !ok {
+		return
+	}
+
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	users, next, err := list(userId, page)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	summary, err := u.IRelationshipService.CountRelationships(userId)
+	if err != nil {
+		responseStorageError(c, err)
+		return
+	}
+
+	userList := models.UserList{Users: users, Count: len(users), Total: total(summary), NextCursor: encodeCursor(next), Success: true}
+
+	responseOk(c, userList)
+}
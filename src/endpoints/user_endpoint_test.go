@@ -10,46 +10,124 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestUsers(t *testing.T) {
-	expectedResult := []string{"user1@gmail.com", "user2@gmail.com"}
+	expectedResult := []models.User{{ID: 1, Email: "user1@gmail.com", Status: models.UserActive}, {ID: 2, Email: "user2@gmail.com", Status: models.UserSuspended}}
 
 	userRepositoryMock := data.UserRepositoryMock{}
-	userRepositoryMock.On("FindAll").Return(expectedResult)
+	userRepositoryMock.On("FindPage", models.Page{Limit: 2}).Return(expectedResult, int64(2), nil)
 
 	userServiceMock := services.UserServiceMock{}
-	userServiceMock.On("FindAll").Return(expectedResult)
+	userServiceMock.On("FindPage", models.Page{Limit: 2}).Return(expectedResult, int64(2), nil)
 
-	userEndpoint := endpoints.UserEndpoint{userServiceMock}
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/users?limit=2", nil)
 	userEndpoint.Users(c)
 
 	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
 
-	var actualResult []string
+	var actualResult models.UserPage
 	body, _ := ioutil.ReadAll(w.Result().Body)
 	err := json.Unmarshal(body, &actualResult)
 
 	assert.Equal(t, err, nil)
-	assert.Equal(t, actualResult, expectedResult)
+	assert.Equal(t, models.UserPage{Users: expectedResult, Count: 2, NextCursor: "Mg", Success: true}, actualResult)
+}
+
+func TestSearchWithInvalidRequest(t *testing.T) {
+	var invalidRequests = map[string][]models.FieldError{
+		`{"query":1}`:                    {{Field: "body", Message: "must be a valid JSON object"}},
+		`{"match":"suffix"}`:             {{Field: "match", Message: "must be prefix or contains"}},
+		`{"domain":"user@example.com"}`:  {{Field: "domain", Message: "must be a domain name"}},
+		`{"sort":"id desc"}`:             {{Field: "sort", Message: "must be id, email or -email"}},
+		`{"sort":"email","cursor":"OA"}`: {{Field: "cursor", Message: "must be the nextCursor of a previous page"}},
+		`{"cursor":"OHxhQGIuY29t"}`:      {{Field: "cursor", Message: "must be the nextCursor of a previous page"}},
+		`{"limit":101,"cursor":"x"}`: {
+			{Field: "limit", Message: "must be between 1 and 100"},
+			{Field: "cursor", Message: "must be the nextCursor of a previous page"}},
+	}
+	for request, expectedDetails := range invalidRequests {
+		userEndpoint := endpoints.UserEndpoint{IUserService: &services.UserServiceMock{}}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("POST", "/users/search", bytes.NewBuffer([]byte(request)))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		userEndpoint.Search(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, request)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, expectedDetails, actualResult.Details, request)
+	}
+}
+
+func TestSearchReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"query":"john","match":"contains","domain":"@example.com","sort":"-email","limit":2}`)
+
+	userServiceMock := services.UserServiceMock{}
+	filter := models.UserFilter{Query: "john", Contains: true, Domain: "example.com", Sort: models.UserSortEmailDesc}
+	users := []models.User{{ID: 3, Email: "john@example.com", Status: models.UserActive}, {ID: 8, Email: "anna.john@example.com", Status: models.UserActive}}
+	userServiceMock.On("SearchUsers", filter, models.Page{Limit: 2}).Return(users, int64(8), nil)
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/users/search", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	userEndpoint.Search(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	var actualResult models.UserPage
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.UserPage{Users: users, Count: 2, NextCursor: "OHxhbm5hLmpvaG5AZXhhbXBsZS5jb20", Success: true}, actualResult)
+}
+
+func TestSearchContinuesFromCursorEmail(t *testing.T) {
+	var jsonStr = []byte(`{"query":"john","sort":"-email","limit":2,"cursor":"OHxhbm5hLmpvaG5AZXhhbXBsZS5jb20"}`)
+
+	userServiceMock := services.UserServiceMock{}
+	filter := models.UserFilter{Query: "john", Sort: models.UserSortEmailDesc}
+	userServiceMock.On("SearchUsers", filter, models.Page{After: 8, AfterSort: "anna.john@example.com", Limit: 2}).Return([]models.User(nil), int64(0), nil)
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/users/search", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	userEndpoint.Search(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+	userServiceMock.AssertExpectations(t)
 }
 
 func TestCreateWithExistedEmail(t *testing.T) {
 	var jsonStr = []byte(`{"email": "user@test.com"}`)
 
 	userRepositoryMock := data.UserRepositoryMock{}
-	userRepositoryMock.On("CheckUserExist", "user@test.com").Return(int64(1))
+	userRepositoryMock.On("Create", "user@test.com").Return(&data.DuplicateError{Entity: "user", Key: "user@test.com"})
 
 	userServiceMock := services.UserServiceMock{}
-	userServiceMock.On("CheckUserExist", "user@test.com").Return(int64(1))
+	userServiceMock.On("Create", "user@test.com").Return(&data.DuplicateError{Entity: "user", Key: "user@test.com"})
 
-	userEndpoint := endpoints.UserEndpoint{userServiceMock}
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("POST", "/users", bytes.NewBuffer(jsonStr))
@@ -65,6 +143,7 @@ func TestCreateWithExistedEmail(t *testing.T) {
 
 	assert.Equal(t, actualResult.Success, false)
 	assert.Equal(t, actualResult.Message, "Invalid request: the email is already in use")
+	assert.Equal(t, models.CodeEmailInUse, actualResult.Code)
 }
 
 func TestCreateWithInvalidEmail(t *testing.T) {
@@ -75,7 +154,7 @@ func TestCreateWithInvalidEmail(t *testing.T) {
 
 		userServiceMock := services.UserServiceMock{}
 
-		userEndpoint := endpoints.UserEndpoint{userServiceMock}
+		userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
 		c.Request, _ = http.NewRequest("POST", "/users", bytes.NewBuffer(jsonStr))
@@ -91,6 +170,7 @@ func TestCreateWithInvalidEmail(t *testing.T) {
 
 		assert.Equal(t, false, actualResult.Success)
 		assert.Equal(t, "Invalid request: incorrect info", actualResult.Message)
+		assert.Equal(t, models.CodeValidationFailed, actualResult.Code)
 	}
 }
 
@@ -98,14 +178,12 @@ func TestCreateWithValidEmail(t *testing.T) {
 	var jsonStr = []byte(`{"email": "user@test.com"}`)
 
 	userRepositoryMock := data.UserRepositoryMock{}
-	userRepositoryMock.On("CheckUserExist", "user@test.com").Return(int64(-1))
-	userRepositoryMock.On("Create", "user@test.com").Return(true)
+	userRepositoryMock.On("Create", "user@test.com").Return(nil)
 
 	userServiceMock := services.UserServiceMock{}
-	userServiceMock.On("CheckUserExist", "user@test.com").Return(int64(-1))
-	userServiceMock.On("Create", "user@test.com").Return(true)
+	userServiceMock.On("Create", "user@test.com").Return(nil)
 
-	userEndpoint := endpoints.UserEndpoint{userServiceMock}
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
 	c.Request, _ = http.NewRequest("POST", "/users", bytes.NewBuffer(jsonStr))
@@ -121,3 +199,355 @@ func TestCreateWithValidEmail(t *testing.T) {
 
 	assert.Equal(t, actualResult.Success, true)
 }
+
+func TestSummaryReturnOk(t *testing.T) {
+	createdAt := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	user := models.User{ID: 1, Email: "user@test.com", DisplayName: "User", Status: models.UserActive, CreatedAt: &createdAt, UpdatedAt: &createdAt}
+
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("GetUser", "user@test.com").Return(user, nil)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	relationshipServiceMock.On("CountRelationships", int64(1)).Return(models.UserSummary{Friends: 3, Followers: 2, Following: 1}, nil)
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock, IRelationshipService: &relationshipServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/users/user@test.com", nil)
+	c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+	userEndpoint.Summary(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	assert.JSONEq(t, `{"id":1,"email":"user@test.com","displayName":"User","avatarUrl":"","status":"active","createdAt":"2020-05-01T10:00:00Z","updatedAt":"2020-05-01T10:00:00Z","friends":3,"followers":2,"following":1,"success":true}`, string(body))
+}
+
+func TestSummaryWithNotFoundAccount(t *testing.T) {
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("GetUser", "user@test.com").Return(models.User{}, &data.NotFoundError{Entity: "user", Key: "user@test.com"})
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock, IRelationshipService: &services.RelationshipServiceMock{}}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/users/user@test.com", nil)
+	c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+	userEndpoint.Summary(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+}
+
+func TestUpdateUserWithInvalidRequest(t *testing.T) {
+	var invalidRequests = map[string][]models.FieldError{
+		`{"displayName":1}`: {{Field: "body", Message: "must be a valid JSON object"}},
+		`{"displayName":"` + strings.Repeat("a", 65) + `"}`: {{Field: "displayName", Message: "must not exceed 64 characters"}},
+		`{"avatarUrl":"ftp://example.com/a.png"}`:           {{Field: "avatarUrl", Message: "must be an http or https url of at most 2048 characters"}},
+		`{"avatarUrl":"/a.png","status":"banned"}`: {
+			{Field: "avatarUrl", Message: "must be an http or https url of at most 2048 characters"},
+			{Field: "status", Message: "must be active, suspended or deleted"}},
+	}
+	for request, expectedDetails := range invalidRequests {
+		userEndpoint := endpoints.UserEndpoint{IUserService: &services.UserServiceMock{}}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("PATCH", "/users/user@test.com", bytes.NewBuffer([]byte(request)))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+		userEndpoint.UpdateUser(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, request)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, expectedDetails, actualResult.Details, request)
+	}
+}
+
+func TestUpdateUserReturnOk(t *testing.T) {
+	var jsonStr = []byte(`{"displayName":"John","avatarUrl":""}`)
+
+	displayName := "John"
+	avatarUrl := ""
+	user := models.User{ID: 1, Email: "user@test.com", DisplayName: "John", Status: models.UserActive}
+
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("UpdateUser", "user@test.com", models.UserPatch{DisplayName: &displayName, AvatarUrl: &avatarUrl}).Return(user, nil)
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("PATCH", "/users/user@test.com", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+	userEndpoint.UpdateUser(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	var actualResult models.UserProfile
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.UserProfile{User: user, Success: true}, actualResult)
+}
+
+func TestDeleteUserWithNotFoundAccount(t *testing.T) {
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("DeleteUser", "user@test.com").Return(models.UserDeletion{}, &data.NotFoundError{Entity: "user", Key: "user@test.com"})
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/users/user@test.com", nil)
+	c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+	userEndpoint.DeleteUser(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
+}
+
+func TestDeleteUserReturnOk(t *testing.T) {
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("DeleteUser", "user@test.com").Return(models.UserDeletion{Email: "user@test.com", Relationships: 4, Posts: 2, Mentions: 3}, nil)
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("DELETE", "/users/user@test.com", nil)
+	c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+	userEndpoint.DeleteUser(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	var actualResult models.UserDeletion
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.UserDeletion{Email: "user@test.com", Relationships: 4, Posts: 2, Mentions: 3, Success: true}, actualResult)
+}
+
+func TestChangeEmailInUse(t *testing.T) {
+	jsonStr := []byte(`{"email":"other@test.com"}`)
+
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("ChangeEmail", "user@test.com", "other@test.com").Return(models.User{}, &data.DuplicateError{Entity: "user", Key: "other@test.com"})
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("PUT", "/users/user@test.com/email", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+	userEndpoint.ChangeEmail(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.CodeEmailInUse, actualResult.Code)
+	userServiceMock.AssertExpectations(t)
+}
+
+func TestChangeEmailWithInvalidEmail(t *testing.T) {
+	jsonStr := []byte(`{"email":"invalid"}`)
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &services.UserServiceMock{}}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("PUT", "/users/user@test.com/email", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+	userEndpoint.ChangeEmail(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, []models.FieldError{{Field: "newEmail", Message: "must be a valid email"}}, actualResult.Details)
+}
+
+func TestChangeEmailReturnOk(t *testing.T) {
+	jsonStr := []byte(`{"email":"new@test.com"}`)
+
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("CheckUserExist", "new@test.com").Return(int64(0), &data.NotFoundError{Entity: "user", Key: "new@test.com"})
+	userServiceMock.On("ChangeEmail", "user@test.com", "new@test.com").Return(models.User{ID: 1, Email: "new@test.com", Status: models.UserActive}, nil)
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("PUT", "/users/user@test.com/email", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+	userEndpoint.ChangeEmail(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	var actualResult models.UserProfile
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, "new@test.com", actualResult.Email)
+	assert.True(t, actualResult.Success)
+}
+
+func TestMergeUsersWithSameUser(t *testing.T) {
+	jsonStr := []byte(`{"source":"user@test.com","target":"user@test.com"}`)
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &services.UserServiceMock{}}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/users/merge", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	userEndpoint.MergeUsers(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, []models.FieldError{{Field: "target", Message: "must differ from source"}}, actualResult.Details)
+}
+
+func TestMergeUsersReturnOk(t *testing.T) {
+	jsonStr := []byte(`{"source":"old@test.com","target":"user@test.com"}`)
+
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("MergeUsers", "old@test.com", "user@test.com").Return(models.UserMerge{Source: "old@test.com", Target: "user@test.com", Relationships: 5, Dropped: 2, Posts: 1}, nil)
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/users/merge", bytes.NewBuffer(jsonStr))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	userEndpoint.MergeUsers(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	var actualResult models.UserMerge
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.UserMerge{Source: "old@test.com", Target: "user@test.com", Relationships: 5, Dropped: 2, Posts: 1, Success: true}, actualResult)
+}
+
+func TestFollowersWithInvalidPage(t *testing.T) {
+	var invalidQueries = map[string][]models.FieldError{
+		"limit=0":           {{Field: "limit", Message: "must be between 1 and 100"}},
+		"limit=abc":         {{Field: "limit", Message: "must be between 1 and 100"}},
+		"limit=5&cursor=-1": {{Field: "cursor", Message: "must be the nextCursor of a previous page"}},
+		"cursor=LTE":        {{Field: "cursor", Message: "must be the nextCursor of a previous page"}},
+	}
+	for query, expectedDetails := range invalidQueries {
+		userServiceMock := services.UserServiceMock{}
+		userServiceMock.On("CheckUserExist", "user@test.com").Return(int64(1), nil)
+
+		userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock, IRelationshipService: &services.RelationshipServiceMock{}}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/users/user@test.com/followers?"+query, nil)
+		c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+		userEndpoint.Followers(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode, query)
+
+		var actualResult models.Failure
+		body, _ := ioutil.ReadAll(w.Result().Body)
+		json.Unmarshal(body, &actualResult)
+
+		assert.Equal(t, expectedDetails, actualResult.Details, query)
+	}
+}
+
+func TestFollowersWithNotFoundAccount(t *testing.T) {
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("CheckUserExist", "user@test.com").Return(int64(0), &data.NotFoundError{Entity: "user", Key: "user@test.com"})
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock, IRelationshipService: &services.RelationshipServiceMock{}}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/users/user@test.com/followers", nil)
+	c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+	userEndpoint.Followers(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+
+	var actualResult models.Failure
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.CodeUserNotFound, actualResult.Code)
+}
+
+func TestFollowingReturnOk(t *testing.T) {
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("CheckUserExist", "user@test.com").Return(int64(1), nil)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	relationshipServiceMock.On("GetFollowing", int64(1), models.Page{After: 4, Limit: 2}).Return([]string{"user5@test.com", "user6@test.com"}, int64(6), nil)
+	relationshipServiceMock.On("CountRelationships", int64(1)).Return(models.UserSummary{Followers: 1, Following: 7}, nil)
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock, IRelationshipService: &relationshipServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/users/user@test.com/following?limit=2&cursor=NA", nil)
+	c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+	userEndpoint.Following(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	var actualResult models.UserList
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	json.Unmarshal(body, &actualResult)
+
+	assert.Equal(t, models.UserList{Users: []string{"user5@test.com", "user6@test.com"}, Count: 2, Total: 7, NextCursor: "Ng", Success: true}, actualResult)
+}
+
+func TestBlockedUsersReturnOk(t *testing.T) {
+	blockedAt := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	blockedUsers := []models.BlockedUser{{Email: "user2@test.com", BlockedAt: &blockedAt}, {Email: "user3@test.com"}}
+
+	userServiceMock := services.UserServiceMock{}
+	userServiceMock.On("CheckUserExist", "user@test.com").Return(int64(1), nil)
+
+	relationshipServiceMock := services.RelationshipServiceMock{}
+	relationshipServiceMock.On("GetBlockedUsers", int64(1), models.Page{Limit: 20}).Return(blockedUsers, int64(0), nil)
+
+	userEndpoint := endpoints.UserEndpoint{IUserService: &userServiceMock, IRelationshipService: &relationshipServiceMock}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/users/user@test.com/blocked", nil)
+	c.Params = gin.Params{{Key: "email", Value: "user@test.com"}}
+
+	userEndpoint.BlockedUsers(c)
+
+	assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+	body, _ := ioutil.ReadAll(w.Result().Body)
+	assert.JSONEq(t, `{"blocked":[{"email":"user2@test.com","blockedAt":"2020-05-01T10:00:00Z"},{"email":"user3@test.com","blockedAt":null}],"count":2,"success":true}`, string(body))
+}
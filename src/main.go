@@ -1,9 +1,12 @@
 package main
 
 import (
+	"flag"
+	"friendMgmt/common"
 	"friendMgmt/data"
 	"friendMgmt/docs"
 	"friendMgmt/endpoints"
+	"log"
 )
 
 func main() {
@@ -14,8 +17,64 @@ func main() {
 	docs.SwaggerInfo.BasePath = "/api"
 	docs.SwaggerInfo.Schemes = []string{"http"}
 
-	db, _ := data.InitDB()
+	config := data.LoadDBConfig()
+	flag.StringVar(&config.Driver, "storage", config.Driver, "storage backend: mysql, sqlite or memory")
+	flag.StringVar(&config.Path, "db-path", config.Path, "database file used by the sqlite storage")
+	autoMigrate := flag.Bool("auto-migrate", true, "apply pending migrations before serving")
+	providerRules := flag.Bool("email-provider-rules", false, "identify users regardless of the dots and plus tags ignored by providers like Gmail")
+	flag.Parse()
+
+	common.SetProviderRules(*providerRules)
+
+	if config.Driver == data.Memory {
+		if flag.Arg(0) == "migrate" {
+			log.Fatal("migrate needs a mysql or sqlite storage, the memory storage has no schema to migrate")
+		}
+		endpoints.ConfigRoutes(data.NewMemoryRepositories(data.NewMemoryStore()))
+		return
+	}
+
+	db, err := data.InitDB(config)
+	if err != nil {
+		log.Fatal(err)
+	}
 	defer db.Close()
 
-	endpoints.ConfigRoutes(db)
+	migrator := data.Migrator{DB: db, Driver: config.Driver}
+
+	if flag.Arg(0) == "migrate" {
+		if err := migrate(migrator, flag.Arg(1)); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *autoMigrate {
+		if err := migrate(migrator, "up"); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if flag.Arg(0) == "emails" {
+		if err := emails(data.NewSQLRepositories(db), flag.Arg(1)); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "import" {
+		if err := importUsers(data.NewSQLRepositories(db), flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if flag.Arg(0) == "export" {
+		if err := exportUsers(data.NewSQLRepositories(db), flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	endpoints.ConfigRoutes(data.NewSQLRepositories(db))
 }
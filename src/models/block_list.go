@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type BlockList struct {
+	Blocked    []BlockedUser `json:"blocked"`
+	Count      int           `json:"count" example:"1"`
+	NextCursor string        `json:"nextCursor,omitempty" example:"MTI"`
+	Success    bool          `json:"success" example:"true"`
+}
+
+// BlockedUser is an user blocked by another one, BlockedAt being unknown for the blocks set before it was recorded.
+type BlockedUser struct {
+	Email     string     `json:"email" example:"janedoe@gmail.com"`
+	BlockedAt *time.Time `json:"blockedAt" example:"2020-05-01T10:00:00Z"`
+}
@@ -0,0 +1,13 @@
+package models
+
+type CommonFriendMatrixRequest struct {
+	Users []string `json:"users" example:"johndoe@gmail.com,janedoe@gmail.com,jacksmith@gmail.com"`
+}
+
+// CommonFriendMatrix holds the number of common friends of every pair of users, counts[i][j] being the count of the
+// users i and j in the order they were requested.
+type CommonFriendMatrix struct {
+	Users   []string `json:"users" example:"johndoe@gmail.com,janedoe@gmail.com"`
+	Counts  [][]int  `json:"counts"`
+	Success bool     `json:"success" example:"true"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type CommonFriendRequest struct {
+	Friends []string `json:"friends" example:"johndoe@gmail.com,janedoe@gmail.com"`
+	Limit   int      `json:"limit" example:"20"`
+	Cursor  string   `json:"cursor" example:""`
+}
@@ -1,6 +1,32 @@
 package models
 
+// Codes of a Failure, stable for clients to act on whatever the message says.
+const (
+	CodeValidationFailed   = "VALIDATION_FAILED"
+	CodeUserNotFound       = "USER_NOT_FOUND"
+	CodeUserInactive       = "USER_INACTIVE"
+	CodePostNotFound       = "POST_NOT_FOUND"
+	CodeEmailInUse         = "EMAIL_IN_USE"
+	CodeAlreadyConnected   = "ALREADY_CONNECTED"
+	CodeNotConnected       = "NOT_CONNECTED"
+	CodeAlreadySubscribed  = "ALREADY_SUBSCRIBED"
+	CodeNotSubscribed      = "NOT_SUBSCRIBED"
+	CodeBlocked            = "BLOCKED"
+	CodeNotBlocked         = "NOT_BLOCKED"
+	CodeRequestPending     = "REQUEST_PENDING"
+	CodeRequestNotFound    = "REQUEST_NOT_FOUND"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+)
+
 type Failure struct {
-	Message string `json:"message" example:"error message"`
-	Success bool   `json:"success" example:"false"`
+	Code    string       `json:"code" example:"VALIDATION_FAILED" enums:"VALIDATION_FAILED,USER_NOT_FOUND,USER_INACTIVE,POST_NOT_FOUND,EMAIL_IN_USE,ALREADY_CONNECTED,NOT_CONNECTED,ALREADY_SUBSCRIBED,NOT_SUBSCRIBED,BLOCKED,NOT_BLOCKED,REQUEST_PENDING,REQUEST_NOT_FOUND,SERVICE_UNAVAILABLE"`
+	Message string       `json:"message" example:"error message"`
+	Details []FieldError `json:"details,omitempty"`
+	Success bool         `json:"success" example:"false"`
+}
+
+// FieldError tells which field of the request body is invalid and why.
+type FieldError struct {
+	Field   string `json:"field" example:"friends[1]"`
+	Message string `json:"message" example:"must be a valid email"`
 }
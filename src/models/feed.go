@@ -0,0 +1,8 @@
+package models
+
+type Feed struct {
+	Posts      []Post `json:"posts"`
+	Count      int    `json:"count" example:"1"`
+	NextCursor string `json:"nextCursor,omitempty" example:"MTI"`
+	Success    bool   `json:"success" example:"true"`
+}
@@ -0,0 +1,7 @@
+package models
+
+type FeedRequest struct {
+	Email  string `json:"email" example:"johndoe@gmail.com"`
+	Limit  int    `json:"limit" example:"20"`
+	Cursor string `json:"cursor" example:""`
+}
@@ -1,7 +1,8 @@
 package models
 
 type Friend struct {
-	Friends []string `json:"friends" example:"johndoe@gmail.com,janedoe@gmail.com"`
-	Count   int      `json:"count" example:"2"`
-	Success bool     `json:"success" example:"true"`
+	Friends    []string `json:"friends" example:"johndoe@gmail.com,janedoe@gmail.com"`
+	Count      int      `json:"count" example:"2"`
+	NextCursor string   `json:"nextCursor,omitempty" example:"MTI"`
+	Success    bool     `json:"success" example:"true"`
 }
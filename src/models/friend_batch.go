@@ -0,0 +1,21 @@
+package models
+
+// FriendOperationResult is the result of an operation of a batch: the outcome of a successful operation, or the code
+// and message of the failure the same operation would answer alone.
+type FriendOperationResult struct {
+	FriendOperation
+	Outcome string `json:"outcome,omitempty" example:"FRIEND_REQUEST_SENT"`
+	Code    string `json:"code,omitempty" example:"BLOCKED"`
+	Message string `json:"message,omitempty" example:"blocked status is existed"`
+	Success bool   `json:"success" example:"true"`
+}
+
+// FriendBatch reports the result of every operation of a batch in their order. Nothing is stored when an atomic
+// batch is rolled back, the results then telling what each operation did before the rollback.
+type FriendBatch struct {
+	Results    []FriendOperationResult `json:"results"`
+	Succeeded  int                     `json:"succeeded" example:"98"`
+	Failed     int                     `json:"failed" example:"2"`
+	RolledBack bool                    `json:"rolledBack" example:"false"`
+	Success    bool                    `json:"success" example:"true"`
+}
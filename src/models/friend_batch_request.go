@@ -0,0 +1,28 @@
+package models
+
+// FriendOperationType is the relationship operation of a batch: a friend request, a subscription or a block.
+type FriendOperationType string
+
+const (
+	FriendOperationFriend    FriendOperationType = "friend"
+	FriendOperationSubscribe FriendOperationType = "subscribe"
+	FriendOperationBlock     FriendOperationType = "block"
+)
+
+// IsValid reports whether operation is one of the known operations.
+func (operation FriendOperationType) IsValid() bool {
+	return operation == FriendOperationFriend || operation == FriendOperationSubscribe || operation == FriendOperationBlock
+}
+
+type FriendOperation struct {
+	Type      FriendOperationType `json:"type" enums:"friend,subscribe,block" example:"friend"`
+	Requestor string              `json:"requestor" example:"johndoe@gmail.com"`
+	Target    string              `json:"target" example:"janedoe@gmail.com"`
+}
+
+// FriendBatchRequest holds the operations of a batch, applied in their order. An atomic batch is rolled back
+// entirely when any of its operations fails.
+type FriendBatchRequest struct {
+	Operations []FriendOperation `json:"operations"`
+	Atomic     bool              `json:"atomic" example:"false"`
+}
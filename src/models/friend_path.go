@@ -0,0 +1,8 @@
+package models
+
+type FriendPath struct {
+	Connected bool     `json:"connected" example:"true"`
+	Path      []string `json:"path" example:"kate@gmail.com"`
+	Length    int      `json:"length" example:"2"`
+	Success   bool     `json:"success" example:"true"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type FriendRequest struct {
+	Requests   []string `json:"requests" example:"johndoe@gmail.com,janedoe@gmail.com"`
+	Count      int      `json:"count" example:"2"`
+	NextCursor string   `json:"nextCursor,omitempty" example:"MTI"`
+	Success    bool     `json:"success" example:"true"`
+}
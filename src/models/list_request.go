@@ -0,0 +1,8 @@
+package models
+
+// ListRequest asks for a page of a list of an user, Cursor being the nextCursor of the previous page or empty for the first one.
+type ListRequest struct {
+	Email  string `json:"email" example:"johndoe@gmail.com"`
+	Limit  int    `json:"limit" example:"20"`
+	Cursor string `json:"cursor" example:""`
+}
@@ -0,0 +1,10 @@
+package models
+
+// Page selects a part of a list by keyset: at most Limit items following the one keyed After in the list order.
+// After is 0 to start from the first item. The lists which aren't ordered by key continue after AfterSort too, the
+// value they are ordered by of the item keyed After, so that they don't depend on this item still existing.
+type Page struct {
+	After     int64
+	AfterSort string
+	Limit     int
+}
@@ -0,0 +1,6 @@
+package models
+
+type PathRequest struct {
+	Friends  []string `json:"friends" example:"johndoe@gmail.com,janedoe@gmail.com"`
+	MaxDepth int      `json:"maxDepth" example:"6"`
+}
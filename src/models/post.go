@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+type Post struct {
+	ID        int64     `json:"id" example:"1"`
+	Sender    string    `json:"sender" example:"janedoe@gmail.com"`
+	Text      string    `json:"text" example:"hello johndoe@gmail.com"`
+	Mentions  []string  `json:"mentions" example:"johndoe@gmail.com"`
+	CreatedAt time.Time `json:"createdAt" example:"2020-04-13T11:46:35Z"`
+}
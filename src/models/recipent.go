@@ -1,6 +1,8 @@
 package models
 
 type Recipent struct {
-	Recipents []string `json:"recipents" example:"johndoe@gmail.com,janedoe@gmail.com"`
-	Success   bool     `json:"success" example:"true"`
+	PostId     int64    `json:"postId" example:"1"`
+	Recipents  []string `json:"recipents" example:"johndoe@gmail.com,janedoe@gmail.com"`
+	NextCursor string   `json:"nextCursor,omitempty" example:"MTI"`
+	Success    bool     `json:"success" example:"true"`
 }
@@ -1,8 +1,12 @@
 package models
 
+import "time"
+
 type Relationship struct {
-	ID            int64 `json:"id"`
-	RequestUserId int64 `json:"requestUserId"`
-	TargetUserId  int64 `json:"targetUserId"`
-	Status        int64 `json:"status"`
+	ID            int64              `json:"id"`
+	RequestUserId int64              `json:"requestUserId"`
+	TargetUserId  int64              `json:"targetUserId"`
+	Status        RelationshipStatus `json:"status" swaggertype:"string" enums:"friend,subscribed,blocked,pending"`
+	// CreatedAt is unknown for the relationships created before it was recorded.
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
 }
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// RelationshipStatus is the status of a relationship from an user to another one, stored as an integer
+// and exposed by its name.
+type RelationshipStatus int64
+
+const (
+	RelationshipNone       RelationshipStatus = 0
+	RelationshipFriend     RelationshipStatus = 1
+	RelationshipSubscribed RelationshipStatus = 2
+	RelationshipBlocked    RelationshipStatus = 3
+	RelationshipPending    RelationshipStatus = 4
+)
+
+var relationshipStatusNames = map[RelationshipStatus]string{
+	RelationshipNone:       "none",
+	RelationshipFriend:     "friend",
+	RelationshipSubscribed: "subscribed",
+	RelationshipBlocked:    "blocked",
+	RelationshipPending:    "pending",
+}
+
+// relationshipTransitions lists the statuses a relationship can move to from each status, none being the absence
+// of relationship. A pending request or a subscription becomes a friend connection when the request is accepted.
+var relationshipTransitions = map[RelationshipStatus][]RelationshipStatus{
+	RelationshipNone:       {RelationshipPending, RelationshipSubscribed, RelationshipBlocked},
+	RelationshipPending:    {RelationshipFriend, RelationshipBlocked, RelationshipNone},
+	RelationshipSubscribed: {RelationshipFriend, RelationshipBlocked, RelationshipNone},
+	RelationshipFriend:     {RelationshipBlocked, RelationshipNone},
+	RelationshipBlocked:    {RelationshipNone},
+}
+
+// IsValid reports whether status is one of the known statuses.
+func (status RelationshipStatus) IsValid() bool {
+	_, ok := relationshipStatusNames[status]
+	return ok
+}
+
+// CanBecome reports whether the transition table allows a relationship to move from status to next.
+func (status RelationshipStatus) CanBecome(next RelationshipStatus) bool {
+	for _, allowed := range relationshipTransitions[status] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (status RelationshipStatus) String() string {
+	if name, ok := relationshipStatusNames[status]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("RelationshipStatus(%d)", int64(status))
+}
+
+func (status RelationshipStatus) MarshalText() ([]byte, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("unknown relationship status %d", int64(status))
+	}
+
+	return []byte(status.String()), nil
+}
+
+func (status *RelationshipStatus) UnmarshalText(text []byte) error {
+	for value, name := range relationshipStatusNames {
+		if name == string(text) {
+			*status = value
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown relationship status %q", text)
+}
+
+// Value stores the status as its integer, none is not a status a relationship can be stored with.
+func (status RelationshipStatus) Value() (driver.Value, error) {
+	if !status.IsValid() || status == RelationshipNone {
+		return nil, fmt.Errorf("unknown relationship status %d", int64(status))
+	}
+
+	return int64(status), nil
+}
+
+// Scan reads a status stored as an integer, rejecting unknown values and none.
+func (status *RelationshipStatus) Scan(value interface{}) error {
+	var scanned RelationshipStatus
+	switch v := value.(type) {
+	case int64:
+		scanned = RelationshipStatus(v)
+	case []byte:
+		if _, err := fmt.Sscan(string(v), (*int64)(&scanned)); err != nil {
+			return fmt.Errorf("unknown relationship status %q", v)
+		}
+	default:
+		return fmt.Errorf("unknown relationship status %v", value)
+	}
+
+	if !scanned.IsValid() || scanned == RelationshipNone {
+		return fmt.Errorf("unknown relationship status %d", int64(scanned))
+	}
+	*status = scanned
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package models_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"friendMgmt/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelationshipStatusTransitions(t *testing.T) {
+	assert.True(t, models.RelationshipNone.CanBecome(models.RelationshipSubscribed))
+	assert.True(t, models.RelationshipSubscribed.CanBecome(models.RelationshipFriend))
+	assert.True(t, models.RelationshipFriend.CanBecome(models.RelationshipBlocked))
+	assert.True(t, models.RelationshipBlocked.CanBecome(models.RelationshipNone))
+
+	assert.False(t, models.RelationshipNone.CanBecome(models.RelationshipFriend))
+	assert.False(t, models.RelationshipBlocked.CanBecome(models.RelationshipFriend))
+	assert.False(t, models.RelationshipFriend.CanBecome(models.RelationshipPending))
+}
+
+func TestRelationshipStatusJSON(t *testing.T) {
+	body, err := json.Marshal(models.Relationship{Status: models.RelationshipBlocked, RequestUserId: 1, TargetUserId: 2})
+	assert.Nil(t, err)
+	assert.Contains(t, string(body), `"status":"blocked"`)
+
+	var relationship models.Relationship
+	assert.Nil(t, json.Unmarshal(body, &relationship))
+	assert.Equal(t, models.RelationshipBlocked, relationship.Status)
+
+	assert.NotNil(t, json.Unmarshal([]byte(`{"status":"enemy"}`), &relationship))
+}
+
+func TestRelationshipStatusScan(t *testing.T) {
+	var status models.RelationshipStatus
+	assert.Nil(t, status.Scan(int64(2)))
+	assert.Equal(t, models.RelationshipSubscribed, status)
+
+	assert.Nil(t, status.Scan([]byte("4")))
+	assert.Equal(t, models.RelationshipPending, status)
+
+	assert.NotNil(t, status.Scan(int64(9)))
+	assert.NotNil(t, status.Scan(int64(0)))
+
+	_, err := models.RelationshipNone.Value()
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,17 @@
+package models
+
+// RelationshipView tells what the target is to the requestor, each flag being read from the requestor's side.
+// Outgoing lists the statuses of the relationships from the requestor to the target and Incoming the ones the other way
+// round, a friend connection being listed on both sides.
+type RelationshipView struct {
+	Outgoing        []RelationshipStatus `json:"outgoing" swaggertype:"array,string" enums:"friend,subscribed,blocked,pending" example:"pending"`
+	Incoming        []RelationshipStatus `json:"incoming" swaggertype:"array,string" enums:"friend,subscribed,blocked,pending" example:"subscribed"`
+	Friends         bool                 `json:"friends" example:"false"`
+	Subscribed      bool                 `json:"subscribed" example:"true"`
+	SubscribedBy    bool                 `json:"subscribedBy" example:"false"`
+	Blocked         bool                 `json:"blocked" example:"false"`
+	BlockedBy       bool                 `json:"blockedBy" example:"false"`
+	RequestSent     bool                 `json:"requestSent" example:"true"`
+	RequestReceived bool                 `json:"requestReceived" example:"false"`
+	Success         bool                 `json:"success" example:"true"`
+}
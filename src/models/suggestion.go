@@ -0,0 +1,12 @@
+package models
+
+type SuggestedFriend struct {
+	Email         string `json:"email" example:"johndoe@gmail.com"`
+	MutualFriends int    `json:"mutualFriends" example:"2"`
+}
+
+type Suggestion struct {
+	Suggestions []SuggestedFriend `json:"suggestions"`
+	Count       int               `json:"count" example:"1"`
+	Success     bool              `json:"success" example:"true"`
+}
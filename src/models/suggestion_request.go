@@ -0,0 +1,6 @@
+package models
+
+type SuggestionRequest struct {
+	Email string `json:"email" example:"johndoe@gmail.com"`
+	Limit int    `json:"limit" example:"10"`
+}
@@ -1,6 +1,20 @@
 package models
 
+import "time"
+
+// Limits of the profile fields of an user.
+const (
+	MaxDisplayNameLength = 64
+	MaxAvatarUrlLength   = 2048
+)
+
+// User is an user account with its profile. The timestamps are unknown for the users created before they were recorded.
 type User struct {
-	ID    int    `json:"id"`
-	Email string `json:"email"`
+	ID          int64      `json:"id" example:"1"`
+	Email       string     `json:"email" example:"johndoe@gmail.com"`
+	DisplayName string     `json:"displayName" example:"John Doe"`
+	AvatarUrl   string     `json:"avatarUrl" example:"https://example.com/johndoe.png"`
+	Status      UserStatus `json:"status" enums:"active,suspended,deleted" example:"active"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt   *time.Time `json:"updatedAt,omitempty"`
 }
@@ -0,0 +1,11 @@
+package models
+
+// UserDeletion reports what was erased with an user: its relationships in both directions, its posts
+// and the mentions of the user or in its posts.
+type UserDeletion struct {
+	Email         string `json:"email" example:"johndoe@gmail.com"`
+	Relationships int    `json:"relationships" example:"12"`
+	Posts         int    `json:"posts" example:"3"`
+	Mentions      int    `json:"mentions" example:"5"`
+	Success       bool   `json:"success" example:"true"`
+}
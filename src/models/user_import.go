@@ -0,0 +1,35 @@
+package models
+
+// ImportResult tells what an import did with a record.
+type ImportResult string
+
+const (
+	// ImportCreated is a record whose user or relationship was created.
+	ImportCreated ImportResult = "created"
+	// ImportExisting is a record whose user or relationship already existed, so importing a file again changes nothing.
+	ImportExisting ImportResult = "existing"
+	// ImportInvalid is a record failing the validation, detailed by field.
+	ImportInvalid ImportResult = "invalid"
+	// ImportConflict is a relationship the relationships between both users don't allow, the code tells why.
+	ImportConflict ImportResult = "conflict"
+)
+
+// UserImportRow is the result of a record, numbered from 1 in the order of the file.
+type UserImportRow struct {
+	Row     int          `json:"row" example:"3"`
+	Result  ImportResult `json:"result" enums:"created,existing,invalid,conflict" example:"conflict"`
+	Code    string       `json:"code,omitempty" example:"BLOCKED"`
+	Message string       `json:"message,omitempty" example:"blocked status is existed"`
+	Details []FieldError `json:"details,omitempty"`
+}
+
+// UserImport reports the result of every record of an import and counts them. Nothing is stored by a dry run.
+type UserImport struct {
+	DryRun    bool            `json:"dryRun" example:"false"`
+	Created   int             `json:"created" example:"120"`
+	Existing  int             `json:"existing" example:"4"`
+	Invalid   int             `json:"invalid" example:"1"`
+	Conflicts int             `json:"conflicts" example:"1"`
+	Rows      []UserImportRow `json:"rows"`
+	Success   bool            `json:"success" example:"true"`
+}
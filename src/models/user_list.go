@@ -0,0 +1,10 @@
+package models
+
+// UserList is a page of users, Total counting them all when the list tells it.
+type UserList struct {
+	Users      []string `json:"users" example:"johndoe@gmail.com,janedoe@gmail.com"`
+	Count      int      `json:"count" example:"2"`
+	Total      int      `json:"total,omitempty" example:"40"`
+	NextCursor string   `json:"nextCursor,omitempty" example:"MTI"`
+	Success    bool     `json:"success" example:"true"`
+}
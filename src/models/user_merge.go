@@ -0,0 +1,13 @@
+package models
+
+// UserMerge reports the merge of an user into another one: the relationships the target holds afterwards, the ones
+// of both users which were not carried over, and the posts and mentions moved to the target.
+type UserMerge struct {
+	Source        string `json:"source" example:"johndoe.old@gmail.com"`
+	Target        string `json:"target" example:"johndoe@gmail.com"`
+	Relationships int    `json:"relationships" example:"12"`
+	Dropped       int    `json:"dropped" example:"2"`
+	Posts         int    `json:"posts" example:"3"`
+	Mentions      int    `json:"mentions" example:"5"`
+	Success       bool   `json:"success" example:"true"`
+}
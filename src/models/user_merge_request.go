@@ -0,0 +1,6 @@
+package models
+
+type UserMergeRequest struct {
+	Source string `json:"source" example:"johndoe.old@gmail.com"`
+	Target string `json:"target" example:"johndoe@gmail.com"`
+}
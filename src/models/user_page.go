@@ -0,0 +1,9 @@
+package models
+
+// UserPage is a page of user accounts.
+type UserPage struct {
+	Users      []User `json:"users"`
+	Count      int    `json:"count" example:"2"`
+	NextCursor string `json:"nextCursor,omitempty" example:"MTI"`
+	Success    bool   `json:"success" example:"true"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// UserPatch holds the profile fields to change, the ones left out are kept.
+type UserPatch struct {
+	DisplayName *string     `json:"displayName" example:"John Doe"`
+	AvatarUrl   *string     `json:"avatarUrl" example:"https://example.com/johndoe.png"`
+	Status      *UserStatus `json:"status" enums:"active,suspended,deleted" example:"suspended"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type UserProfile struct {
+	User
+	Success bool `json:"success" example:"true"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// UserRecord is a record of an import or export of the users graph: an user with its profile, or a relationship
+// from the user to the target. Status is the status of the user or the one of the relationship, CreatedAt the RFC 3339
+// time a relationship was created at, which is unknown for the relationships created before it was recorded.
+type UserRecord struct {
+	Type        UserRecordType `json:"type" enums:"user,relationship" example:"user"`
+	Email       string         `json:"email" example:"johndoe@gmail.com"`
+	DisplayName string         `json:"displayName,omitempty" example:"John Doe"`
+	AvatarUrl   string         `json:"avatarUrl,omitempty" example:"https://example.com/johndoe.png"`
+	Status      string         `json:"status,omitempty" example:"active"`
+	Target      string         `json:"target,omitempty" example:"janedoe@gmail.com"`
+	CreatedAt   string         `json:"createdAt,omitempty" example:"2020-01-02T15:04:05Z"`
+}
+
+type UserRecordType string
+
+const (
+	UserRecordUser         UserRecordType = "user"
+	UserRecordRelationship UserRecordType = "relationship"
+)
+
+// RecordFormat is the encoding of the records of an import or export.
+type RecordFormat string
+
+const (
+	// RecordFormatCSV is a CSV file with a header naming the columns among type, email, displayName, avatarUrl,
+	// status, target and createdAt.
+	RecordFormatCSV RecordFormat = "csv"
+	// RecordFormatNDJSON holds a JSON object per line.
+	RecordFormatNDJSON RecordFormat = "ndjson"
+)
+
+// IsValid reports whether format is one of the known formats.
+func (format RecordFormat) IsValid() bool {
+	return format == RecordFormatCSV || format == RecordFormatNDJSON
+}
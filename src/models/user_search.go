@@ -0,0 +1,29 @@
+package models
+
+// UserSort orders the users found by a search.
+type UserSort string
+
+const (
+	UserSortId        UserSort = "id"
+	UserSortEmail     UserSort = "email"
+	UserSortEmailDesc UserSort = "-email"
+)
+
+// UserSearch asks for a page of the users whose email starts with Query, or contains it when Match is "contains",
+// optionally restricted to a Domain, Cursor being the nextCursor of the previous page or empty for the first one.
+type UserSearch struct {
+	Query  string   `json:"query" example:"john"`
+	Match  string   `json:"match" enums:"prefix,contains" example:"prefix"`
+	Domain string   `json:"domain" example:"example.com"`
+	Sort   UserSort `json:"sort" enums:"id,email,-email" example:"email"`
+	Limit  int      `json:"limit" example:"20"`
+	Cursor string   `json:"cursor" example:""`
+}
+
+// UserFilter selects users by email, matching is case insensitive. An empty Query or Domain doesn't filter.
+type UserFilter struct {
+	Query    string
+	Contains bool
+	Domain   string
+	Sort     UserSort
+}
@@ -0,0 +1,15 @@
+package models
+
+// UserStatus is the lifecycle status of an user account.
+type UserStatus string
+
+const (
+	UserActive    UserStatus = "active"
+	UserSuspended UserStatus = "suspended"
+	UserDeleted   UserStatus = "deleted"
+)
+
+// IsValid reports whether status is one of the known statuses.
+func (status UserStatus) IsValid() bool {
+	return status == UserActive || status == UserSuspended || status == UserDeleted
+}
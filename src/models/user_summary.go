@@ -0,0 +1,10 @@
+package models
+
+// UserSummary is an user with the counts of its relationships.
+type UserSummary struct {
+	User
+	Friends   int  `json:"friends" example:"12"`
+	Followers int  `json:"followers" example:"40"`
+	Following int  `json:"following" example:"7"`
+	Success   bool `json:"success" example:"true"`
+}
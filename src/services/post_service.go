@@ -0,0 +1,29 @@
+package services
+
+import (
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"time"
+)
+
+type IPostService interface {
+	CreatePost(senderId int64, text string, mentionIds []int64) (int64, error)
+	GetFeed(userId int64, page models.Page) ([]models.Post, int64, error)
+	GetPostAudience(postId int64) (int64, []int64, error)
+}
+
+type PostService struct {
+	IPostRepository data.IPostRepository
+}
+
+func (svc PostService) CreatePost(senderId int64, text string, mentionIds []int64) (int64, error) {
+	return svc.IPostRepository.CreatePost(senderId, text, mentionIds, time.Now().UTC())
+}
+
+func (svc PostService) GetFeed(userId int64, page models.Page) ([]models.Post, int64, error) {
+	return svc.IPostRepository.GetFeed(userId, page)
+}
+
+func (svc PostService) GetPostAudience(postId int64) (int64, []int64, error) {
+	return svc.IPostRepository.GetPostAudience(postId)
+}
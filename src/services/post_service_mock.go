@@ -0,0 +1,29 @@
+package services
+
+import (
+	"friendMgmt/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type PostServiceMock struct {
+	mock.Mock
+}
+
+func (m *PostServiceMock) CreatePost(senderId int64, text string, mentionIds []int64) (int64, error) {
+	args := m.Called(senderId, text, mentionIds)
+
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *PostServiceMock) GetFeed(userId int64, page models.Page) ([]models.Post, int64, error) {
+	args := m.Called(userId, page)
+
+	return args.Get(0).([]models.Post), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *PostServiceMock) GetPostAudience(postId int64) (int64, []int64, error) {
+	args := m.Called(postId)
+
+	return args.Get(0).(int64), args.Get(1).([]int64), args.Error(2)
+}
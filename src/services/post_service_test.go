@@ -0,0 +1,60 @@
+package services_test
+
+import (
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"friendMgmt/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreatePost(t *testing.T) {
+	mentionIds := []int64{int64(2), int64(3)}
+
+	postRepositoryMock := data.PostRepositoryMock{}
+	postRepositoryMock.On("CreatePost", int64(1), "hello", mentionIds, mock.AnythingOfType("time.Time")).Return(int64(10), nil)
+
+	postService := services.PostService{&postRepositoryMock}
+
+	actualResult, err := postService.CreatePost(int64(1), "hello", mentionIds)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), actualResult)
+
+	postRepositoryMock.AssertExpectations(t)
+}
+
+func TestGetFeed(t *testing.T) {
+	expectedResult := []models.Post{{ID: 1, Sender: "user1@gmail.com", Text: "hello", CreatedAt: time.Now()}}
+
+	postRepositoryMock := data.PostRepositoryMock{}
+	postRepositoryMock.On("GetFeed", int64(1), models.Page{Limit: 20}).Return(expectedResult, int64(0), nil)
+
+	postService := services.PostService{&postRepositoryMock}
+
+	actualResult, next, err := postService.GetFeed(int64(1), models.Page{Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+	assert.Equal(t, int64(0), next)
+
+	postRepositoryMock.AssertExpectations(t)
+}
+
+func TestGetPostAudience(t *testing.T) {
+	postRepositoryMock := data.PostRepositoryMock{}
+	postRepositoryMock.On("GetPostAudience", int64(10)).Return(int64(1), []int64{2, 3}, nil)
+
+	postService := services.PostService{&postRepositoryMock}
+
+	senderId, mentionIds, err := postService.GetPostAudience(int64(10))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), senderId)
+	assert.Equal(t, []int64{2, 3}, mentionIds)
+
+	postRepositoryMock.AssertExpectations(t)
+}
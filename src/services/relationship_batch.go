@@ -0,0 +1,124 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"friendMgmt/common"
+	"friendMgmt/data"
+	"friendMgmt/models"
+)
+
+// errBatchRollback rolls back the unit of work of an atomic batch with a failed operation.
+var errBatchRollback = errors.New("batch rolled back")
+
+// ApplyBatch applies the operations in their order in a single transaction, with the semantics of Befriend, Subscribe
+// and Block, and reports the result of each of them. The users of all the operations are resolved at once. A failed
+// operation is skipped, unless the batch is atomic in which case every operation is rolled back.
+func (svc RelationshipService) ApplyBatch(operations []models.FriendOperation, atomic bool) (models.FriendBatch, error) {
+	var batch models.FriendBatch
+	err := svc.IUnitOfWork.Do(func(repositories data.Repositories) error {
+		batch = models.FriendBatch{Results: make([]models.FriendOperationResult, 0, len(operations))}
+
+		users, err := resolveUsers(repositories.IUserRepository, operations)
+		if err != nil {
+			return err
+		}
+
+		tx := RelationshipService{IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+		for _, operation := range operations {
+			result := models.FriendOperationResult{FriendOperation: operation}
+			if err := tx.applyOperation(operation, users, &result); err != nil {
+				return err
+			}
+
+			if result.Success {
+				batch.Succeeded++
+			} else {
+				batch.Failed++
+			}
+			batch.Results = append(batch.Results, result)
+		}
+
+		if atomic && batch.Failed > 0 {
+			return errBatchRollback
+		}
+
+		return nil
+	})
+	if err == errBatchRollback {
+		batch.RolledBack = true
+		return batch, nil
+	}
+	if err != nil {
+		return models.FriendBatch{}, err
+	}
+
+	return batch, nil
+}
+
+// resolveUsers returns the users of the operations by their normalized email.
+func resolveUsers(users data.IUserRepository, operations []models.FriendOperation) (map[string]models.User, error) {
+	var emails []string
+	for _, operation := range operations {
+		emails = append(emails, operation.Requestor, operation.Target)
+	}
+
+	found, err := users.GetUsers(emails)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := make(map[string]models.User, len(found))
+	for _, user := range found {
+		resolved[common.NormalizeEmail(user.Email)] = user
+	}
+
+	return resolved, nil
+}
+
+// applyOperation applies an operation between users resolved by users and fills its result. Only the storage
+// errors are returned, the failures of the operation are reported by the result.
+func (svc RelationshipService) applyOperation(operation models.FriendOperation, users map[string]models.User, result *models.FriendOperationResult) error {
+	for _, email := range []string{operation.Requestor, operation.Target} {
+		user, ok := users[common.NormalizeEmail(email)]
+		if !ok {
+			result.Code = models.CodeUserNotFound
+			result.Message = fmt.Sprintf("user %s is not found", email)
+			return nil
+		}
+		if err := checkActive(user); err != nil {
+			result.Code = models.CodeUserInactive
+			result.Message = err.Error()
+			return nil
+		}
+	}
+	requestUserId := users[common.NormalizeEmail(operation.Requestor)].ID
+	targetUserId := users[common.NormalizeEmail(operation.Target)].ID
+
+	var outcome Outcome
+	var err error
+	switch operation.Type {
+	case models.FriendOperationFriend:
+		outcome, err = svc.befriend(requestUserId, targetUserId)
+	case models.FriendOperationSubscribe:
+		outcome, err = svc.subscribe(requestUserId, targetUserId)
+	case models.FriendOperationBlock:
+		outcome, err = svc.block(requestUserId, targetUserId)
+	default:
+		return fmt.Errorf("unknown relationship operation %q", operation.Type)
+	}
+
+	if relationshipErr, ok := err.(*RelationshipError); ok {
+		result.Code = relationshipErr.Code
+		result.Message = relationshipErr.Message
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	result.Outcome = string(outcome)
+	result.Success = true
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"friendMgmt/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// batchOperations succeed up to the last two, which fail on the block and on an unknown user.
+var batchOperations = []models.FriendOperation{
+	{Type: models.FriendOperationFriend, Requestor: "a@test.com", Target: "b@test.com"},
+	{Type: models.FriendOperationFriend, Requestor: "b@test.com", Target: "A@test.com"},
+	{Type: models.FriendOperationSubscribe, Requestor: "c@test.com", Target: "a@test.com"},
+	{Type: models.FriendOperationBlock, Requestor: "a@test.com", Target: "c@test.com"},
+	{Type: models.FriendOperationFriend, Requestor: "c@test.com", Target: "a@test.com"},
+	{Type: models.FriendOperationSubscribe, Requestor: "a@test.com", Target: "x@test.com"},
+}
+
+var batchResults = []models.FriendOperationResult{
+	{FriendOperation: batchOperations[0], Outcome: "FRIEND_REQUEST_SENT", Success: true},
+	{FriendOperation: batchOperations[1], Outcome: "FRIEND_REQUEST_ACCEPTED", Success: true},
+	{FriendOperation: batchOperations[2], Outcome: "SUBSCRIBED", Success: true},
+	{FriendOperation: batchOperations[3], Outcome: "BLOCKED", Success: true},
+	{FriendOperation: batchOperations[4], Code: models.CodeBlocked, Message: "blocked status is existed"},
+	{FriendOperation: batchOperations[5], Code: models.CodeUserNotFound, Message: "user x@test.com is not found"},
+}
+
+func newBatchRepositories() data.Repositories {
+	repositories := data.NewMemoryRepositories(data.NewMemoryStore())
+	for _, email := range []string{"a@test.com", "b@test.com", "c@test.com"} {
+		repositories.IUserRepository.Create(email, time.Now())
+	}
+
+	return repositories
+}
+
+func TestApplyBatch(t *testing.T) {
+	repositories := newBatchRepositories()
+	relationshipService := services.RelationshipService{IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+
+	actualResult, err := relationshipService.ApplyBatch(batchOperations, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.FriendBatch{Results: batchResults, Succeeded: 4, Failed: 2}, actualResult)
+	assert.Equal(t, []string{"b@test.com"}, noErrPage(repositories.IRelationshipRepository.GetFriendList(1, models.Page{Limit: 10})))
+	assert.Empty(t, noErrPage(repositories.IRelationshipRepository.GetIncomingFriendRequests(1, models.Page{Limit: 10})))
+}
+
+func TestApplyBatchAtomicRollsBack(t *testing.T) {
+	repositories := newBatchRepositories()
+	relationshipService := services.RelationshipService{IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+
+	actualResult, err := relationshipService.ApplyBatch(batchOperations, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.FriendBatch{Results: batchResults, Succeeded: 4, Failed: 2, RolledBack: true}, actualResult)
+	relationships, err := repositories.IRelationshipRepository.GetUserRelationships(1)
+	assert.NoError(t, err)
+	assert.Empty(t, relationships)
+}
+
+func TestApplyBatchAtomicCommits(t *testing.T) {
+	repositories := newBatchRepositories()
+	relationshipService := services.RelationshipService{IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+
+	actualResult, err := relationshipService.ApplyBatch(batchOperations[:4], true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.FriendBatch{Results: batchResults[:4], Succeeded: 4}, actualResult)
+	assert.Equal(t, []string{"b@test.com"}, noErrPage(repositories.IRelationshipRepository.GetFriendList(1, models.Page{Limit: 10})))
+}
+
+func TestApplyBatchWithSuspendedUser(t *testing.T) {
+	repositories := newBatchRepositories()
+	suspended, _ := repositories.IUserRepository.GetUser("c@test.com")
+	suspended.Status = models.UserSuspended
+	repositories.IUserRepository.UpdateUser(suspended)
+	relationshipService := services.RelationshipService{IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+
+	actualResult, err := relationshipService.ApplyBatch(batchOperations[2:4], false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.FriendBatch{Results: []models.FriendOperationResult{
+		{FriendOperation: batchOperations[2], Code: models.CodeUserInactive, Message: "user c@test.com is suspended"},
+		{FriendOperation: batchOperations[3], Code: models.CodeUserInactive, Message: "user c@test.com is suspended"},
+	}, Failed: 2}, actualResult)
+	relationships, err := repositories.IRelationshipRepository.GetUserRelationships(3)
+	assert.NoError(t, err)
+	assert.Empty(t, relationships)
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"fmt"
+	"friendMgmt/models"
+)
+
+// Outcome tells what a relationship operation did, so every transport reports the same result.
+type Outcome string
+
+const (
+	FriendRequestSent     Outcome = "FRIEND_REQUEST_SENT"
+	FriendRequestAccepted Outcome = "FRIEND_REQUEST_ACCEPTED"
+	FriendRequestRejected Outcome = "FRIEND_REQUEST_REJECTED"
+	FriendRequestCanceled Outcome = "FRIEND_REQUEST_CANCELED"
+	FriendRemoved         Outcome = "FRIEND_REMOVED"
+	Subscribed            Outcome = "SUBSCRIBED"
+	AlreadyFriends        Outcome = "ALREADY_FRIENDS"
+	Unsubscribed          Outcome = "UNSUBSCRIBED"
+	Blocked               Outcome = "BLOCKED"
+	Unblocked             Outcome = "UNBLOCKED"
+)
+
+// RelationshipError tells that the relationships between two users don't allow the requested change.
+type RelationshipError struct {
+	Code    string
+	Message string
+}
+
+func (err *RelationshipError) Error() string {
+	return err.Message
+}
+
+// checkActive returns a RelationshipError unless the user is active, suspended and deleted users taking no part in
+// relationships.
+func checkActive(user models.User) error {
+	if user.Status != models.UserActive {
+		return &RelationshipError{Code: models.CodeUserInactive, Message: fmt.Sprintf("user %s is %s", user.Email, user.Status)}
+	}
+
+	return nil
+}
+
+// existingRelationshipError returns the RelationshipError telling that a relationship in the status already exists.
+func existingRelationshipError(status models.RelationshipStatus) error {
+	switch status {
+	case models.RelationshipFriend:
+		return &RelationshipError{Code: models.CodeAlreadyConnected, Message: "connected status is existed"}
+	case models.RelationshipSubscribed:
+		return &RelationshipError{Code: models.CodeAlreadySubscribed, Message: "subcribed status is existed"}
+	case models.RelationshipBlocked:
+		return &RelationshipError{Code: models.CodeBlocked, Message: "blocked status is existed"}
+	default:
+		return &RelationshipError{Code: models.CodeRequestPending, Message: "pending request is existed"}
+	}
+}
@@ -1,63 +1,560 @@
 package services
 
 import (
+	"fmt"
 	"friendMgmt/data"
 	"friendMgmt/models"
+	"sort"
+	"time"
 )
 
 type IRelationshipService interface {
-	CreateRelationship(relationship *models.Relationship) int64
-	DeleteRelationships(ids []int64) bool
-	CheckConnected(requestUserId int64, targetUserId int64) []int64
-	CheckFullySubcribed(requestUserId int64, targetUserId int64) []int64
-	CheckFullyBlocked(requestUserId int64, targetUserId int64) []int64
-	CheckPartialSubcribed(requestUserId int64, targetUserId int64) []int64
-	CheckPartialBlocked(requestUserId int64, targetUserId int64) []int64
-	GetFriendList(id int64) []string
-	GetCommonFriendList(id int64, withId int64) []string
-	GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64) []string
+	CreateRelationship(relationship *models.Relationship) (int64, error)
+	DeleteRelationships(ids []int64) error
+	CheckConnected(requestUserId int64, targetUserId int64) ([]int64, error)
+	CheckFullySubcribed(requestUserId int64, targetUserId int64) ([]int64, error)
+	CheckFullyBlocked(requestUserId int64, targetUserId int64) ([]int64, error)
+	CheckPartialSubcribed(requestUserId int64, targetUserId int64) ([]int64, error)
+	CheckPartialBlocked(requestUserId int64, targetUserId int64) ([]int64, error)
+	CheckFullyPending(requestUserId int64, targetUserId int64) ([]int64, error)
+	CheckPartialPending(requestUserId int64, targetUserId int64) ([]int64, error)
+	GetFriendList(id int64, page models.Page) ([]string, int64, error)
+	GetCommonFriendList(ids []int64, page models.Page) ([]string, int64, error)
+	CountCommonFriends(ids []int64) (map[int64]map[int64]int, error)
+	GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64, page models.Page) ([]string, int64, error)
+	GetIncomingFriendRequests(id int64, page models.Page) ([]string, int64, error)
+	GetOutgoingFriendRequests(id int64, page models.Page) ([]string, int64, error)
+	GetFriendSuggestions(id int64, limit int) ([]models.SuggestedFriend, error)
+	GetShortestPath(requestUserId int64, targetUserId int64, maxDepth int) ([]int64, error)
+	GetRelationshipView(requestUserId int64, targetUserId int64) (models.RelationshipView, error)
+	GetBlockedUsers(id int64, page models.Page) ([]models.BlockedUser, int64, error)
+	GetFollowers(id int64, page models.Page) ([]string, int64, error)
+	GetFollowing(id int64, page models.Page) ([]string, int64, error)
+	CountRelationships(id int64) (models.UserSummary, error)
+	Befriend(requestEmail string, targetEmail string) (Outcome, error)
+	AcceptFriendRequest(requestEmail string, targetEmail string) (Outcome, error)
+	RejectFriendRequest(requestEmail string, targetEmail string) (Outcome, error)
+	CancelFriendRequest(requestEmail string, targetEmail string) (Outcome, error)
+	RemoveFriend(requestEmail string, targetEmail string) (Outcome, error)
+	Subscribe(requestEmail string, targetEmail string) (Outcome, error)
+	Unsubscribe(requestEmail string, targetEmail string) (Outcome, error)
+	Block(requestEmail string, targetEmail string) (Outcome, error)
+	Unblock(requestEmail string, targetEmail string) (Outcome, error)
+	ApplyBatch(operations []models.FriendOperation, atomic bool) (models.FriendBatch, error)
 }
 
 type RelationshipService struct {
 	IRelationshipRepository data.IRelationshipRepository
+	IUnitOfWork             data.IUnitOfWork
+	// createdAt is the creation time of the relationships created instead of now, set by the imports.
+	createdAt *time.Time
 }
 
-func (svc RelationshipService) GetFriendList(id int64) []string {
-	return svc.IRelationshipRepository.GetFriendList(id)
+// change resolves the users owning both emails and runs fn with a service bound to repositories sharing a single
+// transaction, so its changes are applied all together or not at all. Both users must be active.
+func (svc RelationshipService) change(requestEmail string, targetEmail string, fn func(tx RelationshipService, requestUserId int64, targetUserId int64) (Outcome, error)) (Outcome, error) {
+	var outcome Outcome
+	err := svc.IUnitOfWork.Do(func(repositories data.Repositories) error {
+		requestUser, err := repositories.IUserRepository.GetUser(requestEmail)
+		if err != nil {
+			return err
+		}
+
+		targetUser, err := repositories.IUserRepository.GetUser(targetEmail)
+		if err != nil {
+			return err
+		}
+
+		if err := checkActive(requestUser); err != nil {
+			return err
+		}
+		if err := checkActive(targetUser); err != nil {
+			return err
+		}
+
+		tx := RelationshipService{IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+		outcome, err = fn(tx, requestUser.ID, targetUser.ID)
+
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return outcome, nil
+}
+
+func (svc RelationshipService) GetFriendList(id int64, page models.Page) ([]string, int64, error) {
+	return svc.IRelationshipRepository.GetFriendList(id, page)
 }
 
-func (svc RelationshipService) GetCommonFriendList(id int64, withId int64) []string {
-	return svc.IRelationshipRepository.GetCommonFriendList(id, withId)
+func (svc RelationshipService) GetCommonFriendList(ids []int64, page models.Page) ([]string, int64, error) {
+	return svc.IRelationshipRepository.GetCommonFriendList(ids, page)
 }
 
-func (svc RelationshipService) CreateRelationship(relationship *models.Relationship) int64 {
+func (svc RelationshipService) CountCommonFriends(ids []int64) (map[int64]map[int64]int, error) {
+	return svc.IRelationshipRepository.CountCommonFriends(ids)
+}
+
+func (svc RelationshipService) CreateRelationship(relationship *models.Relationship) (int64, error) {
 	return svc.IRelationshipRepository.CreateRelationship(relationship)
 }
 
-func (svc RelationshipService) DeleteRelationships(ids []int64) bool {
+func (svc RelationshipService) DeleteRelationships(ids []int64) error {
 	return svc.IRelationshipRepository.DeleteRelationships(ids)
 }
 
-func (svc RelationshipService) CheckConnected(requestUserId int64, targetUserId int64) []int64 {
-	return svc.IRelationshipRepository.CheckRelationshipTwoWay(requestUserId, targetUserId, 1)
+func (svc RelationshipService) CheckConnected(requestUserId int64, targetUserId int64) ([]int64, error) {
+	return svc.IRelationshipRepository.CheckRelationshipTwoWay(requestUserId, targetUserId, models.RelationshipFriend)
+}
+
+func (svc RelationshipService) CheckFullySubcribed(requestUserId int64, targetUserId int64) ([]int64, error) {
+	return svc.IRelationshipRepository.CheckRelationshipTwoWay(requestUserId, targetUserId, models.RelationshipSubscribed)
+}
+
+func (svc RelationshipService) CheckFullyBlocked(requestUserId int64, targetUserId int64) ([]int64, error) {
+	return svc.IRelationshipRepository.CheckRelationshipTwoWay(requestUserId, targetUserId, models.RelationshipBlocked)
+}
+
+func (svc RelationshipService) CheckPartialSubcribed(requestUserId int64, targetUserId int64) ([]int64, error) {
+	return svc.IRelationshipRepository.CheckRelationshipOneWay(requestUserId, targetUserId, models.RelationshipSubscribed)
+}
+
+func (svc RelationshipService) CheckPartialBlocked(requestUserId int64, targetUserId int64) ([]int64, error) {
+	return svc.IRelationshipRepository.CheckRelationshipOneWay(requestUserId, targetUserId, models.RelationshipBlocked)
+}
+
+func (svc RelationshipService) CheckFullyPending(requestUserId int64, targetUserId int64) ([]int64, error) {
+	return svc.IRelationshipRepository.CheckRelationshipTwoWay(requestUserId, targetUserId, models.RelationshipPending)
+}
+
+func (svc RelationshipService) CheckPartialPending(requestUserId int64, targetUserId int64) ([]int64, error) {
+	return svc.IRelationshipRepository.CheckRelationshipOneWay(requestUserId, targetUserId, models.RelationshipPending)
+}
+
+func (svc RelationshipService) GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64, page models.Page) ([]string, int64, error) {
+	return svc.IRelationshipRepository.GetValidUsersCanReceiveUpdates(senderId, mentionIds, page)
+}
+
+func (svc RelationshipService) GetIncomingFriendRequests(id int64, page models.Page) ([]string, int64, error) {
+	return svc.IRelationshipRepository.GetIncomingFriendRequests(id, page)
+}
+
+func (svc RelationshipService) GetOutgoingFriendRequests(id int64, page models.Page) ([]string, int64, error) {
+	return svc.IRelationshipRepository.GetOutgoingFriendRequests(id, page)
 }
 
-func (svc RelationshipService) CheckFullySubcribed(requestUserId int64, targetUserId int64) []int64 {
-	return svc.IRelationshipRepository.CheckRelationshipTwoWay(requestUserId, targetUserId, 2)
+func (svc RelationshipService) GetFriendSuggestions(id int64, limit int) ([]models.SuggestedFriend, error) {
+	return svc.IRelationshipRepository.GetFriendSuggestions(id, limit)
 }
 
-func (svc RelationshipService) CheckFullyBlocked(requestUserId int64, targetUserId int64) []int64 {
-	return svc.IRelationshipRepository.CheckRelationshipTwoWay(requestUserId, targetUserId, 3)
+func (svc RelationshipService) GetBlockedUsers(id int64, page models.Page) ([]models.BlockedUser, int64, error) {
+	return svc.IRelationshipRepository.GetBlockedUsers(id, page)
 }
 
-func (svc RelationshipService) CheckPartialSubcribed(requestUserId int64, targetUserId int64) []int64 {
-	return svc.IRelationshipRepository.CheckRelationshipOneWay(requestUserId, targetUserId, 2)
+func (svc RelationshipService) GetFollowers(id int64, page models.Page) ([]string, int64, error) {
+	return svc.IRelationshipRepository.GetFollowers(id, page)
 }
 
-func (svc RelationshipService) CheckPartialBlocked(requestUserId int64, targetUserId int64) []int64 {
-	return svc.IRelationshipRepository.CheckRelationshipOneWay(requestUserId, targetUserId, 3)
+func (svc RelationshipService) GetFollowing(id int64, page models.Page) ([]string, int64, error) {
+	return svc.IRelationshipRepository.GetFollowing(id, page)
 }
 
-func (svc RelationshipService) GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64) []string {
-	return svc.IRelationshipRepository.GetValidUsersCanReceiveUpdates(senderId, mentionIds)
+func (svc RelationshipService) CountRelationships(id int64) (models.UserSummary, error) {
+	return svc.IRelationshipRepository.CountRelationships(id)
+}
+
+// GetRelationshipView reads the relationships between both users in each direction.
+func (svc RelationshipService) GetRelationshipView(requestUserId int64, targetUserId int64) (models.RelationshipView, error) {
+	view := models.RelationshipView{Outgoing: []models.RelationshipStatus{}, Incoming: []models.RelationshipStatus{}}
+	flags := []struct {
+		flag          *bool
+		check         func(int64, int64) ([]int64, error)
+		requestUserId int64
+		targetUserId  int64
+		status        models.RelationshipStatus
+		statuses      []*[]models.RelationshipStatus
+	}{
+		{&view.Friends, svc.CheckConnected, requestUserId, targetUserId, models.RelationshipFriend, []*[]models.RelationshipStatus{&view.Outgoing, &view.Incoming}},
+		{&view.Subscribed, svc.CheckPartialSubcribed, requestUserId, targetUserId, models.RelationshipSubscribed, []*[]models.RelationshipStatus{&view.Outgoing}},
+		{&view.SubscribedBy, svc.CheckPartialSubcribed, targetUserId, requestUserId, models.RelationshipSubscribed, []*[]models.RelationshipStatus{&view.Incoming}},
+		{&view.Blocked, svc.CheckPartialBlocked, requestUserId, targetUserId, models.RelationshipBlocked, []*[]models.RelationshipStatus{&view.Outgoing}},
+		{&view.BlockedBy, svc.CheckPartialBlocked, targetUserId, requestUserId, models.RelationshipBlocked, []*[]models.RelationshipStatus{&view.Incoming}},
+		{&view.RequestSent, svc.CheckPartialPending, requestUserId, targetUserId, models.RelationshipPending, []*[]models.RelationshipStatus{&view.Outgoing}},
+		{&view.RequestReceived, svc.CheckPartialPending, targetUserId, requestUserId, models.RelationshipPending, []*[]models.RelationshipStatus{&view.Incoming}},
+	}
+
+	for _, f := range flags {
+		ids, err := f.check(f.requestUserId, f.targetUserId)
+		if err != nil {
+			return models.RelationshipView{}, err
+		}
+		*f.flag = len(ids) > 0
+
+		if *f.flag {
+			for _, statuses := range f.statuses {
+				*statuses = append(*statuses, f.status)
+			}
+		}
+	}
+
+	return view, nil
+}
+
+// pathSearch is one side of the bidirectional search: the users reached so far with the user they were reached from,
+// their distance to the side origin and the users to expand next.
+type pathSearch struct {
+	parents  map[int64]int64
+	depths   map[int64]int
+	frontier []int64
+}
+
+func newPathSearch(origin int64) *pathSearch {
+	return &pathSearch{parents: map[int64]int64{}, depths: map[int64]int{origin: 0}, frontier: []int64{origin}}
+}
+
+// pathTo returns the users from the side origin to the given reached user.
+func (search *pathSearch) pathTo(id int64) []int64 {
+	path := []int64{id}
+	for parent, ok := search.parents[id]; ok; parent, ok = search.parents[parent] {
+		path = append([]int64{parent}, path...)
+	}
+
+	return path
+}
+
+// GetShortestPath returns the ids of the users on the shortest friendship path between two users, both included,
+// or nil when they aren't connected within maxDepth friendships. Friendships between users blocking each other are skipped.
+// The search runs from both users at once, expanding the smaller side one level at a time with a single query per level.
+func (svc RelationshipService) GetShortestPath(requestUserId int64, targetUserId int64, maxDepth int) ([]int64, error) {
+	if requestUserId == targetUserId {
+		return []int64{requestUserId}, nil
+	}
+
+	forward, backward := newPathSearch(requestUserId), newPathSearch(targetUserId)
+	for depth := 0; depth < maxDepth && len(forward.frontier) > 0 && len(backward.frontier) > 0; depth++ {
+		search, other := forward, backward
+		if len(backward.frontier) < len(forward.frontier) {
+			search, other = backward, forward
+		}
+
+		friendIds, err := svc.IRelationshipRepository.GetFriendIds(search.frontier)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []int64
+		meetingId, meetingDepth := int64(0), -1
+		for _, id := range search.frontier {
+			for _, friendId := range friendIds[id] {
+				if _, ok := search.depths[friendId]; ok {
+					continue
+				}
+				search.parents[friendId] = id
+				search.depths[friendId] = search.depths[id] + 1
+				next = append(next, friendId)
+
+				if otherDepth, ok := other.depths[friendId]; ok && (meetingDepth < 0 || search.depths[friendId]+otherDepth < meetingDepth) {
+					meetingId, meetingDepth = friendId, search.depths[friendId]+otherDepth
+				}
+			}
+		}
+		search.frontier = next
+
+		if meetingDepth >= 0 {
+			path := forward.pathTo(meetingId)
+			backwardPath := backward.pathTo(meetingId)
+			for i := len(backwardPath) - 2; i >= 0; i-- {
+				path = append(path, backwardPath[i])
+			}
+
+			return path, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// Befriend sends a friend request from the requestor to the target. If the target has already requested the requestor,
+// both requests are accepted at once and the users become friends.
+func (svc RelationshipService) Befriend(requestEmail string, targetEmail string) (Outcome, error) {
+	return svc.change(requestEmail, targetEmail, RelationshipService.befriend)
+}
+
+// befriend is Befriend between users resolved by their ids, within the transaction of svc.
+func (svc RelationshipService) befriend(requestUserId int64, targetUserId int64) (Outcome, error) {
+	connectedRelationshipIds, err := svc.CheckConnected(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+	if len(connectedRelationshipIds) > 0 {
+		return "", &RelationshipError{Code: models.CodeAlreadyConnected, Message: "connected status is existed"}
+	}
+
+	blockedRelationshipIds, err := svc.CheckFullyBlocked(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+	if len(blockedRelationshipIds) > 0 {
+		return "", &RelationshipError{Code: models.CodeBlocked, Message: "blocked status is existed"}
+	}
+
+	pendingRelationshipIds, err := svc.CheckPartialPending(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+	if len(pendingRelationshipIds) > 0 {
+		return "", &RelationshipError{Code: models.CodeRequestPending, Message: "pending request is existed"}
+	}
+
+	incomingRelationshipIds, err := svc.CheckPartialPending(targetUserId, requestUserId)
+	if err != nil {
+		return "", err
+	}
+	if len(incomingRelationshipIds) > 0 {
+		return FriendRequestAccepted, svc.connect(targetUserId, requestUserId, incomingRelationshipIds)
+	}
+
+	return FriendRequestSent, svc.transition(requestUserId, targetUserId, nil, models.RelationshipPending)
+}
+
+// AcceptFriendRequest turns the pending friend request sent by the target to the requestor into a friend connection.
+func (svc RelationshipService) AcceptFriendRequest(requestEmail string, targetEmail string) (Outcome, error) {
+	return svc.change(requestEmail, targetEmail, RelationshipService.acceptFriendRequest)
+}
+
+// acceptFriendRequest is AcceptFriendRequest between users resolved by their ids, within the transaction of svc.
+func (svc RelationshipService) acceptFriendRequest(requestUserId int64, targetUserId int64) (Outcome, error) {
+	pendingRelationshipIds, err := svc.incomingRequestIds(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+
+	blockedRelationshipIds, err := svc.CheckFullyBlocked(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+	if len(blockedRelationshipIds) > 0 {
+		return "", &RelationshipError{Code: models.CodeBlocked, Message: "blocked status is existed"}
+	}
+
+	return FriendRequestAccepted, svc.connect(targetUserId, requestUserId, pendingRelationshipIds)
+}
+
+// RejectFriendRequest drops the pending friend request sent by the target to the requestor.
+func (svc RelationshipService) RejectFriendRequest(requestEmail string, targetEmail string) (Outcome, error) {
+	return svc.change(requestEmail, targetEmail, func(tx RelationshipService, requestUserId int64, targetUserId int64) (Outcome, error) {
+		pendingRelationshipIds, err := tx.incomingRequestIds(requestUserId, targetUserId)
+		if err != nil {
+			return "", err
+		}
+
+		return FriendRequestRejected, tx.transition(targetUserId, requestUserId, map[models.RelationshipStatus][]int64{models.RelationshipPending: pendingRelationshipIds}, models.RelationshipNone)
+	})
+}
+
+// CancelFriendRequest drops the pending friend request sent by the requestor to the target.
+func (svc RelationshipService) CancelFriendRequest(requestEmail string, targetEmail string) (Outcome, error) {
+	return svc.change(requestEmail, targetEmail, func(tx RelationshipService, requestUserId int64, targetUserId int64) (Outcome, error) {
+		pendingRelationshipIds, err := tx.incomingRequestIds(targetUserId, requestUserId)
+		if err != nil {
+			return "", err
+		}
+
+		return FriendRequestCanceled, tx.transition(requestUserId, targetUserId, map[models.RelationshipStatus][]int64{models.RelationshipPending: pendingRelationshipIds}, models.RelationshipNone)
+	})
+}
+
+// RemoveFriend removes the friend connection between both users. Subscriptions dropped when they became friends
+// are not restored.
+func (svc RelationshipService) RemoveFriend(requestEmail string, targetEmail string) (Outcome, error) {
+	return svc.change(requestEmail, targetEmail, func(tx RelationshipService, requestUserId int64, targetUserId int64) (Outcome, error) {
+		connectedRelationshipIds, err := tx.CheckConnected(requestUserId, targetUserId)
+		if err != nil {
+			return "", err
+		}
+		if len(connectedRelationshipIds) == 0 {
+			return "", &RelationshipError{Code: models.CodeNotConnected, Message: "connected status is not existed"}
+		}
+
+		return FriendRemoved, tx.transition(requestUserId, targetUserId, map[models.RelationshipStatus][]int64{models.RelationshipFriend: connectedRelationshipIds}, models.RelationshipNone)
+	})
+}
+
+// Subscribe makes the requestor receive the updates of the target. Subscribing to a friend changes nothing
+// since friends receive updates from each other anyway.
+func (svc RelationshipService) Subscribe(requestEmail string, targetEmail string) (Outcome, error) {
+	return svc.change(requestEmail, targetEmail, RelationshipService.subscribe)
+}
+
+// subscribe is Subscribe between users resolved by their ids, within the transaction of svc.
+func (svc RelationshipService) subscribe(requestUserId int64, targetUserId int64) (Outcome, error) {
+	subcribedRelationshipIds, err := svc.CheckPartialSubcribed(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+	if len(subcribedRelationshipIds) > 0 {
+		return "", &RelationshipError{Code: models.CodeAlreadySubscribed, Message: "subcribed status is existed"}
+	}
+
+	blockedRelationshipIds, err := svc.CheckPartialBlocked(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+	if len(blockedRelationshipIds) > 0 {
+		return "", &RelationshipError{Code: models.CodeBlocked, Message: "blocked status is existed"}
+	}
+
+	connectedRelationshipIds, err := svc.CheckConnected(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+	if len(connectedRelationshipIds) > 0 {
+		return AlreadyFriends, nil
+	}
+
+	return Subscribed, svc.transition(requestUserId, targetUserId, nil, models.RelationshipSubscribed)
+}
+
+// Unsubscribe removes the subscription of the requestor to the target. Friends keep receiving updates
+// from each other until the connection is removed.
+func (svc RelationshipService) Unsubscribe(requestEmail string, targetEmail string) (Outcome, error) {
+	return svc.change(requestEmail, targetEmail, func(tx RelationshipService, requestUserId int64, targetUserId int64) (Outcome, error) {
+		subcribedRelationshipIds, err := tx.CheckPartialSubcribed(requestUserId, targetUserId)
+		if err != nil {
+			return "", err
+		}
+		if len(subcribedRelationshipIds) == 0 {
+			return "", &RelationshipError{Code: models.CodeNotSubscribed, Message: "subcribed status is not existed"}
+		}
+
+		return Unsubscribed, tx.transition(requestUserId, targetUserId, map[models.RelationshipStatus][]int64{models.RelationshipSubscribed: subcribedRelationshipIds}, models.RelationshipNone)
+	})
+}
+
+// Block makes the requestor stop receiving updates from the target. The subscription of the requestor,
+// the friend connection and the pending friend requests between both users are removed.
+func (svc RelationshipService) Block(requestEmail string, targetEmail string) (Outcome, error) {
+	return svc.change(requestEmail, targetEmail, RelationshipService.block)
+}
+
+// block is Block between users resolved by their ids, within the transaction of svc.
+func (svc RelationshipService) block(requestUserId int64, targetUserId int64) (Outcome, error) {
+	blockedRelationshipIds, err := svc.CheckPartialBlocked(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+	if len(blockedRelationshipIds) > 0 {
+		return "", &RelationshipError{Code: models.CodeBlocked, Message: "blocked status is existed"}
+	}
+
+	subcribedRelationshipIds, err := svc.CheckPartialSubcribed(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+
+	connectedRelationshipIds, err := svc.CheckConnected(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+
+	pendingRelationshipIds, err := svc.CheckFullyPending(requestUserId, targetUserId)
+	if err != nil {
+		return "", err
+	}
+
+	current := map[models.RelationshipStatus][]int64{
+		models.RelationshipSubscribed: subcribedRelationshipIds,
+		models.RelationshipFriend:     connectedRelationshipIds,
+		models.RelationshipPending:    pendingRelationshipIds,
+	}
+
+	return Blocked, svc.transition(requestUserId, targetUserId, current, models.RelationshipBlocked)
+}
+
+// Unblock removes the block set by the requestor on the target. Connections, subscriptions and friend requests
+// dropped by the block are not restored, and a block set by the target on the requestor is kept.
+func (svc RelationshipService) Unblock(requestEmail string, targetEmail string) (Outcome, error) {
+	return svc.change(requestEmail, targetEmail, func(tx RelationshipService, requestUserId int64, targetUserId int64) (Outcome, error) {
+		blockedRelationshipIds, err := tx.CheckPartialBlocked(requestUserId, targetUserId)
+		if err != nil {
+			return "", err
+		}
+		if len(blockedRelationshipIds) == 0 {
+			return "", &RelationshipError{Code: models.CodeNotBlocked, Message: "blocked status is not existed"}
+		}
+
+		return Unblocked, tx.transition(requestUserId, targetUserId, map[models.RelationshipStatus][]int64{models.RelationshipBlocked: blockedRelationshipIds}, models.RelationshipNone)
+	})
+}
+
+// incomingRequestIds returns the pending friend requests sent by targetUserId to requestUserId,
+// or a RelationshipError when there is none.
+func (svc RelationshipService) incomingRequestIds(requestUserId int64, targetUserId int64) ([]int64, error) {
+	pendingRelationshipIds, err := svc.CheckPartialPending(targetUserId, requestUserId)
+	if err != nil {
+		return nil, err
+	}
+	if len(pendingRelationshipIds) == 0 {
+		return nil, &RelationshipError{Code: models.CodeRequestNotFound, Message: "pending request is not existed"}
+	}
+
+	return pendingRelationshipIds, nil
+}
+
+// connect turns the pending request of requestUserId to targetUserId into a friend connection.
+// Subscriptions between both users are removed since friends receive updates anyway.
+func (svc RelationshipService) connect(requestUserId int64, targetUserId int64, pendingRelationshipIds []int64) error {
+	subcribedRelationshipIds, err := svc.CheckFullySubcribed(requestUserId, targetUserId)
+	if err != nil {
+		return err
+	}
+
+	current := map[models.RelationshipStatus][]int64{
+		models.RelationshipPending:    pendingRelationshipIds,
+		models.RelationshipSubscribed: subcribedRelationshipIds,
+	}
+
+	return svc.transition(requestUserId, targetUserId, current, models.RelationshipFriend)
+}
+
+// transition replaces the current relationships between two users, keyed by their status, by a relationship
+// from requestUserId to targetUserId in the next status, or by nothing when next is none. Every change goes through it
+// so the transition table of models.RelationshipStatus is enforced in a single place.
+func (svc RelationshipService) transition(requestUserId int64, targetUserId int64, current map[models.RelationshipStatus][]int64, next models.RelationshipStatus) error {
+	var deletedRelationshipIds []int64
+	for status, ids := range current {
+		if len(ids) > 0 && !status.CanBecome(next) {
+			return fmt.Errorf("a %s relationship can't become %s", status, next)
+		}
+		deletedRelationshipIds = append(deletedRelationshipIds, ids...)
+	}
+
+	if len(deletedRelationshipIds) == 0 && !models.RelationshipNone.CanBecome(next) {
+		return fmt.Errorf("a %s relationship can't be created", next)
+	}
+
+	if len(deletedRelationshipIds) > 0 {
+		sort.Slice(deletedRelationshipIds, func(i, j int) bool { return deletedRelationshipIds[i] < deletedRelationshipIds[j] })
+		if err := svc.DeleteRelationships(deletedRelationshipIds); err != nil {
+			return err
+		}
+	}
+
+	if next == models.RelationshipNone {
+		return nil
+	}
+
+	createdAt := time.Now().UTC()
+	if svc.createdAt != nil {
+		createdAt = *svc.createdAt
+	}
+	_, err := svc.CreateRelationship(&models.Relationship{Status: next, RequestUserId: requestUserId, TargetUserId: targetUserId, CreatedAt: &createdAt})
+	// The checks don't lock the rows they read, a concurrent change creating the same relationship first is
+	// caught by the unique indexes instead.
+	if data.IsDuplicate(err) {
+		return existingRelationshipError(next)
+	}
+
+	return err
 }
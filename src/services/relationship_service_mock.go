@@ -10,62 +10,194 @@ type RelationshipServiceMock struct {
 	mock.Mock
 }
 
-func (m RelationshipServiceMock) GetFriendList(id int64) []string {
-	args := m.Called(id)
+func (m *RelationshipServiceMock) GetFriendList(id int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(id, page)
 
-	return args.Get(0).([]string)
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
 }
 
-func (m RelationshipServiceMock) GetCommonFriendList(id int64, withId int64) []string {
-	args := m.Called(id, withId)
+func (m *RelationshipServiceMock) GetCommonFriendList(ids []int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(ids, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipServiceMock) CountCommonFriends(ids []int64) (map[int64]map[int64]int, error) {
+	args := m.Called(ids)
 
-	return args.Get(0).([]string)
+	return args.Get(0).(map[int64]map[int64]int), args.Error(1)
 }
 
-func (m RelationshipServiceMock) CreateRelationship(relationship *models.Relationship) int64 {
+func (m *RelationshipServiceMock) CreateRelationship(relationship *models.Relationship) (int64, error) {
 	args := m.Called(relationship)
 
-	return args.Get(0).(int64)
+	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m RelationshipServiceMock) DeleteRelationships(ids []int64) bool {
+func (m *RelationshipServiceMock) DeleteRelationships(ids []int64) error {
 	args := m.Called(ids)
 
-	return args.Get(0).(bool)
+	return args.Error(0)
+}
+
+func (m *RelationshipServiceMock) CheckConnected(requestUserId int64, targetUserId int64) ([]int64, error) {
+	args := m.Called(requestUserId, targetUserId)
+
+	return args.Get(0).([]int64), args.Error(1)
 }
 
-func (m RelationshipServiceMock) CheckConnected(requestUserId int64, targetUserId int64) []int64 {
+func (m *RelationshipServiceMock) CheckFullySubcribed(requestUserId int64, targetUserId int64) ([]int64, error) {
 	args := m.Called(requestUserId, targetUserId)
 
-	return args.Get(0).([]int64)
+	return args.Get(0).([]int64), args.Error(1)
 }
 
-func (m RelationshipServiceMock) CheckFullySubcribed(requestUserId int64, targetUserId int64) []int64 {
+func (m *RelationshipServiceMock) CheckFullyBlocked(requestUserId int64, targetUserId int64) ([]int64, error) {
 	args := m.Called(requestUserId, targetUserId)
 
-	return args.Get(0).([]int64)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) CheckPartialSubcribed(requestUserId int64, targetUserId int64) ([]int64, error) {
+	args := m.Called(requestUserId, targetUserId)
+
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) CheckPartialBlocked(requestUserId int64, targetUserId int64) ([]int64, error) {
+	args := m.Called(requestUserId, targetUserId)
+
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(senderId, mentionIds, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
 }
 
-func (m RelationshipServiceMock) CheckFullyBlocked(requestUserId int64, targetUserId int64) []int64 {
+func (m *RelationshipServiceMock) CheckFullyPending(requestUserId int64, targetUserId int64) ([]int64, error) {
 	args := m.Called(requestUserId, targetUserId)
 
-	return args.Get(0).([]int64)
+	return args.Get(0).([]int64), args.Error(1)
 }
 
-func (m RelationshipServiceMock) CheckPartialSubcribed(requestUserId int64, targetUserId int64) []int64 {
+func (m *RelationshipServiceMock) CheckPartialPending(requestUserId int64, targetUserId int64) ([]int64, error) {
 	args := m.Called(requestUserId, targetUserId)
 
-	return args.Get(0).([]int64)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) GetIncomingFriendRequests(id int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(id, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipServiceMock) GetOutgoingFriendRequests(id int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(id, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipServiceMock) GetFriendSuggestions(id int64, limit int) ([]models.SuggestedFriend, error) {
+	args := m.Called(id, limit)
+
+	return args.Get(0).([]models.SuggestedFriend), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) GetShortestPath(requestUserId int64, targetUserId int64, maxDepth int) ([]int64, error) {
+	args := m.Called(requestUserId, targetUserId, maxDepth)
+
+	return args.Get(0).([]int64), args.Error(1)
 }
 
-func (m RelationshipServiceMock) CheckPartialBlocked(requestUserId int64, targetUserId int64) []int64 {
+func (m *RelationshipServiceMock) GetRelationshipView(requestUserId int64, targetUserId int64) (models.RelationshipView, error) {
 	args := m.Called(requestUserId, targetUserId)
 
-	return args.Get(0).([]int64)
+	return args.Get(0).(models.RelationshipView), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) Befriend(requestEmail string, targetEmail string) (Outcome, error) {
+	args := m.Called(requestEmail, targetEmail)
+
+	return args.Get(0).(Outcome), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) AcceptFriendRequest(requestEmail string, targetEmail string) (Outcome, error) {
+	args := m.Called(requestEmail, targetEmail)
+
+	return args.Get(0).(Outcome), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) RejectFriendRequest(requestEmail string, targetEmail string) (Outcome, error) {
+	args := m.Called(requestEmail, targetEmail)
+
+	return args.Get(0).(Outcome), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) CancelFriendRequest(requestEmail string, targetEmail string) (Outcome, error) {
+	args := m.Called(requestEmail, targetEmail)
+
+	return args.Get(0).(Outcome), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) RemoveFriend(requestEmail string, targetEmail string) (Outcome, error) {
+	args := m.Called(requestEmail, targetEmail)
+
+	return args.Get(0).(Outcome), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) Subscribe(requestEmail string, targetEmail string) (Outcome, error) {
+	args := m.Called(requestEmail, targetEmail)
+
+	return args.Get(0).(Outcome), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) Unsubscribe(requestEmail string, targetEmail string) (Outcome, error) {
+	args := m.Called(requestEmail, targetEmail)
+
+	return args.Get(0).(Outcome), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) Block(requestEmail string, targetEmail string) (Outcome, error) {
+	args := m.Called(requestEmail, targetEmail)
+
+	return args.Get(0).(Outcome), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) Unblock(requestEmail string, targetEmail string) (Outcome, error) {
+	args := m.Called(requestEmail, targetEmail)
+
+	return args.Get(0).(Outcome), args.Error(1)
+}
+
+func (m *RelationshipServiceMock) GetBlockedUsers(id int64, page models.Page) ([]models.BlockedUser, int64, error) {
+	args := m.Called(id, page)
+
+	return args.Get(0).([]models.BlockedUser), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipServiceMock) GetFollowers(id int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(id, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipServiceMock) GetFollowing(id int64, page models.Page) ([]string, int64, error) {
+	args := m.Called(id, page)
+
+	return args.Get(0).([]string), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *RelationshipServiceMock) CountRelationships(id int64) (models.UserSummary, error) {
+	args := m.Called(id)
+
+	return args.Get(0).(models.UserSummary), args.Error(1)
 }
 
-func (m RelationshipServiceMock) GetValidUsersCanReceiveUpdates(senderId int64, mentionIds []int64) []string {
-	args := m.Called(senderId, mentionIds)
+func (m *RelationshipServiceMock) ApplyBatch(operations []models.FriendOperation, atomic bool) (models.FriendBatch, error) {
+	args := m.Called(operations, atomic)
 
-	return args.Get(0).([]string)
+	return args.Get(0).(models.FriendBatch), args.Error(1)
 }
@@ -1,23 +1,34 @@
 package services_test
 
 import (
+	"errors"
 	"friendMgmt/data"
 	"friendMgmt/models"
 	"friendMgmt/services"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+// createdRelationship matches the relationship passed to CreateRelationship whatever its creation time.
+func createdRelationship(expected models.Relationship) interface{} {
+	return mock.MatchedBy(func(actual *models.Relationship) bool {
+		return actual.CreatedAt != nil && actual.RequestUserId == expected.RequestUserId &&
+			actual.TargetUserId == expected.TargetUserId && actual.Status == expected.Status
+	})
+}
+
 func TestCreateRelationship(t *testing.T) {
-	relationshipModel := models.Relationship{Status: int64(1), RequestUserId: int64(1), TargetUserId: int64(2)}
+	relationshipModel := models.Relationship{Status: models.RelationshipFriend, RequestUserId: int64(1), TargetUserId: int64(2)}
 
 	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	relationshipRepositoryMock.On("CreateRelationship", &relationshipModel).Return(int64(1))
+	relationshipRepositoryMock.On("CreateRelationship", &relationshipModel).Return(int64(1), nil)
 
-	relationshipService := services.RelationshipService{relationshipRepositoryMock}
-	id := relationshipService.CreateRelationship(&relationshipModel)
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+	id, err := relationshipService.CreateRelationship(&relationshipModel)
 
+	assert.NoError(t, err)
 	assert.Equal(t, int64(1), id)
 
 	relationshipRepositoryMock.AssertExpectations(t)
@@ -27,53 +38,84 @@ func TestDeleteRelationships(t *testing.T) {
 	ids := []int64{int64(1), int64(2)}
 
 	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	relationshipRepositoryMock.On("DeleteRelationships", ids).Return(true)
+	relationshipRepositoryMock.On("DeleteRelationships", ids).Return(nil)
 
-	relationshipService := services.RelationshipService{relationshipRepositoryMock}
-	isDeleted := relationshipService.DeleteRelationships(ids)
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+	err := relationshipService.DeleteRelationships(ids)
 
-	assert.Equal(t, true, isDeleted)
+	assert.NoError(t, err)
 
 	relationshipRepositoryMock.AssertExpectations(t)
 }
 
 func TestGetFriendList(t *testing.T) {
+	page := models.Page{After: 3, Limit: 2}
 	expectedResult := []string{"user1@gmail.com", "user2@gmail.com"}
 
 	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	relationshipRepositoryMock.On("GetFriendList", int64(1)).Return(expectedResult)
+	relationshipRepositoryMock.On("GetFriendList", int64(1), page).Return(expectedResult, int64(7), nil)
 
-	relationshipService := services.RelationshipService{relationshipRepositoryMock}
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
 
-	assert.Equal(t, expectedResult, relationshipService.GetFriendList(int64(1)))
+	actualResult, next, err := relationshipService.GetFriendList(int64(1), page)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+	assert.Equal(t, int64(7), next)
 
 	relationshipRepositoryMock.AssertExpectations(t)
 }
 
 func TestGetCommonFriendList(t *testing.T) {
+	page := models.Page{After: 3, Limit: 2}
 	expectedResult := []string{"user1@gmail.com", "user2@gmail.com"}
 
 	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	relationshipRepositoryMock.On("GetCommonFriendList", int64(1), int64(2)).Return(expectedResult)
+	relationshipRepositoryMock.On("GetCommonFriendList", []int64{1, 2}, page).Return(expectedResult, int64(7), nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	actualResult, next, err := relationshipService.GetCommonFriendList([]int64{1, 2}, page)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+	assert.Equal(t, int64(7), next)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestCountCommonFriends(t *testing.T) {
+	expectedResult := map[int64]map[int64]int{1: {2: 3}, 2: {1: 3}}
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CountCommonFriends", []int64{1, 2, 3}).Return(expectedResult, nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
 
-	relationshipService := services.RelationshipService{relationshipRepositoryMock}
+	actualResult, err := relationshipService.CountCommonFriends([]int64{1, 2, 3})
 
-	assert.Equal(t, expectedResult, relationshipService.GetCommonFriendList(int64(1), int64(2)))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
 
 	relationshipRepositoryMock.AssertExpectations(t)
 }
 
 func TestGetValidUsersCanReceiveUpdates(t *testing.T) {
+	page := models.Page{After: 3, Limit: 2}
 	expectedResult := []string{"user1@gmail.com", "user2@gmail.com"}
 	senderId := int64(1)
 	mentionedIds := []int64{int64(2), int64(3)}
 
 	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	relationshipRepositoryMock.On("GetValidUsersCanReceiveUpdates", senderId, mentionedIds).Return(expectedResult)
+	relationshipRepositoryMock.On("GetValidUsersCanReceiveUpdates", senderId, mentionedIds, page).Return(expectedResult, int64(7), nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
 
-	relationshipService := services.RelationshipService{relationshipRepositoryMock}
+	actualResult, next, err := relationshipService.GetValidUsersCanReceiveUpdates(senderId, mentionedIds, page)
 
-	assert.Equal(t, expectedResult, relationshipService.GetValidUsersCanReceiveUpdates(senderId, mentionedIds))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+	assert.Equal(t, int64(7), next)
 
 	relationshipRepositoryMock.AssertExpectations(t)
 }
@@ -84,11 +126,14 @@ func TestCheckConnected(t *testing.T) {
 	targetUserId := int64(2)
 
 	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, int64(1)).Return(expectedResult)
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, models.RelationshipFriend).Return(expectedResult, nil)
 
-	relationshipService := services.RelationshipService{relationshipRepositoryMock}
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
 
-	assert.Equal(t, expectedResult, relationshipService.CheckConnected(requestUserId, targetUserId))
+	actualResult, err := relationshipService.CheckConnected(requestUserId, targetUserId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
 
 	relationshipRepositoryMock.AssertExpectations(t)
 }
@@ -99,11 +144,14 @@ func TestCheckFullySubcribed(t *testing.T) {
 	targetUserId := int64(2)
 
 	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, int64(2)).Return(expectedResult)
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, models.RelationshipSubscribed).Return(expectedResult, nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
 
-	relationshipService := services.RelationshipService{relationshipRepositoryMock}
+	actualResult, err := relationshipService.CheckFullySubcribed(requestUserId, targetUserId)
 
-	assert.Equal(t, expectedResult, relationshipService.CheckFullySubcribed(requestUserId, targetUserId))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
 
 	relationshipRepositoryMock.AssertExpectations(t)
 }
@@ -114,11 +162,14 @@ func TestCheckFullyBlocked(t *testing.T) {
 	targetUserId := int64(2)
 
 	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, int64(3)).Return(expectedResult)
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, models.RelationshipBlocked).Return(expectedResult, nil)
 
-	relationshipService := services.RelationshipService{relationshipRepositoryMock}
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
 
-	assert.Equal(t, expectedResult, relationshipService.CheckFullyBlocked(requestUserId, targetUserId))
+	actualResult, err := relationshipService.CheckFullyBlocked(requestUserId, targetUserId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
 
 	relationshipRepositoryMock.AssertExpectations(t)
 }
@@ -129,11 +180,14 @@ func TestCheckPartialSubcribed(t *testing.T) {
 	targetUserId := int64(2)
 
 	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, int64(2)).Return(expectedResult)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, models.RelationshipSubscribed).Return(expectedResult, nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
 
-	relationshipService := services.RelationshipService{relationshipRepositoryMock}
+	actualResult, err := relationshipService.CheckPartialSubcribed(requestUserId, targetUserId)
 
-	assert.Equal(t, expectedResult, relationshipService.CheckPartialSubcribed(requestUserId, targetUserId))
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
 
 	relationshipRepositoryMock.AssertExpectations(t)
 }
@@ -144,11 +198,436 @@ func TestCheckPartialBlocked(t *testing.T) {
 	targetUserId := int64(2)
 
 	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
-	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, int64(3)).Return(expectedResult)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, models.RelationshipBlocked).Return(expectedResult, nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	actualResult, err := relationshipService.CheckPartialBlocked(requestUserId, targetUserId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestCheckFullyPending(t *testing.T) {
+	expectedResult := []int64{int64(3), int64(4)}
+	requestUserId := int64(1)
+	targetUserId := int64(2)
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", requestUserId, targetUserId, models.RelationshipPending).Return(expectedResult, nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	actualResult, err := relationshipService.CheckFullyPending(requestUserId, targetUserId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestCheckPartialPending(t *testing.T) {
+	expectedResult := []int64{int64(3)}
+	requestUserId := int64(1)
+	targetUserId := int64(2)
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", requestUserId, targetUserId, models.RelationshipPending).Return(expectedResult, nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	actualResult, err := relationshipService.CheckPartialPending(requestUserId, targetUserId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestGetIncomingFriendRequests(t *testing.T) {
+	page := models.Page{After: 3, Limit: 2}
+	expectedResult := []string{"user1@gmail.com", "user2@gmail.com"}
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("GetIncomingFriendRequests", int64(1), page).Return(expectedResult, int64(7), nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	actualResult, next, err := relationshipService.GetIncomingFriendRequests(int64(1), page)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+	assert.Equal(t, int64(7), next)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestGetOutgoingFriendRequests(t *testing.T) {
+	page := models.Page{After: 3, Limit: 2}
+	expectedResult := []string{"user1@gmail.com"}
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("GetOutgoingFriendRequests", int64(1), page).Return(expectedResult, int64(7), nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	actualResult, next, err := relationshipService.GetOutgoingFriendRequests(int64(1), page)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+	assert.Equal(t, int64(7), next)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestGetFriendSuggestions(t *testing.T) {
+	expectedResult := []models.SuggestedFriend{{Email: "user1@gmail.com", MutualFriends: 2}}
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("GetFriendSuggestions", int64(1), 10).Return(expectedResult, nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	actualResult, err := relationshipService.GetFriendSuggestions(int64(1), 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestGetFollowers(t *testing.T) {
+	page := models.Page{Limit: 20}
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("GetFollowers", int64(1), page).Return([]string{"user2@gmail.com"}, int64(0), nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	followers, next, err := relationshipService.GetFollowers(int64(1), page)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user2@gmail.com"}, followers)
+	assert.Equal(t, int64(0), next)
+}
+
+func TestGetFollowing(t *testing.T) {
+	page := models.Page{After: 2, Limit: 2}
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("GetFollowing", int64(1), page).Return([]string{"user4@gmail.com"}, int64(4), nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	following, next, err := relationshipService.GetFollowing(int64(1), page)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user4@gmail.com"}, following)
+	assert.Equal(t, int64(4), next)
+}
+
+func TestGetRelationshipView(t *testing.T) {
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipFriend).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipSubscribed).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(2), int64(1), models.RelationshipSubscribed).Return([]int64{3}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipBlocked).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(2), int64(1), models.RelationshipBlocked).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipPending).Return([]int64{4}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(2), int64(1), models.RelationshipPending).Return([]int64{}, nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	actualResult, err := relationshipService.GetRelationshipView(int64(1), int64(2))
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RelationshipView{
+		Outgoing:     []models.RelationshipStatus{models.RelationshipPending},
+		Incoming:     []models.RelationshipStatus{models.RelationshipSubscribed},
+		SubscribedBy: true,
+		RequestSent:  true,
+	}, actualResult)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestGetRelationshipViewWithUnavailableStorage(t *testing.T) {
+	storageErr := errors.New("connection refused")
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipFriend).Return([]int64(nil), storageErr)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	_, err := relationshipService.GetRelationshipView(int64(1), int64(2))
+
+	assert.Equal(t, storageErr, err)
+}
+
+func TestGetShortestPath(t *testing.T) {
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("GetFriendIds", []int64{1}).Return(map[int64][]int64{1: {2, 5}}, nil)
+	relationshipRepositoryMock.On("GetFriendIds", []int64{4}).Return(map[int64][]int64{4: {3}}, nil)
+	relationshipRepositoryMock.On("GetFriendIds", []int64{3}).Return(map[int64][]int64{3: {2, 4}}, nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	actualResult, err := relationshipService.GetShortestPath(int64(1), int64(4), 6)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1, 2, 3, 4}, actualResult)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestGetShortestPathBeyondMaxDepth(t *testing.T) {
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("GetFriendIds", []int64{1}).Return(map[int64][]int64{1: {2, 5}}, nil)
+	relationshipRepositoryMock.On("GetFriendIds", []int64{4}).Return(map[int64][]int64{4: {3}}, nil)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	path, err := relationshipService.GetShortestPath(int64(1), int64(4), 2)
+
+	assert.NoError(t, err)
+	assert.Nil(t, path)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestGetShortestPathWithStorageError(t *testing.T) {
+	storageErr := errors.New("connection refused")
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("GetFriendIds", []int64{1}).Return(map[int64][]int64(nil), storageErr)
+
+	relationshipService := services.RelationshipService{IRelationshipRepository: &relationshipRepositoryMock}
+
+	path, err := relationshipService.GetShortestPath(int64(1), int64(4), 6)
+
+	assert.Equal(t, storageErr, err)
+	assert.Nil(t, path)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+// newTransactionalService returns a RelationshipService running its transactions against the repository mock,
+// where email@request.com and email@target.com are the users 1 and 2 and suspended@target.com the suspended user 3.
+func newTransactionalService(relationshipRepositoryMock *data.RelationshipRepositoryMock) (services.RelationshipService, *data.UnitOfWorkMock) {
+	userRepositoryMock := data.UserRepositoryMock{}
+	userRepositoryMock.On("GetUser", "email@request.com").Return(models.User{ID: 1, Email: "email@request.com", Status: models.UserActive}, nil)
+	userRepositoryMock.On("GetUser", "email@target.com").Return(models.User{ID: 2, Email: "email@target.com", Status: models.UserActive}, nil)
+	userRepositoryMock.On("GetUser", "suspended@target.com").Return(models.User{ID: 3, Email: "suspended@target.com", Status: models.UserSuspended}, nil)
+	userRepositoryMock.On("GetUser", "undefined@target.com").Return(models.User{}, &data.NotFoundError{Entity: "user", Key: "undefined@target.com"})
+
+	unitOfWorkMock := &data.UnitOfWorkMock{Repositories: data.Repositories{IUserRepository: &userRepositoryMock, IRelationshipRepository: relationshipRepositoryMock}}
+	unitOfWorkMock.On("Do").Return()
+
+	return services.RelationshipService{IRelationshipRepository: relationshipRepositoryMock, IUnitOfWork: unitOfWorkMock}, unitOfWorkMock
+}
+
+func TestBefriendSendsFriendRequest(t *testing.T) {
+	relationshipModel := models.Relationship{Status: models.RelationshipPending, RequestUserId: int64(1), TargetUserId: int64(2)}
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipFriend).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipBlocked).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipPending).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(2), int64(1), models.RelationshipPending).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CreateRelationship", createdRelationship(relationshipModel)).Return(int64(10), nil)
+
+	relationshipService, unitOfWorkMock := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.Befriend("email@request.com", "email@target.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, services.FriendRequestSent, outcome)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+	unitOfWorkMock.AssertExpectations(t)
+}
+
+func TestBefriendAcceptsIncomingRequest(t *testing.T) {
+	relationshipModel := models.Relationship{Status: models.RelationshipFriend, RequestUserId: int64(2), TargetUserId: int64(1)}
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipFriend).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipBlocked).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipPending).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(2), int64(1), models.RelationshipPending).Return([]int64{5}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(2), int64(1), models.RelationshipSubscribed).Return([]int64{6}, nil)
+	relationshipRepositoryMock.On("DeleteRelationships", []int64{5, 6}).Return(nil)
+	relationshipRepositoryMock.On("CreateRelationship", createdRelationship(relationshipModel)).Return(int64(10), nil)
+
+	relationshipService, _ := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.Befriend("email@request.com", "email@target.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, services.FriendRequestAccepted, outcome)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestBefriendWithBlockedUsers(t *testing.T) {
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipFriend).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipBlocked).Return([]int64{7}, nil)
+
+	relationshipService, _ := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.Befriend("email@request.com", "email@target.com")
+
+	assert.Equal(t, &services.RelationshipError{Code: models.CodeBlocked, Message: "blocked status is existed"}, err)
+	assert.Empty(t, outcome)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestAcceptFriendRequestWithoutPendingRequest(t *testing.T) {
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(2), int64(1), models.RelationshipPending).Return([]int64{}, nil)
+
+	relationshipService, _ := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.AcceptFriendRequest("email@request.com", "email@target.com")
+
+	assert.Equal(t, &services.RelationshipError{Code: models.CodeRequestNotFound, Message: "pending request is not existed"}, err)
+	assert.Empty(t, outcome)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestSubscribeToFriend(t *testing.T) {
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipSubscribed).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipBlocked).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipFriend).Return([]int64{3}, nil)
+
+	relationshipService, _ := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.Subscribe("email@request.com", "email@target.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, services.AlreadyFriends, outcome)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+	relationshipRepositoryMock.AssertNotCalled(t, "CreateRelationship", mock.Anything)
+}
+
+func TestSubscribeWithConcurrentSubscription(t *testing.T) {
+	relationshipModel := models.Relationship{Status: models.RelationshipSubscribed, RequestUserId: int64(1), TargetUserId: int64(2)}
+	duplicateErr := &data.DuplicateError{Entity: "relationship", Key: "1-2 with status subscribed"}
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipSubscribed).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipBlocked).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipFriend).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CreateRelationship", createdRelationship(relationshipModel)).Return(int64(0), duplicateErr)
+
+	relationshipService, _ := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.Subscribe("email@request.com", "email@target.com")
+
+	assert.Equal(t, &services.RelationshipError{Code: models.CodeAlreadySubscribed, Message: "subcribed status is existed"}, err)
+	assert.Empty(t, outcome)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestBlockRemovesRelationships(t *testing.T) {
+	relationshipModel := models.Relationship{Status: models.RelationshipBlocked, RequestUserId: int64(1), TargetUserId: int64(2)}
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipBlocked).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipSubscribed).Return([]int64{4}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipFriend).Return([]int64{5}, nil)
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipPending).Return([]int64{}, nil)
+	relationshipRepositoryMock.On("DeleteRelationships", []int64{4, 5}).Return(nil)
+	relationshipRepositoryMock.On("CreateRelationship", createdRelationship(relationshipModel)).Return(int64(10), nil)
+
+	relationshipService, unitOfWorkMock := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.Block("email@request.com", "email@target.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, services.Blocked, outcome)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+	unitOfWorkMock.AssertExpectations(t)
+}
+
+func TestBlockWithStorageError(t *testing.T) {
+	storageErr := errors.New("connection refused")
+
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipBlocked).Return([]int64(nil), storageErr)
+
+	relationshipService, _ := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.Block("email@request.com", "email@target.com")
+
+	assert.Equal(t, storageErr, err)
+	assert.Empty(t, outcome)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestBefriendWithNotFoundUser(t *testing.T) {
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+
+	relationshipService, _ := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.Befriend("email@request.com", "undefined@target.com")
+
+	assert.True(t, data.IsNotFound(err))
+	assert.Empty(t, outcome)
+
+	relationshipRepositoryMock.AssertNotCalled(t, "CreateRelationship", mock.Anything)
+}
+
+func TestSubscribeWithSuspendedUser(t *testing.T) {
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+
+	relationshipService, _ := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.Subscribe("email@request.com", "suspended@target.com")
+
+	assert.Equal(t, &services.RelationshipError{Code: models.CodeUserInactive, Message: "user suspended@target.com is suspended"}, err)
+	assert.Empty(t, outcome)
+
+	relationshipRepositoryMock.AssertNotCalled(t, "CreateRelationship", mock.Anything)
+}
+
+func TestRemoveFriendWithNotConnectedUsers(t *testing.T) {
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipTwoWay", int64(1), int64(2), models.RelationshipFriend).Return([]int64{}, nil)
+
+	relationshipService, _ := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.RemoveFriend("email@request.com", "email@target.com")
+
+	assert.Equal(t, &services.RelationshipError{Code: models.CodeNotConnected, Message: "connected status is not existed"}, err)
+	assert.Empty(t, outcome)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestCancelFriendRequest(t *testing.T) {
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipPending).Return([]int64{5}, nil)
+	relationshipRepositoryMock.On("DeleteRelationships", []int64{5}).Return(nil)
+
+	relationshipService, _ := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.CancelFriendRequest("email@request.com", "email@target.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, services.FriendRequestCanceled, outcome)
+
+	relationshipRepositoryMock.AssertExpectations(t)
+}
+
+func TestUnblock(t *testing.T) {
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	relationshipRepositoryMock.On("CheckRelationshipOneWay", int64(1), int64(2), models.RelationshipBlocked).Return([]int64{7}, nil)
+	relationshipRepositoryMock.On("DeleteRelationships", []int64{7}).Return(nil)
 
-	relationshipService := services.RelationshipService{relationshipRepositoryMock}
+	relationshipService, _ := newTransactionalService(&relationshipRepositoryMock)
+	outcome, err := relationshipService.Unblock("email@request.com", "email@target.com")
 
-	assert.Equal(t, expectedResult, relationshipService.CheckPartialBlocked(requestUserId, targetUserId))
+	assert.NoError(t, err)
+	assert.Equal(t, services.Unblocked, outcome)
 
 	relationshipRepositoryMock.AssertExpectations(t)
 }
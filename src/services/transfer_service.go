@@ -0,0 +1,284 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"friendMgmt/common"
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"time"
+	"unicode/utf8"
+)
+
+// ITransferService imports and exports the users with their relationships.
+type ITransferService interface {
+	Import(records []models.UserRecord, dryRun bool) (models.UserImport, error)
+	Export() ([]models.UserRecord, error)
+}
+
+type TransferService struct {
+	IUserRepository         data.IUserRepository
+	IRelationshipRepository data.IRelationshipRepository
+	IUnitOfWork             data.IUnitOfWork
+}
+
+// errDryRun rolls back the unit of work of a dry run once every record was imported.
+var errDryRun = errors.New("dry run")
+
+// transferPageLimit is the number of users or relationships an export reads at once.
+const transferPageLimit = 500
+
+// Import applies the records in their order in a single transaction and reports the result of each of them, a dry run
+// reporting the same results without storing anything. Invalid and conflicting records are skipped, and the users or
+// relationships which already exist are kept as they are so importing a file again changes nothing. Relationships
+// go through the relationship operations: a friend record sends and accepts a friend request, a pending record sends
+// one, a subscribed record subscribes and a blocked record blocks, removing what a block removes. The relationships
+// created keep the creation time of their record when it has one.
+func (svc TransferService) Import(records []models.UserRecord, dryRun bool) (models.UserImport, error) {
+	var report models.UserImport
+	err := svc.IUnitOfWork.Do(func(repositories data.Repositories) error {
+		report = models.UserImport{DryRun: dryRun, Rows: make([]models.UserImportRow, 0, len(records))}
+		relationships := RelationshipService{IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+		now := time.Now().UTC()
+
+		for i, record := range records {
+			row := models.UserImportRow{Row: i + 1, Details: validateUserRecord(record)}
+			if len(row.Details) == 0 {
+				var err error
+				if record.Type == models.UserRecordUser {
+					err = importUser(repositories.IUserRepository, record, now, &row)
+				} else {
+					err = importRelationship(repositories.IUserRepository, relationships, record, &row)
+				}
+				if err != nil {
+					return err
+				}
+			} else {
+				row.Result = models.ImportInvalid
+			}
+
+			switch row.Result {
+			case models.ImportCreated:
+				report.Created++
+			case models.ImportExisting:
+				report.Existing++
+			case models.ImportInvalid:
+				report.Invalid++
+			case models.ImportConflict:
+				report.Conflicts++
+			}
+			report.Rows = append(report.Rows, row)
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+
+		return nil
+	})
+	if err != nil && err != errDryRun {
+		return models.UserImport{}, err
+	}
+
+	return report, nil
+}
+
+// validateUserRecord returns the fields of a record failing the validation.
+func validateUserRecord(record models.UserRecord) []models.FieldError {
+	var details []models.FieldError
+	check := func(ok bool, field string, message string) {
+		if !ok {
+			details = append(details, models.FieldError{Field: field, Message: message})
+		}
+	}
+
+	check(common.IsValidEmail(record.Email), "email", "must be a valid email")
+	switch record.Type {
+	case models.UserRecordUser:
+		check(utf8.RuneCountInString(record.DisplayName) <= models.MaxDisplayNameLength, "displayName", fmt.Sprintf("must not exceed %d characters", models.MaxDisplayNameLength))
+		check(record.AvatarUrl == "" || common.IsWebUrl(record.AvatarUrl, models.MaxAvatarUrlLength), "avatarUrl", fmt.Sprintf("must be an http or https url of at most %d characters", models.MaxAvatarUrlLength))
+		check(record.Status == "" || models.UserStatus(record.Status).IsValid(), "status", "must be active, suspended or deleted")
+	case models.UserRecordRelationship:
+		check(common.IsValidEmail(record.Target), "target", "must be a valid email")
+		check(common.NormalizeEmail(record.Email) != common.NormalizeEmail(record.Target), "target", "must differ from email")
+		var status models.RelationshipStatus
+		check(status.UnmarshalText([]byte(record.Status)) == nil && status != models.RelationshipNone, "status", "must be friend, subscribed, blocked or pending")
+		_, err := time.Parse(time.RFC3339, record.CreatedAt)
+		check(record.CreatedAt == "" || err == nil, "createdAt", "must be an RFC 3339 time")
+	default:
+		check(false, "type", "must be user or relationship")
+	}
+
+	return details
+}
+
+// importUser creates the user of a record with its profile, unless an user owns its email already.
+func importUser(users data.IUserRepository, record models.UserRecord, now time.Time, row *models.UserImportRow) error {
+	_, err := users.CheckUserExist(record.Email)
+	if err == nil {
+		row.Result = models.ImportExisting
+		return nil
+	}
+	if !data.IsNotFound(err) {
+		return err
+	}
+
+	// An user created meanwhile by another transaction is existing too.
+	if err := users.Create(record.Email, now); data.IsDuplicate(err) {
+		row.Result = models.ImportExisting
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	user, err := users.GetUser(record.Email)
+	if err != nil {
+		return err
+	}
+	user.DisplayName = record.DisplayName
+	user.AvatarUrl = record.AvatarUrl
+	if record.Status != "" {
+		user.Status = models.UserStatus(record.Status)
+	}
+	if err := users.UpdateUser(user); err != nil {
+		return err
+	}
+
+	row.Result = models.ImportCreated
+
+	return nil
+}
+
+// importRelationship creates the relationship of a record through the relationship operations. The errors telling
+// that it exists already make the record existing, the other ones a conflict. The statuses of the users aren't
+// checked, so the relationships of a suspended user import back.
+func importRelationship(users data.IUserRepository, relationships RelationshipService, record models.UserRecord, row *models.UserImportRow) error {
+	var userIds []int64
+	for _, field := range []struct{ name, email string }{{"email", record.Email}, {"target", record.Target}} {
+		userId, err := users.CheckUserExist(field.email)
+		userIds = append(userIds, userId)
+		if data.IsNotFound(err) {
+			row.Details = append(row.Details, models.FieldError{Field: field.name, Message: "must be an imported or existing user"})
+		} else if err != nil {
+			return err
+		}
+	}
+	if len(row.Details) > 0 {
+		row.Result = models.ImportInvalid
+		return nil
+	}
+	requestUserId, targetUserId := userIds[0], userIds[1]
+
+	var status models.RelationshipStatus
+	status.UnmarshalText([]byte(record.Status))
+	if record.CreatedAt != "" {
+		createdAt, _ := time.Parse(time.RFC3339, record.CreatedAt)
+		createdAt = createdAt.UTC()
+		relationships.createdAt = &createdAt
+	}
+
+	var outcome Outcome
+	var existingCode string
+	var err error
+	switch status {
+	case models.RelationshipFriend:
+		existingCode = models.CodeAlreadyConnected
+		outcome, err = relationships.befriend(requestUserId, targetUserId)
+		if relationshipErrorCode(err) == models.CodeRequestPending || (err == nil && outcome == FriendRequestSent) {
+			_, err = relationships.acceptFriendRequest(targetUserId, requestUserId)
+		}
+	case models.RelationshipPending:
+		existingCode = models.CodeRequestPending
+		_, err = relationships.befriend(requestUserId, targetUserId)
+	case models.RelationshipSubscribed:
+		existingCode = models.CodeAlreadySubscribed
+		outcome, err = relationships.subscribe(requestUserId, targetUserId)
+	case models.RelationshipBlocked:
+		existingCode = models.CodeBlocked
+		_, err = relationships.block(requestUserId, targetUserId)
+	}
+
+	if err != nil {
+		relationshipErr, ok := err.(*RelationshipError)
+		if !ok {
+			return err
+		}
+
+		if relationshipErr.Code == existingCode {
+			row.Result = models.ImportExisting
+		} else {
+			row.Result = models.ImportConflict
+			row.Code = relationshipErr.Code
+			row.Message = relationshipErr.Message
+		}
+		return nil
+	}
+
+	// Friends receive the updates of each other, subscribing to a friend stores nothing.
+	if outcome == AlreadyFriends {
+		row.Result = models.ImportExisting
+	} else {
+		row.Result = models.ImportCreated
+	}
+
+	return nil
+}
+
+// relationshipErrorCode returns the code of a RelationshipError, or an empty code for any other error.
+func relationshipErrorCode(err error) string {
+	if relationshipErr, ok := err.(*RelationshipError); ok {
+		return relationshipErr.Code
+	}
+
+	return ""
+}
+
+// Export returns the records of every user followed by the records of every relationship, in the order they were
+// created so importing them rebuilds the same graph. It reads page by page outside of any transaction so the writes
+// aren't held back, the relationships of the users created while the users were read being left out.
+func (svc TransferService) Export() ([]models.UserRecord, error) {
+	var records []models.UserRecord
+	emails := map[int64]string{}
+
+	page := models.Page{Limit: transferPageLimit}
+	for {
+		users, next, err := svc.IUserRepository.FindPage(page)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			emails[user.ID] = user.Email
+			records = append(records, models.UserRecord{Type: models.UserRecordUser, Email: user.Email, DisplayName: user.DisplayName, AvatarUrl: user.AvatarUrl, Status: string(user.Status)})
+		}
+		if next == 0 {
+			break
+		}
+		page.After = next
+	}
+
+	page = models.Page{Limit: transferPageLimit}
+	for {
+		relationships, next, err := svc.IRelationshipRepository.GetRelationships(page)
+		if err != nil {
+			return nil, err
+		}
+		for _, relationship := range relationships {
+			requestEmail, requestFound := emails[relationship.RequestUserId]
+			targetEmail, targetFound := emails[relationship.TargetUserId]
+			if !requestFound || !targetFound {
+				continue
+			}
+			record := models.UserRecord{Type: models.UserRecordRelationship, Email: requestEmail, Status: relationship.Status.String(), Target: targetEmail}
+			if relationship.CreatedAt != nil {
+				record.CreatedAt = relationship.CreatedAt.UTC().Format(time.RFC3339Nano)
+			}
+			records = append(records, record)
+		}
+		if next == 0 {
+			break
+		}
+		page.After = next
+	}
+
+	return records, nil
+}
@@ -0,0 +1,23 @@
+package services
+
+import (
+	"friendMgmt/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TransferServiceMock struct {
+	mock.Mock
+}
+
+func (m *TransferServiceMock) Import(records []models.UserRecord, dryRun bool) (models.UserImport, error) {
+	args := m.Called(records, dryRun)
+
+	return args.Get(0).(models.UserImport), args.Error(1)
+}
+
+func (m *TransferServiceMock) Export() ([]models.UserRecord, error) {
+	args := m.Called()
+
+	return args.Get(0).([]models.UserRecord), args.Error(1)
+}
@@ -0,0 +1,180 @@
+package services_test
+
+import (
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"friendMgmt/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// importRecords holds users and relationships covering every result of an import.
+var importRecords = []models.UserRecord{
+	{Type: models.UserRecordUser, Email: "a@test.com", DisplayName: "A"},
+	{Type: models.UserRecordUser, Email: "B@test.com", Status: "suspended"},
+	{Type: models.UserRecordUser, Email: "a@test.com"},
+	{Type: models.UserRecordUser, Email: "invalid"},
+	{Type: models.UserRecordRelationship, Email: "a@test.com", Status: "friend", Target: "b@test.com"},
+	{Type: models.UserRecordRelationship, Email: "b@test.com", Status: "friend", Target: "a@test.com"},
+	{Type: models.UserRecordRelationship, Email: "c@test.com", Status: "subscribed", Target: "a@test.com"},
+	{Type: models.UserRecordUser, Email: "c@test.com"},
+	{Type: models.UserRecordRelationship, Email: "c@test.com", Status: "subscribed", Target: "a@test.com"},
+	{Type: models.UserRecordRelationship, Email: "a@test.com", Status: "blocked", Target: "c@test.com"},
+	{Type: models.UserRecordRelationship, Email: "c@test.com", Status: "friend", Target: "a@test.com"},
+	{Type: models.UserRecordRelationship, Email: "b@test.com", Status: "subscribed", Target: "a@test.com"},
+	{Type: models.UserRecordRelationship, Email: "a@test.com", Status: "pending", Target: "b@test.com"},
+	{Type: "group", Email: "a@test.com"},
+}
+
+var importRows = []models.UserImportRow{
+	{Row: 1, Result: models.ImportCreated},
+	{Row: 2, Result: models.ImportCreated},
+	{Row: 3, Result: models.ImportExisting},
+	{Row: 4, Result: models.ImportInvalid, Details: []models.FieldError{{Field: "email", Message: "must be a valid email"}}},
+	{Row: 5, Result: models.ImportCreated},
+	{Row: 6, Result: models.ImportExisting},
+	{Row: 7, Result: models.ImportInvalid, Details: []models.FieldError{{Field: "email", Message: "must be an imported or existing user"}}},
+	{Row: 8, Result: models.ImportCreated},
+	{Row: 9, Result: models.ImportCreated},
+	{Row: 10, Result: models.ImportCreated},
+	{Row: 11, Result: models.ImportConflict, Code: models.CodeBlocked, Message: "blocked status is existed"},
+	{Row: 12, Result: models.ImportExisting},
+	{Row: 13, Result: models.ImportConflict, Code: models.CodeAlreadyConnected, Message: "connected status is existed"},
+	{Row: 14, Result: models.ImportInvalid, Details: []models.FieldError{{Field: "type", Message: "must be user or relationship"}}},
+}
+
+func TestImport(t *testing.T) {
+	repositories := data.NewMemoryRepositories(data.NewMemoryStore())
+	transferService := services.TransferService{IUserRepository: repositories.IUserRepository, IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+
+	actualResult, err := transferService.Import(importRecords, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.UserImport{Created: 6, Existing: 3, Invalid: 3, Conflicts: 2, Rows: importRows}, actualResult)
+
+	user, err := repositories.IUserRepository.GetUser("b@test.com")
+	assert.NoError(t, err)
+	assert.Equal(t, models.UserSuspended, user.Status)
+	// b is suspended, so the friendship is left out of the friend list but stored all the same.
+	friendIds, err := repositories.IRelationshipRepository.CheckRelationshipTwoWay(1, 2, models.RelationshipFriend)
+	assert.NoError(t, err)
+	assert.Len(t, friendIds, 1)
+	assert.Equal(t, []string{"c@test.com"}, noErrPage(repositories.IRelationshipRepository.GetFollowers(1, models.Page{Limit: 10})))
+}
+
+func TestImportDryRun(t *testing.T) {
+	repositories := data.NewMemoryRepositories(data.NewMemoryStore())
+	transferService := services.TransferService{IUserRepository: repositories.IUserRepository, IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+
+	actualResult, err := transferService.Import(importRecords, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.UserImport{DryRun: true, Created: 6, Existing: 3, Invalid: 3, Conflicts: 2, Rows: importRows}, actualResult)
+	users, _, err := repositories.IUserRepository.FindPage(models.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestExportImportsBack(t *testing.T) {
+	repositories := data.NewMemoryRepositories(data.NewMemoryStore())
+	transferService := services.TransferService{IUserRepository: repositories.IUserRepository, IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+	transferService.Import(importRecords, false)
+
+	records, err := transferService.Export()
+
+	assert.NoError(t, err)
+	expectedRecords := []models.UserRecord{
+		{Type: models.UserRecordUser, Email: "a@test.com", DisplayName: "A", Status: "active"},
+		{Type: models.UserRecordUser, Email: "b@test.com", Status: "suspended"},
+		{Type: models.UserRecordUser, Email: "c@test.com", Status: "active"},
+		{Type: models.UserRecordRelationship, Email: "a@test.com", Status: "friend", Target: "b@test.com"},
+		{Type: models.UserRecordRelationship, Email: "c@test.com", Status: "subscribed", Target: "a@test.com"},
+		{Type: models.UserRecordRelationship, Email: "a@test.com", Status: "blocked", Target: "c@test.com"},
+	}
+	// The relationships are exported with the time they were created at, which the copy below keeps.
+	for i := range records {
+		if records[i].Type == models.UserRecordRelationship {
+			assert.NotEmpty(t, records[i].CreatedAt)
+			expectedRecords[i].CreatedAt = records[i].CreatedAt
+		}
+	}
+	assert.Equal(t, expectedRecords, records)
+
+	copied := data.NewMemoryRepositories(data.NewMemoryStore())
+	copiedService := services.TransferService{IUserRepository: copied.IUserRepository, IRelationshipRepository: copied.IRelationshipRepository, IUnitOfWork: copied.IUnitOfWork}
+
+	imported, err := copiedService.Import(records, false)
+	assert.NoError(t, err)
+	assert.Equal(t, len(records), imported.Created)
+
+	exported, err := copiedService.Export()
+	assert.NoError(t, err)
+	assert.Equal(t, records, exported)
+
+	imported, err = copiedService.Import(records, false)
+	assert.NoError(t, err)
+	assert.Equal(t, len(records), imported.Existing)
+}
+
+func TestImportRestoresRelationshipCreatedAt(t *testing.T) {
+	repositories := data.NewMemoryRepositories(data.NewMemoryStore())
+	transferService := services.TransferService{IUserRepository: repositories.IUserRepository, IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}
+
+	actualResult, err := transferService.Import([]models.UserRecord{
+		{Type: models.UserRecordUser, Email: "a@test.com"},
+		{Type: models.UserRecordUser, Email: "b@test.com"},
+		{Type: models.UserRecordUser, Email: "c@test.com"},
+		{Type: models.UserRecordRelationship, Email: "a@test.com", Status: "friend", Target: "b@test.com", CreatedAt: "2020-01-02T15:04:05+07:00"},
+		{Type: models.UserRecordRelationship, Email: "c@test.com", Status: "blocked", Target: "a@test.com", CreatedAt: "2020-01-02"},
+		{Type: models.UserRecordRelationship, Email: "c@test.com", Status: "subscribed", Target: "b@test.com"},
+	}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ImportInvalid, actualResult.Rows[4].Result)
+	assert.Equal(t, []models.FieldError{{Field: "createdAt", Message: "must be an RFC 3339 time"}}, actualResult.Rows[4].Details)
+
+	relationships, _, err := repositories.IRelationshipRepository.GetRelationships(models.Page{Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, relationships, 2)
+	assert.Equal(t, time.Date(2020, 1, 2, 8, 4, 5, 0, time.UTC), *relationships[0].CreatedAt)
+	assert.WithinDuration(t, time.Now(), *relationships[1].CreatedAt, time.Minute)
+}
+
+func TestExportReadsWithoutUnitOfWork(t *testing.T) {
+	userRepositoryMock := data.UserRepositoryMock{}
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+
+	userRepositoryMock.On("FindPage", models.Page{Limit: 500}).Return([]models.User{
+		{ID: 1, Email: "a@test.com", Status: models.UserActive},
+		{ID: 2, Email: "b@test.com", Status: models.UserActive},
+	}, int64(0), nil)
+	createdAt := time.Date(2020, 1, 2, 8, 4, 5, 500, time.UTC)
+	relationshipRepositoryMock.On("GetRelationships", models.Page{Limit: 500}).Return([]models.Relationship{
+		{ID: 1, RequestUserId: 1, TargetUserId: 2, Status: models.RelationshipFriend, CreatedAt: &createdAt},
+		{ID: 2, RequestUserId: 3, TargetUserId: 1, Status: models.RelationshipSubscribed},
+	}, int64(0), nil)
+
+	unitOfWorkMock := data.UnitOfWorkMock{}
+	transferService := services.TransferService{IUserRepository: &userRepositoryMock, IRelationshipRepository: &relationshipRepositoryMock, IUnitOfWork: &unitOfWorkMock}
+
+	records, err := transferService.Export()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.UserRecord{
+		{Type: models.UserRecordUser, Email: "a@test.com", Status: "active"},
+		{Type: models.UserRecordUser, Email: "b@test.com", Status: "active"},
+		{Type: models.UserRecordRelationship, Email: "a@test.com", Status: "friend", Target: "b@test.com", CreatedAt: "2020-01-02T08:04:05.0000005Z"},
+	}, records)
+	unitOfWorkMock.AssertNotCalled(t, "Do")
+}
+
+// noErrPage returns the items of a page read by a repository call, or its error so that the assertion fails with it.
+func noErrPage(value interface{}, next int64, err error) interface{} {
+	if err != nil {
+		return err
+	}
+
+	return value
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"sort"
+	"time"
+)
+
+// MergeUsers merges the user owning sourceEmail into the one owning targetEmail in a single transaction: the
+// relationships of both users with the others are unioned on the target, their posts and mentions are moved to it
+// and the source is deleted. The relationships between the two users are dropped.
+func (svc UserService) MergeUsers(sourceEmail string, targetEmail string) (models.UserMerge, error) {
+	merge := models.UserMerge{Source: sourceEmail, Target: targetEmail}
+	err := svc.IUnitOfWork.Do(func(repositories data.Repositories) error {
+		sourceId, err := repositories.IUserRepository.CheckUserExist(sourceEmail)
+		if err != nil {
+			return err
+		}
+
+		targetId, err := repositories.IUserRepository.CheckUserExist(targetEmail)
+		if err != nil {
+			return err
+		}
+
+		// The relationships between both users are read for each of them.
+		current := map[int64]models.Relationship{}
+		for _, userId := range []int64{sourceId, targetId} {
+			relationships, err := repositories.IRelationshipRepository.GetUserRelationships(userId)
+			if err != nil {
+				return err
+			}
+			for _, relationship := range relationships {
+				current[relationship.ID] = relationship
+			}
+		}
+
+		merged := mergeRelationships(current, sourceId, targetId)
+
+		for _, userId := range []int64{sourceId, targetId} {
+			if _, err := repositories.IRelationshipRepository.DeleteUserRelationships(userId); err != nil {
+				return err
+			}
+		}
+
+		// The relationships are moved rather than changed, so they are created as they are instead of going through
+		// the transition table, which doesn't lead from none to friend.
+		for _, relationship := range merged {
+			relationship := relationship
+			if _, err := repositories.IRelationshipRepository.CreateRelationship(&relationship); err != nil {
+				return err
+			}
+		}
+		merge.Relationships = len(merged)
+		merge.Dropped = len(current) - len(merged)
+
+		if merge.Posts, merge.Mentions, err = repositories.IPostRepository.MoveUserPosts(sourceId, targetId); err != nil {
+			return err
+		}
+
+		return repositories.IUserRepository.DeleteUser(sourceId)
+	})
+	if err != nil {
+		return models.UserMerge{}, err
+	}
+
+	return merge, nil
+}
+
+// mergedRelationship is a relationship between the merged user and another user, outgoing when the merged user
+// is its requestor. Friend connections go both ways and are always outgoing.
+type mergedRelationship struct {
+	otherUserId int64
+	outgoing    bool
+	status      models.RelationshipStatus
+}
+
+// mergeRelationships returns the relationships the target holds once the source is merged into it, ordered by the
+// other user. Duplicates keep the earliest known creation time and conflicts are resolved like the relationship
+// operations would: mutual requests become a friend connection, a block removes the friend connection, the
+// subscription of the blocker and the requests, and a friend connection removes the subscriptions and the requests.
+func mergeRelationships(relationships map[int64]models.Relationship, sourceId int64, targetId int64) []models.Relationship {
+	merged := map[mergedRelationship]*time.Time{}
+	keep := func(key mergedRelationship, createdAt *time.Time) {
+		current, ok := merged[key]
+		if !ok || (createdAt != nil && (current == nil || createdAt.Before(*current))) {
+			merged[key] = createdAt
+		}
+	}
+
+	otherUserIds := map[int64]bool{}
+	for _, relationship := range relationships {
+		key := mergedRelationship{otherUserId: relationship.TargetUserId, outgoing: true, status: relationship.Status}
+		if relationship.TargetUserId == sourceId || relationship.TargetUserId == targetId {
+			key.otherUserId, key.outgoing = relationship.RequestUserId, false
+		}
+		if key.otherUserId == sourceId || key.otherUserId == targetId {
+			continue
+		}
+		if key.status == models.RelationshipFriend {
+			key.outgoing = true
+		}
+
+		keep(key, relationship.CreatedAt)
+		otherUserIds[key.otherUserId] = true
+	}
+
+	for otherUserId := range otherUserIds {
+		has := func(outgoing bool, status models.RelationshipStatus) bool {
+			_, ok := merged[mergedRelationship{otherUserId: otherUserId, outgoing: outgoing, status: status}]
+			return ok
+		}
+		drop := func(outgoing bool, statuses ...models.RelationshipStatus) {
+			for _, status := range statuses {
+				delete(merged, mergedRelationship{otherUserId: otherUserId, outgoing: outgoing, status: status})
+			}
+		}
+		dropRequests := func() {
+			drop(true, models.RelationshipPending)
+			drop(false, models.RelationshipPending)
+		}
+
+		if has(true, models.RelationshipPending) && has(false, models.RelationshipPending) {
+			friend := mergedRelationship{otherUserId: otherUserId, outgoing: true, status: models.RelationshipFriend}
+			keep(friend, merged[mergedRelationship{otherUserId: otherUserId, outgoing: true, status: models.RelationshipPending}])
+			keep(friend, merged[mergedRelationship{otherUserId: otherUserId, outgoing: false, status: models.RelationshipPending}])
+			dropRequests()
+		}
+		if has(true, models.RelationshipBlocked) {
+			drop(true, models.RelationshipFriend, models.RelationshipSubscribed)
+			dropRequests()
+		}
+		if has(false, models.RelationshipBlocked) {
+			drop(true, models.RelationshipFriend)
+			drop(false, models.RelationshipSubscribed)
+			dropRequests()
+		}
+		if has(true, models.RelationshipFriend) {
+			drop(true, models.RelationshipSubscribed)
+			drop(false, models.RelationshipSubscribed)
+			dropRequests()
+		}
+	}
+
+	keys := make([]mergedRelationship, 0, len(merged))
+	for key := range merged {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].otherUserId != keys[j].otherUserId {
+			return keys[i].otherUserId < keys[j].otherUserId
+		}
+		if keys[i].outgoing != keys[j].outgoing {
+			return keys[i].outgoing
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	var result []models.Relationship
+	for _, key := range keys {
+		relationship := models.Relationship{RequestUserId: targetId, TargetUserId: key.otherUserId, Status: key.status, CreatedAt: merged[key]}
+		if !key.outgoing {
+			relationship.RequestUserId, relationship.TargetUserId = key.otherUserId, targetId
+		}
+		result = append(result, relationship)
+	}
+
+	return result
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"friendMgmt/common"
+	"friendMgmt/models"
+	"io"
+	"strings"
+)
+
+// userRecordColumns are the CSV columns of the user records, in the order they are exported.
+var userRecordColumns = []string{"type", "email", "displayName", "avatarUrl", "status", "target", "createdAt"}
+
+// maxRecordLineLength bounds the lines of an NDJSON import.
+const maxRecordLineLength = 1024 * 1024
+
+// RecordError tells that a line of an import can't be read.
+type RecordError struct {
+	Line    int
+	Message string
+}
+
+func (err *RecordError) Error() string {
+	return fmt.Sprintf("line %d %s", err.Line, err.Message)
+}
+
+// ReadUserRecords reads the records of an import in the given format, or returns a RecordError for the first line
+// which can't be read. Blank NDJSON lines are skipped. When reading fails, its error is returned rather than the one
+// of the line it cut.
+func ReadUserRecords(reader io.Reader, format models.RecordFormat) ([]models.UserRecord, error) {
+	source := &recordSource{Reader: reader}
+
+	var records []models.UserRecord
+	var err error
+	if format == models.RecordFormatCSV {
+		records, err = readCSVRecords(source)
+	} else {
+		records, err = readNDJSONRecords(source)
+	}
+	if source.err != nil {
+		return nil, source.err
+	}
+
+	return records, err
+}
+
+// recordSource keeps the first error of the reader of an import other than its end.
+type recordSource struct {
+	io.Reader
+	err error
+}
+
+func (source *recordSource) Read(p []byte) (int, error) {
+	n, err := source.Reader.Read(p)
+	if err != nil && err != io.EOF && source.err == nil {
+		source.err = err
+	}
+
+	return n, err
+}
+
+func readNDJSONRecords(reader io.Reader) ([]models.UserRecord, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxRecordLineLength)
+
+	var records []models.UserRecord
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var record models.UserRecord
+		if err := json.Unmarshal(text, &record); err != nil {
+			return nil, &RecordError{Line: line, Message: "must be a JSON object of an user record"}
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err == bufio.ErrTooLong {
+		return nil, &RecordError{Line: line + 1, Message: fmt.Sprintf("must not exceed %d bytes", maxRecordLineLength)}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func readCSVRecords(reader io.Reader) ([]models.UserRecord, error) {
+	csvReader := csv.NewReader(reader)
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, csvRecordError(err)
+	}
+	for _, column := range header {
+		if common.GetIndex(column, userRecordColumns) < 0 {
+			return nil, &RecordError{Line: 1, Message: fmt.Sprintf("must only name the columns %s", strings.Join(userRecordColumns, ", "))}
+		}
+	}
+
+	var records []models.UserRecord
+	for {
+		fields, err := csvReader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, csvRecordError(err)
+		}
+
+		var record models.UserRecord
+		for i, value := range fields {
+			setRecordField(&record, header[i], value)
+		}
+		records = append(records, record)
+	}
+}
+
+func csvRecordError(err error) error {
+	parseErr, ok := err.(*csv.ParseError)
+	if !ok {
+		return err
+	}
+	if parseErr.Err == csv.ErrFieldCount {
+		return &RecordError{Line: parseErr.Line, Message: "must have a field for each column of the header"}
+	}
+
+	return &RecordError{Line: parseErr.Line, Message: "must be a valid CSV record"}
+}
+
+// WriteUserRecords writes the records of an export in the given format.
+func WriteUserRecords(writer io.Writer, format models.RecordFormat, records []models.UserRecord) error {
+	if format == models.RecordFormatCSV {
+		csvWriter := csv.NewWriter(writer)
+		csvWriter.Write(userRecordColumns)
+		for _, record := range records {
+			csvWriter.Write(recordFields(record))
+		}
+		csvWriter.Flush()
+
+		return csvWriter.Error()
+	}
+
+	encoder := json.NewEncoder(writer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func recordFields(record models.UserRecord) []string {
+	return []string{string(record.Type), record.Email, record.DisplayName, record.AvatarUrl, record.Status, record.Target, record.CreatedAt}
+}
+
+func setRecordField(record *models.UserRecord, column string, value string) {
+	switch column {
+	case "type":
+		record.Type = models.UserRecordType(value)
+	case "email":
+		record.Email = value
+	case "displayName":
+		record.DisplayName = value
+	case "avatarUrl":
+		record.AvatarUrl = value
+	case "status":
+		record.Status = value
+	case "target":
+		record.Target = value
+	case "createdAt":
+		record.CreatedAt = value
+	}
+}
@@ -0,0 +1,64 @@
+package services_test
+
+import (
+	"bytes"
+	"friendMgmt/models"
+	"friendMgmt/services"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var exportRecords = []models.UserRecord{
+	{Type: models.UserRecordUser, Email: "a@test.com", DisplayName: "Doe, John", AvatarUrl: "https://example.com/a.png?size=2&x=1", Status: "active"},
+	{Type: models.UserRecordRelationship, Email: "a@test.com", Status: "friend", Target: "b@test.com", CreatedAt: "2020-01-02T15:04:05Z"},
+}
+
+func TestUserRecordsRoundTrip(t *testing.T) {
+	for _, format := range []models.RecordFormat{models.RecordFormatCSV, models.RecordFormatNDJSON} {
+		var buffer bytes.Buffer
+		assert.NoError(t, services.WriteUserRecords(&buffer, format, exportRecords), format)
+
+		records, err := services.ReadUserRecords(&buffer, format)
+
+		assert.NoError(t, err, format)
+		assert.Equal(t, exportRecords, records, format)
+	}
+}
+
+func TestWriteUserRecordsAsCSV(t *testing.T) {
+	var buffer bytes.Buffer
+
+	assert.NoError(t, services.WriteUserRecords(&buffer, models.RecordFormatCSV, exportRecords))
+	assert.Equal(t, "type,email,displayName,avatarUrl,status,target,createdAt\n"+
+		"user,a@test.com,\"Doe, John\",https://example.com/a.png?size=2&x=1,active,,\n"+
+		"relationship,a@test.com,,,friend,b@test.com,2020-01-02T15:04:05Z\n", buffer.String())
+}
+
+func TestReadUserRecordsWithColumnsInAnyOrder(t *testing.T) {
+	records, err := services.ReadUserRecords(strings.NewReader("email,type\na@test.com,user\n"), models.RecordFormatCSV)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.UserRecord{{Type: models.UserRecordUser, Email: "a@test.com"}}, records)
+}
+
+func TestReadUserRecordsWithInvalidLines(t *testing.T) {
+	var invalidFiles = map[string]struct {
+		format  models.RecordFormat
+		content string
+	}{
+		"line 1 must only name the columns type, email, displayName, avatarUrl, status, target, createdAt": {models.RecordFormatCSV, "type,email,name\n"},
+		"line 3 must have a field for each column of the header":                                           {models.RecordFormatCSV, "type,email\nuser,a@test.com\nuser\n"},
+		"line 2 must be a valid CSV record":                                                                {models.RecordFormatCSV, "type,email\nuser,\"a@test.com\n"},
+		"line 3 must be a JSON object of an user record":                                                   {models.RecordFormatNDJSON, "{\"type\":\"user\",\"email\":\"a@test.com\"}\n\n[]\n"},
+	}
+	for expected, file := range invalidFiles {
+		_, err := services.ReadUserRecords(strings.NewReader(file.content), file.format)
+
+		assert.IsType(t, &services.RecordError{}, err, expected)
+		if err != nil {
+			assert.Equal(t, expected, err.Error())
+		}
+	}
+}
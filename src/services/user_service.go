@@ -1,32 +1,149 @@
 package services
 
 import (
+	"friendMgmt/common"
 	"friendMgmt/data"
+	"friendMgmt/models"
+	"time"
 )
 
 type IUserService interface {
-	FindAll() []string
-	Create(email string) bool
-	CheckUserExist(email string) int64
-	CheckUsersExist(emails []string) []int64
+	FindPage(page models.Page) ([]models.User, int64, error)
+	SearchUsers(filter models.UserFilter, page models.Page) ([]models.User, int64, error)
+	Create(email string) error
+	GetUser(email string) (models.User, error)
+	UpdateUser(email string, patch models.UserPatch) (models.User, error)
+	DeleteUser(email string) (models.UserDeletion, error)
+	ChangeEmail(email string, newEmail string) (models.User, error)
+	MergeUsers(sourceEmail string, targetEmail string) (models.UserMerge, error)
+	CheckUserExist(email string) (int64, error)
+	CheckUsersExist(emails []string) ([]int64, error)
+	GetUsers(emails []string) ([]models.User, error)
+	GetEmails(ids []int64) (map[int64]string, error)
 }
 
 type UserService struct {
 	IUserRepository data.IUserRepository
+	IUnitOfWork     data.IUnitOfWork
 }
 
-func (svc UserService) FindAll() []string {
-	return svc.IUserRepository.FindAll()
+func (svc UserService) FindPage(page models.Page) ([]models.User, int64, error) {
+	return svc.IUserRepository.FindPage(page)
 }
 
-func (svc UserService) Create(email string) bool {
-	return svc.IUserRepository.Create(email)
+func (svc UserService) SearchUsers(filter models.UserFilter, page models.Page) ([]models.User, int64, error) {
+	return svc.IUserRepository.SearchUsers(filter, page)
 }
 
-func (svc UserService) CheckUserExist(email string) int64 {
+func (svc UserService) Create(email string) error {
+	return svc.IUserRepository.Create(email, time.Now().UTC())
+}
+
+func (svc UserService) GetUser(email string) (models.User, error) {
+	return svc.IUserRepository.GetUser(email)
+}
+
+// UpdateUser changes the fields of the patch on the user owning the email in a single transaction and returns the
+// updated user.
+func (svc UserService) UpdateUser(email string, patch models.UserPatch) (models.User, error) {
+	var user models.User
+	err := svc.IUnitOfWork.Do(func(repositories data.Repositories) error {
+		var err error
+		if user, err = repositories.IUserRepository.GetUser(email); err != nil {
+			return err
+		}
+
+		if patch.DisplayName != nil {
+			user.DisplayName = *patch.DisplayName
+		}
+		if patch.AvatarUrl != nil {
+			user.AvatarUrl = *patch.AvatarUrl
+		}
+		if patch.Status != nil {
+			user.Status = *patch.Status
+		}
+		now := time.Now().UTC()
+		user.UpdatedAt = &now
+
+		return repositories.IUserRepository.UpdateUser(user)
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// DeleteUser erases the user owning the email with its relationships, posts and mentions in a single transaction,
+// and reports how many of them were deleted.
+func (svc UserService) DeleteUser(email string) (models.UserDeletion, error) {
+	deletion := models.UserDeletion{Email: email}
+	err := svc.IUnitOfWork.Do(func(repositories data.Repositories) error {
+		userId, err := repositories.IUserRepository.CheckUserExist(email)
+		if err != nil {
+			return err
+		}
+
+		if deletion.Relationships, err = repositories.IRelationshipRepository.DeleteUserRelationships(userId); err != nil {
+			return err
+		}
+
+		if deletion.Posts, deletion.Mentions, err = repositories.IPostRepository.DeleteUserPosts(userId); err != nil {
+			return err
+		}
+
+		return repositories.IUserRepository.DeleteUser(userId)
+	})
+	if err != nil {
+		return models.UserDeletion{}, err
+	}
+
+	return deletion, nil
+}
+
+// ChangeEmail gives the user owning the email a new one and returns the updated user, or a DuplicateError if
+// another user owns the new email.
+func (svc UserService) ChangeEmail(email string, newEmail string) (models.User, error) {
+	var user models.User
+	err := svc.IUnitOfWork.Do(func(repositories data.Repositories) error {
+		var err error
+		if user, err = repositories.IUserRepository.GetUser(email); err != nil {
+			return err
+		}
+
+		ownerId, err := repositories.IUserRepository.CheckUserExist(newEmail)
+		if err == nil && ownerId != user.ID {
+			return &data.DuplicateError{Entity: "user", Key: newEmail}
+		}
+		if err != nil && !data.IsNotFound(err) {
+			return err
+		}
+
+		now := time.Now().UTC()
+		user.Email = common.NormalizeEmail(newEmail)
+		user.UpdatedAt = &now
+
+		return repositories.IUserRepository.ChangeEmail(user.ID, newEmail, now)
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+func (svc UserService) CheckUserExist(email string) (int64, error) {
 	return svc.IUserRepository.CheckUserExist(email)
 }
 
-func (svc UserService) CheckUsersExist(emails []string) []int64 {
+func (svc UserService) CheckUsersExist(emails []string) ([]int64, error) {
 	return svc.IUserRepository.CheckUsersExist(emails)
 }
+
+func (svc UserService) GetUsers(emails []string) ([]models.User, error) {
+	return svc.IUserRepository.GetUsers(emails)
+}
+
+func (svc UserService) GetEmails(ids []int64) (map[int64]string, error) {
+	return svc.IUserRepository.GetEmails(ids)
+}
@@ -1,31 +1,83 @@
 package services
 
-import "github.com/stretchr/testify/mock"
+import (
+	"friendMgmt/models"
+
+	"github.com/stretchr/testify/mock"
+)
 
 type UserServiceMock struct {
 	mock.Mock
 }
 
-func (m UserServiceMock) FindAll() []string {
-	args := m.Called()
+func (m *UserServiceMock) FindPage(page models.Page) ([]models.User, int64, error) {
+	args := m.Called(page)
+
+	return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *UserServiceMock) SearchUsers(filter models.UserFilter, page models.Page) ([]models.User, int64, error) {
+	args := m.Called(filter, page)
+
+	return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *UserServiceMock) Create(email string) error {
+	args := m.Called(email)
+
+	return args.Error(0)
+}
+
+func (m *UserServiceMock) GetUser(email string) (models.User, error) {
+	args := m.Called(email)
+
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *UserServiceMock) UpdateUser(email string, patch models.UserPatch) (models.User, error) {
+	args := m.Called(email, patch)
 
-	return args.Get(0).([]string)
+	return args.Get(0).(models.User), args.Error(1)
 }
 
-func (m UserServiceMock) Create(email string) bool {
+func (m *UserServiceMock) DeleteUser(email string) (models.UserDeletion, error) {
 	args := m.Called(email)
 
-	return args.Get(0).(bool)
+	return args.Get(0).(models.UserDeletion), args.Error(1)
 }
 
-func (m UserServiceMock) CheckUserExist(email string) int64 {
+func (m *UserServiceMock) CheckUserExist(email string) (int64, error) {
 	args := m.Called(email)
 
-	return args.Get(0).(int64)
+	return args.Get(0).(int64), args.Error(1)
 }
 
-func (m UserServiceMock) CheckUsersExist(emails []string) []int64 {
+func (m *UserServiceMock) CheckUsersExist(emails []string) ([]int64, error) {
 	args := m.Called(emails)
 
-	return args.Get(0).([]int64)
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *UserServiceMock) GetUsers(emails []string) ([]models.User, error) {
+	args := m.Called(emails)
+
+	return args.Get(0).([]models.User), args.Error(1)
+}
+
+func (m *UserServiceMock) GetEmails(ids []int64) (map[int64]string, error) {
+	args := m.Called(ids)
+
+	return args.Get(0).(map[int64]string), args.Error(1)
+}
+
+func (m *UserServiceMock) ChangeEmail(email string, newEmail string) (models.User, error) {
+	args := m.Called(email, newEmail)
+
+	return args.Get(0).(models.User), args.Error(1)
+}
+
+func (m *UserServiceMock) MergeUsers(sourceEmail string, targetEmail string) (models.UserMerge, error) {
+	args := m.Called(sourceEmail, targetEmail)
+
+	return args.Get(0).(models.UserMerge), args.Error(1)
 }
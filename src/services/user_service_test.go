@@ -2,22 +2,48 @@ package services_test
 
 import (
 	"friendMgmt/data"
+	"friendMgmt/models"
 	"friendMgmt/services"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
-func TestFindAll(t *testing.T) {
+func TestFindPage(t *testing.T) {
 	userRepositoryMock := data.UserRepositoryMock{}
 
-	expectedResult := []string{"user1@gmail.com", "user2@gmail.com"}
+	expectedResult := []models.User{{ID: 1, Email: "user1@gmail.com"}, {ID: 2, Email: "user2@gmail.com"}}
 
-	userRepositoryMock.On("FindAll").Return(expectedResult)
+	userRepositoryMock.On("FindPage", models.Page{After: 1, Limit: 2}).Return(expectedResult, int64(3), nil)
 
-	userService := services.UserService{userRepositoryMock}
+	userService := services.UserService{IUserRepository: &userRepositoryMock}
 
-	assert.Equal(t, expectedResult, userService.FindAll())
+	actualResult, next, err := userService.FindPage(models.Page{After: 1, Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+	assert.Equal(t, int64(3), next)
+
+	userRepositoryMock.AssertExpectations(t)
+}
+
+func TestSearchUsers(t *testing.T) {
+	userRepositoryMock := data.UserRepositoryMock{}
+
+	filter := models.UserFilter{Query: "user", Domain: "gmail.com", Sort: models.UserSortEmail}
+	expectedResult := []models.User{{ID: 1, Email: "user1@gmail.com"}, {ID: 2, Email: "user2@gmail.com"}}
+
+	userRepositoryMock.On("SearchUsers", filter, models.Page{Limit: 2}).Return(expectedResult, int64(2), nil)
+
+	userService := services.UserService{IUserRepository: &userRepositoryMock}
+
+	actualResult, next, err := userService.SearchUsers(filter, models.Page{Limit: 2})
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedResult, actualResult)
+	assert.Equal(t, int64(2), next)
 
 	userRepositoryMock.AssertExpectations(t)
 }
@@ -25,25 +51,218 @@ func TestFindAll(t *testing.T) {
 func TestCreate(t *testing.T) {
 	userRepositoryMock := data.UserRepositoryMock{}
 
-	createSuccess := true
+	userRepositoryMock.On("Create", "user@test.com", mock.AnythingOfType("time.Time")).Return(nil)
+
+	userService := services.UserService{IUserRepository: &userRepositoryMock}
+
+	assert.NoError(t, userService.Create("user@test.com"))
+
+	userRepositoryMock.AssertExpectations(t)
+}
+
+func TestUpdateUser(t *testing.T) {
+	userRepositoryMock := data.UserRepositoryMock{}
+
+	createdAt := time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC)
+	user := models.User{ID: 1, Email: "user@test.com", DisplayName: "User", AvatarUrl: "https://example.com/user.png", Status: models.UserActive, CreatedAt: &createdAt, UpdatedAt: &createdAt}
+
+	userRepositoryMock.On("GetUser", "user@test.com").Return(user, nil)
+	userRepositoryMock.On("UpdateUser", mock.MatchedBy(func(updated models.User) bool {
+		return updated.DisplayName == "John" && updated.AvatarUrl == user.AvatarUrl && updated.Status == models.UserSuspended && updated.UpdatedAt.After(createdAt)
+	})).Return(nil)
+
+	unitOfWorkMock := data.UnitOfWorkMock{Repositories: data.Repositories{IUserRepository: &userRepositoryMock}}
+	unitOfWorkMock.On("Do").Return(nil)
+
+	userService := services.UserService{IUserRepository: &userRepositoryMock, IUnitOfWork: &unitOfWorkMock}
+
+	displayName := "John"
+	status := models.UserSuspended
+	actualResult, err := userService.UpdateUser("user@test.com", models.UserPatch{DisplayName: &displayName, Status: &status})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "John", actualResult.DisplayName)
+	assert.Equal(t, models.UserSuspended, actualResult.Status)
+	assert.Equal(t, &createdAt, actualResult.CreatedAt)
+
+	userRepositoryMock.AssertExpectations(t)
+}
+
+func TestDeleteUser(t *testing.T) {
+	userRepositoryMock := data.UserRepositoryMock{}
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	postRepositoryMock := data.PostRepositoryMock{}
+
+	userRepositoryMock.On("CheckUserExist", "user@test.com").Return(int64(1), nil)
+	relationshipRepositoryMock.On("DeleteUserRelationships", int64(1)).Return(4, nil)
+	postRepositoryMock.On("DeleteUserPosts", int64(1)).Return(2, 3, nil)
+	userRepositoryMock.On("DeleteUser", int64(1)).Return(nil)
+
+	unitOfWorkMock := data.UnitOfWorkMock{Repositories: data.Repositories{IUserRepository: &userRepositoryMock, IRelationshipRepository: &relationshipRepositoryMock, IPostRepository: &postRepositoryMock}}
+	unitOfWorkMock.On("Do").Return(nil)
+
+	userService := services.UserService{IUserRepository: &userRepositoryMock, IUnitOfWork: &unitOfWorkMock}
+
+	actualResult, err := userService.DeleteUser("user@test.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.UserDeletion{Email: "user@test.com", Relationships: 4, Posts: 2, Mentions: 3}, actualResult)
+
+	userRepositoryMock.AssertExpectations(t)
+	relationshipRepositoryMock.AssertExpectations(t)
+	postRepositoryMock.AssertExpectations(t)
+}
+
+func TestDeleteUnknownUser(t *testing.T) {
+	userRepositoryMock := data.UserRepositoryMock{}
+	userRepositoryMock.On("CheckUserExist", "unknown@test.com").Return(int64(0), &data.NotFoundError{Entity: "user", Key: "unknown@test.com"})
+
+	unitOfWorkMock := data.UnitOfWorkMock{Repositories: data.Repositories{IUserRepository: &userRepositoryMock}}
+	unitOfWorkMock.On("Do").Return(nil)
+
+	userService := services.UserService{IUserRepository: &userRepositoryMock, IUnitOfWork: &unitOfWorkMock}
+
+	_, err := userService.DeleteUser("unknown@test.com")
+
+	assert.True(t, data.IsNotFound(err))
+}
+
+func TestChangeEmail(t *testing.T) {
+	userRepositoryMock := data.UserRepositoryMock{}
+
+	user := models.User{ID: 1, Email: "user@test.com", Status: models.UserActive}
 
-	userRepositoryMock.On("Create", "user@test.com").Return(createSuccess)
+	userRepositoryMock.On("GetUser", "user@test.com").Return(user, nil)
+	userRepositoryMock.On("CheckUserExist", "new@test.com").Return(int64(0), &data.NotFoundError{Entity: "user", Key: "new@test.com"})
+	userRepositoryMock.On("ChangeEmail", int64(1), "new@test.com", mock.AnythingOfType("time.Time")).Return(nil)
 
-	userService := services.UserService{userRepositoryMock}
+	unitOfWorkMock := data.UnitOfWorkMock{Repositories: data.Repositories{IUserRepository: &userRepositoryMock}}
+	unitOfWorkMock.On("Do").Return(nil)
 
-	assert.Equal(t, createSuccess, userService.Create("user@test.com"))
+	userService := services.UserService{IUserRepository: &userRepositoryMock, IUnitOfWork: &unitOfWorkMock}
+
+	actualResult, err := userService.ChangeEmail("user@test.com", "new@test.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), actualResult.ID)
+	assert.Equal(t, "new@test.com", actualResult.Email)
+	assert.NotNil(t, actualResult.UpdatedAt)
+
+	userRepositoryMock.AssertExpectations(t)
+}
+
+func TestChangeEmailReturnsNormalizedEmail(t *testing.T) {
+	userRepositoryMock := data.UserRepositoryMock{}
+
+	userRepositoryMock.On("GetUser", "user@test.com").Return(models.User{ID: 1, Email: "user@test.com"}, nil)
+	userRepositoryMock.On("CheckUserExist", "New@Test.com").Return(int64(0), &data.NotFoundError{Entity: "user", Key: "New@Test.com"})
+	userRepositoryMock.On("ChangeEmail", int64(1), "New@Test.com", mock.AnythingOfType("time.Time")).Return(nil)
+
+	unitOfWorkMock := data.UnitOfWorkMock{Repositories: data.Repositories{IUserRepository: &userRepositoryMock}}
+	unitOfWorkMock.On("Do").Return(nil)
+
+	userService := services.UserService{IUserRepository: &userRepositoryMock, IUnitOfWork: &unitOfWorkMock}
+
+	actualResult, err := userService.ChangeEmail("user@test.com", "New@Test.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new@test.com", actualResult.Email)
 
 	userRepositoryMock.AssertExpectations(t)
 }
 
+func TestChangeEmailInUse(t *testing.T) {
+	userRepositoryMock := data.UserRepositoryMock{}
+
+	userRepositoryMock.On("GetUser", "user@test.com").Return(models.User{ID: 1, Email: "user@test.com"}, nil)
+	userRepositoryMock.On("CheckUserExist", "other@test.com").Return(int64(2), nil)
+
+	unitOfWorkMock := data.UnitOfWorkMock{Repositories: data.Repositories{IUserRepository: &userRepositoryMock}}
+	unitOfWorkMock.On("Do").Return(nil)
+
+	userService := services.UserService{IUserRepository: &userRepositoryMock, IUnitOfWork: &unitOfWorkMock}
+
+	_, err := userService.ChangeEmail("user@test.com", "other@test.com")
+
+	assert.True(t, data.IsDuplicate(err))
+	userRepositoryMock.AssertNotCalled(t, "ChangeEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMergeUsers(t *testing.T) {
+	userRepositoryMock := data.UserRepositoryMock{}
+	relationshipRepositoryMock := data.RelationshipRepositoryMock{}
+	postRepositoryMock := data.PostRepositoryMock{}
+
+	earlier := time.Date(2020, 5, 1, 9, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	between := models.Relationship{ID: 1, RequestUserId: 1, TargetUserId: 2, Status: models.RelationshipFriend}
+
+	userRepositoryMock.On("CheckUserExist", "source@test.com").Return(int64(1), nil)
+	userRepositoryMock.On("CheckUserExist", "target@test.com").Return(int64(2), nil)
+	relationshipRepositoryMock.On("GetUserRelationships", int64(1)).Return([]models.Relationship{
+		between,
+		{ID: 2, RequestUserId: 1, TargetUserId: 3, Status: models.RelationshipFriend, CreatedAt: &later},
+		{ID: 3, RequestUserId: 4, TargetUserId: 1, Status: models.RelationshipPending, CreatedAt: &later},
+		{ID: 4, RequestUserId: 1, TargetUserId: 5, Status: models.RelationshipBlocked},
+		{ID: 5, RequestUserId: 6, TargetUserId: 1, Status: models.RelationshipSubscribed},
+		{ID: 6, RequestUserId: 1, TargetUserId: 7, Status: models.RelationshipSubscribed},
+	}, nil)
+	relationshipRepositoryMock.On("GetUserRelationships", int64(2)).Return([]models.Relationship{
+		between,
+		{ID: 7, RequestUserId: 3, TargetUserId: 2, Status: models.RelationshipFriend, CreatedAt: &earlier},
+		{ID: 8, RequestUserId: 2, TargetUserId: 4, Status: models.RelationshipPending},
+		{ID: 9, RequestUserId: 2, TargetUserId: 5, Status: models.RelationshipFriend},
+		{ID: 10, RequestUserId: 6, TargetUserId: 2, Status: models.RelationshipBlocked},
+		{ID: 11, RequestUserId: 2, TargetUserId: 7, Status: models.RelationshipSubscribed},
+	}, nil)
+	relationshipRepositoryMock.On("DeleteUserRelationships", int64(1)).Return(6, nil)
+	relationshipRepositoryMock.On("DeleteUserRelationships", int64(2)).Return(5, nil)
+
+	var created []models.Relationship
+	relationshipRepositoryMock.On("CreateRelationship", mock.Anything).Return(int64(0), nil).Run(func(args mock.Arguments) {
+		created = append(created, *args.Get(0).(*models.Relationship))
+	})
+	postRepositoryMock.On("MoveUserPosts", int64(1), int64(2)).Return(2, 1, nil)
+	userRepositoryMock.On("DeleteUser", int64(1)).Return(nil)
+
+	unitOfWorkMock := data.UnitOfWorkMock{Repositories: data.Repositories{IUserRepository: &userRepositoryMock, IRelationshipRepository: &relationshipRepositoryMock, IPostRepository: &postRepositoryMock}}
+	unitOfWorkMock.On("Do").Return(nil)
+
+	userService := services.UserService{IUserRepository: &userRepositoryMock, IUnitOfWork: &unitOfWorkMock}
+
+	actualResult, err := userService.MergeUsers("source@test.com", "target@test.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.UserMerge{Source: "source@test.com", Target: "target@test.com", Relationships: 5, Dropped: 6, Posts: 2, Mentions: 1}, actualResult)
+	assert.Equal(t, []models.Relationship{
+		{RequestUserId: 2, TargetUserId: 3, Status: models.RelationshipFriend, CreatedAt: &earlier},
+		{RequestUserId: 2, TargetUserId: 4, Status: models.RelationshipFriend, CreatedAt: &later},
+		{RequestUserId: 2, TargetUserId: 5, Status: models.RelationshipBlocked},
+		{RequestUserId: 6, TargetUserId: 2, Status: models.RelationshipBlocked},
+		{RequestUserId: 2, TargetUserId: 7, Status: models.RelationshipSubscribed},
+	}, created)
+
+	userRepositoryMock.AssertExpectations(t)
+	relationshipRepositoryMock.AssertExpectations(t)
+	postRepositoryMock.AssertExpectations(t)
+}
+
 func TestCheckUserExist(t *testing.T) {
 	userRepositoryMock := data.UserRepositoryMock{}
 
-	userRepositoryMock.On("CheckUserExist", "user@test.com").Return(int64(1))
+	userRepositoryMock.On("CheckUserExist", "user@test.com").Return(int64(1), nil)
+	userRepositoryMock.On("CheckUserExist", "unknown@test.com").Return(int64(0), &data.NotFoundError{Entity: "user", Key: "unknown@test.com"})
+
+	userService := services.UserService{IUserRepository: &userRepositoryMock}
 
-	userService := services.UserService{userRepositoryMock}
+	actualResult, err := userService.CheckUserExist("user@test.com")
 
-	assert.Equal(t, int64(1), userService.CheckUserExist("user@test.com"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), actualResult)
+
+	_, err = userService.CheckUserExist("unknown@test.com")
+
+	assert.True(t, data.IsNotFound(err))
 
 	userRepositoryMock.AssertExpectations(t)
 }
@@ -54,11 +273,32 @@ func TestCheckUsersExist(t *testing.T) {
 	emails := []string{"user1@gmail.com", "user2@gmail.com"}
 	idsResult := []int64{int64(1), int64(2)}
 
-	userRepositoryMock.On("CheckUsersExist", emails).Return(idsResult)
+	userRepositoryMock.On("CheckUsersExist", emails).Return(idsResult, nil)
+
+	userService := services.UserService{IUserRepository: &userRepositoryMock}
+
+	actualResult, err := userService.CheckUsersExist(emails)
+
+	assert.NoError(t, err)
+	assert.Equal(t, idsResult, actualResult)
+
+	userRepositoryMock.AssertExpectations(t)
+}
+
+func TestGetUsers(t *testing.T) {
+	userRepositoryMock := data.UserRepositoryMock{}
+
+	emails := []string{"user1@gmail.com", "user2@gmail.com"}
+	users := []models.User{{ID: 1, Email: "user1@gmail.com"}, {ID: 2, Email: "user2@gmail.com"}}
+
+	userRepositoryMock.On("GetUsers", emails).Return(users, nil)
+
+	userService := services.UserService{IUserRepository: &userRepositoryMock}
 
-	userService := services.UserService{userRepositoryMock}
+	actualResult, err := userService.GetUsers(emails)
 
-	assert.Equal(t, idsResult, userService.CheckUsersExist(emails))
+	assert.NoError(t, err)
+	assert.Equal(t, users, actualResult)
 
 	userRepositoryMock.AssertExpectations(t)
 }
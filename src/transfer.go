@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"friendMgmt/data"
+	"friendMgmt/models"
+	"friendMgmt/services"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// importUsers runs the "import [-dry-run] [-format csv|ndjson] file" command and prints the records which were not
+// imported with the reason.
+func importUsers(repositories data.Repositories, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report the results without storing anything")
+	format := flags.String("format", "", "csv or ndjson, guessed from the file extension by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected the file to import")
+	}
+
+	recordFormat, err := fileFormat(*format, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	records, err := services.ReadUserRecords(file, recordFormat)
+	if err != nil {
+		return err
+	}
+
+	report, err := services.TransferService{IUserRepository: repositories.IUserRepository, IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}.Import(records, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range report.Rows {
+		switch row.Result {
+		case models.ImportInvalid:
+			reasons := make([]string, len(row.Details))
+			for i, detail := range row.Details {
+				reasons[i] = detail.Field + " " + detail.Message
+			}
+			fmt.Printf("row %d\t%s\t%s\n", row.Row, row.Result, strings.Join(reasons, ", "))
+		case models.ImportConflict:
+			fmt.Printf("row %d\t%s\t%s %s\n", row.Row, row.Result, row.Code, row.Message)
+		}
+	}
+
+	summary := fmt.Sprintf("%d created, %d existing, %d invalid, %d conflicts", report.Created, report.Existing, report.Invalid, report.Conflicts)
+	if report.DryRun {
+		summary += ", nothing stored by the dry run"
+	}
+	fmt.Println(summary)
+
+	return nil
+}
+
+// exportUsers runs the "export [-format csv|ndjson] [file]" command, writing to the standard output without a file.
+func exportUsers(repositories data.Repositories, args []string) (err error) {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "", "csv or ndjson, guessed from the file extension by default")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		return errors.New("expected at most the file to export to")
+	}
+
+	recordFormat, err := fileFormat(*format, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	records, err := services.TransferService{IUserRepository: repositories.IUserRepository, IRelationshipRepository: repositories.IRelationshipRepository, IUnitOfWork: repositories.IUnitOfWork}.Export()
+	if err != nil {
+		return err
+	}
+
+	var writer io.Writer = os.Stdout
+	if flags.NArg() == 1 {
+		file, err := os.Create(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		writer = file
+	}
+
+	return services.WriteUserRecords(writer, recordFormat, records)
+}
+
+// fileFormat returns the given format, or the one of the file extension when it's empty, ndjson by default.
+func fileFormat(format string, path string) (models.RecordFormat, error) {
+	if format == "" {
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			return models.RecordFormatCSV, nil
+		}
+		return models.RecordFormatNDJSON, nil
+	}
+
+	if !models.RecordFormat(format).IsValid() {
+		return "", fmt.Errorf("unknown format %q, expected csv or ndjson", format)
+	}
+
+	return models.RecordFormat(format), nil
+}